@@ -40,6 +40,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(GroupVersion,
 		&StrimziSchemaRegistry{},
 		&StrimziSchemaRegistryList{},
+		&StrimziSchema{},
+		&StrimziSchemaList{},
+		&StrimziSchemaSubjectConfig{},
+		&StrimziSchemaSubjectConfigList{},
+		&StrimziSchemaRegistryBackup{},
+		&StrimziSchemaRegistryBackupList{},
+		&StrimziSchemaExporter{},
+		&StrimziSchemaExporterList{},
 	)
 
 	// Register the group version in the scheme (required for certain operations)
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SchemaType is the format of a registered schema.
+// +kubebuilder:validation:Enum=AVRO;JSON;PROTOBUF
+type SchemaType string
+
+const (
+	SchemaTypeAvro     SchemaType = "AVRO"
+	SchemaTypeJSON     SchemaType = "JSON"
+	SchemaTypeProtobuf SchemaType = "PROTOBUF"
+)
+
+// StrimziSchemaSpec defines the desired state of StrimziSchema
+// +kubebuilder:validation:XValidation:rule="has(self.schema) != has(self.schemaFrom)",message="exactly one of schema and schemaFrom must be set"
+type StrimziSchemaSpec struct {
+	// Registry is the StrimziSchemaRegistry the schema is registered into.
+	Registry RegistryReference `json:"registry"`
+
+	// Subject the schema is registered under.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Subject string `json:"subject"`
+
+	// SchemaType is the schema format.
+	// +kubebuilder:default=AVRO
+	// +optional
+	SchemaType SchemaType `json:"schemaType,omitempty"`
+
+	// Schema is the schema definition.
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// SchemaFrom reads the schema definition from a ConfigMap key in the namespace of the StrimziSchema.
+	// +optional
+	SchemaFrom *corev1.ConfigMapKeySelector `json:"schemaFrom,omitempty"`
+
+	// References lists the schemas the definition imports.
+	// +optional
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+// RegistryReference identifies a StrimziSchemaRegistry.
+type RegistryReference struct {
+	// Name of the StrimziSchemaRegistry.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the StrimziSchemaRegistry. Defaults to the namespace of the referencing resource.
+	// A registry of another namespace must share itself with that namespace through spec.tenancy.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// SchemaReference is a reference to another registered schema.
+type SchemaReference struct {
+	// Name of the reference as used in the schema definition (Avro type, JSON $ref, Protobuf import).
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Subject of the referenced schema.
+	// +kubebuilder:validation:MinLength=1
+	Subject string `json:"subject"`
+
+	// Version of the referenced schema.
+	// +kubebuilder:validation:Minimum=1
+	Version int32 `json:"version"`
+}
+
+// StrimziSchemaStatus defines the observed state of StrimziSchema
+type StrimziSchemaStatus struct {
+	// Conditions represent the registration state: Ready, Compatible and PolicyCompliant.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Subject is the subject the schema is registered under, qualified with the context of the
+	// namespace when the registry is shared through spec.tenancy.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// ID is the schema ID assigned by the registry.
+	// +optional
+	ID int32 `json:"id,omitempty"`
+
+	// Version is the version of the schema under the subject.
+	// +optional
+	Version int32 `json:"version,omitempty"`
+
+	// PolicyViolations lists the checks of the registry schema policy the schema fails.
+	// +optional
+	PolicyViolations []PolicyViolation `json:"policyViolations,omitempty"`
+
+	// ObservedGeneration is the generation last registered.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// PolicyViolation is a failed check of the registry schema policy.
+type PolicyViolation struct {
+	// Rule is the failed check: requireDoc, namespacePattern, maxSchemaBytes,
+	// forbidFieldTypeChanges or the name of a CEL rule.
+	Rule string `json:"rule"`
+
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Subject",type="string",JSONPath=".spec.subject"
+// +kubebuilder:printcolumn:name="ID",type="integer",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="Version",type="integer",JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// StrimziSchema is a schema registered into a StrimziSchemaRegistry
+type StrimziSchema struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StrimziSchemaSpec   `json:"spec,omitempty"`
+	Status StrimziSchemaStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StrimziSchemaList contains a list of StrimziSchema
+type StrimziSchemaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StrimziSchema `json:"items"`
+}
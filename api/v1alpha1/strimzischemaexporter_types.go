@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ExporterMethod selects how the schemas are copied to the destination.
+// +kubebuilder:validation:Enum=Auto;Exporter;Copy
+type ExporterMethod string
+
+const (
+	// ExporterMethodAuto uses a Schema Registry exporter when the source registry supports them,
+	// the copy loop of the operator otherwise.
+	ExporterMethodAuto ExporterMethod = "Auto"
+	// ExporterMethodExporter manages an exporter of the source registry (schema linking).
+	ExporterMethodExporter ExporterMethod = "Exporter"
+	// ExporterMethodCopy copies the missing versions periodically through the REST API, with
+	// their original IDs, the destination subjects being switched to IMPORT mode.
+	ExporterMethodCopy ExporterMethod = "Copy"
+)
+
+// ExporterContextType selects the context the schemas land in at the destination.
+// +kubebuilder:validation:Enum=NONE;CUSTOM
+type ExporterContextType string
+
+const (
+	// ExporterContextNone keeps the subjects in the default context of the destination.
+	ExporterContextNone ExporterContextType = "NONE"
+	// ExporterContextCustom moves the subjects into spec.context.
+	ExporterContextCustom ExporterContextType = "CUSTOM"
+)
+
+// ExporterDestination is the registry the schemas are copied to. Exactly one of registry and
+// url must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.registry) != has(self.url)",message="exactly one of registry and url must be set"
+type ExporterDestination struct {
+	// Registry is a StrimziSchemaRegistry of this cluster, reached the way the operator reaches it.
+	// +optional
+	Registry *RegistryReference `json:"registry,omitempty"`
+
+	// URL is the REST API of a registry outside of this cluster.
+	// +kubebuilder:validation:Pattern="^https?://"
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// CredentialsSecretName is a Secret in the exporter namespace holding how to authenticate to
+	// the destination: the "username" and "password" keys for Basic authentication, "ca.crt" to
+	// trust a private CA and "tls.crt" and "tls.key" to present a client certificate. All are
+	// optional. With registry, it is only used by the Exporter method, which never receives the
+	// operator's credentials, and is required when the registry authenticates its clients.
+	// +optional
+	CredentialsSecretName string `json:"credentialsSecretName,omitempty"`
+}
+
+// StrimziSchemaExporterSpec defines the desired state of StrimziSchemaExporter
+// +kubebuilder:validation:XValidation:rule="self.contextType != 'CUSTOM' || has(self.context)",message="context is required when contextType is CUSTOM"
+type StrimziSchemaExporterSpec struct {
+	// Registry is the StrimziSchemaRegistry the schemas are exported from.
+	Registry RegistryReference `json:"registry"`
+
+	// Destination is the registry the schemas are exported to.
+	Destination ExporterDestination `json:"destination"`
+
+	// Method selects how the schemas are copied: Auto uses a Schema Registry exporter when the
+	// source registry supports them and the copy loop of the operator otherwise.
+	// +kubebuilder:default=Auto
+	// +optional
+	Method ExporterMethod `json:"method,omitempty"`
+
+	// Subjects lists the exported subjects; "*", the default, exports every subject of the
+	// default context.
+	// +optional
+	Subjects []string `json:"subjects,omitempty"`
+
+	// ContextType is where the schemas land at the destination: NONE keeps them in the default
+	// context, preserving subject names for disaster recovery; CUSTOM moves them into context.
+	// +kubebuilder:default=NONE
+	// +optional
+	ContextType ExporterContextType `json:"contextType,omitempty"`
+
+	// Context is the destination context when contextType is CUSTOM, without the surrounding
+	// ":." and ":".
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9_.-]+$"
+	// +optional
+	Context string `json:"context,omitempty"`
+
+	// SubjectRenameFormat renames the subjects at the destination, "${subject}" standing for the
+	// source subject (e.g. "dc1.${subject}").
+	// +optional
+	SubjectRenameFormat string `json:"subjectRenameFormat,omitempty"`
+
+	// Suspend pauses the export.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// StrimziSchemaExporterStatus defines the observed state of StrimziSchemaExporter
+type StrimziSchemaExporterStatus struct {
+	// Conditions represent the state of the export: Ready.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Method is the method in use, Exporter or Copy.
+	// +optional
+	Method ExporterMethod `json:"method,omitempty"`
+
+	// State is the state of the export: STARTING, RUNNING, PAUSED or ERROR.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Offset is the position of the exporter in the schemas topic of the source registry.
+	// +optional
+	Offset int64 `json:"offset,omitempty"`
+
+	// Lag is the number of exported subject versions missing from the destination. It is not
+	// reported while the destination cannot be read.
+	// +optional
+	Lag *int32 `json:"lag,omitempty"`
+
+	// LastSyncTime is the last time the destination held every exported version.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// ImportSubjects are the destination subjects the Copy method switched to IMPORT mode. Their
+	// mode is deleted along with the resource.
+	// +optional
+	ImportSubjects []string `json:"importSubjects,omitempty"`
+
+	// ObservedGeneration is the generation last acted on.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Registry",type="string",JSONPath=".spec.registry.name"
+// +kubebuilder:printcolumn:name="Method",type="string",JSONPath=".status.method"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Lag",type="integer",JSONPath=".status.lag"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// StrimziSchemaExporter keeps the schemas of a StrimziSchemaRegistry, with their IDs, in another
+// registry
+type StrimziSchemaExporter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StrimziSchemaExporterSpec   `json:"spec,omitempty"`
+	Status StrimziSchemaExporterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StrimziSchemaExporterList contains a list of StrimziSchemaExporter
+type StrimziSchemaExporterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StrimziSchemaExporter `json:"items"`
+}
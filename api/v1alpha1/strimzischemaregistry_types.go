@@ -17,8 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // StrimziSchemaRegistry manages Confluent Schema Registry deployment
@@ -26,6 +28,16 @@ import (
 // keystore/truststore generation automatically.
 
 // StrimziSchemaRegistrySpec defines the desired state of StrimziSchemaRegistry
+// +kubebuilder:validation:XValidation:rule="!has(self.dualListener) || !self.dualListener || self.securehttp",message="dualListener requires securehttp"
+// +kubebuilder:validation:XValidation:rule="!has(self.dualListener) || !self.dualListener || !has(self.service) || !has(self.service.port) || self.service.port != 80",message="service.port 80 is used by the plain HTTP listener in dualListener mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.tls) || !has(self.tls.clientAuth) || self.tls.clientAuth == 'None' || self.securehttp",message="tls.clientAuth requires securehttp"
+// +kubebuilder:validation:XValidation:rule="!has(self.connection) || !has(self.connection.basicAuthUser) || (has(self.authentication) && has(self.authentication.basic))",message="connection.basicAuthUser requires authentication.basic"
+// +kubebuilder:validation:XValidation:rule="!has(self.secondary) || (has(self.role) && self.role == 'secondary')",message="secondary requires role secondary"
+// +kubebuilder:validation:XValidation:rule="!has(self.flavor) || self.flavor == 'confluent' || !has(self.authentication)",message="authentication requires flavor confluent"
+// +kubebuilder:validation:XValidation:rule="!has(self.flavor) || self.flavor == 'confluent' || !has(self.tls) || !has(self.tls.clientAuth) || self.tls.clientAuth == 'None'",message="tls.clientAuth requires flavor confluent"
+// +kubebuilder:validation:XValidation:rule="!has(self.flavor) || self.flavor == 'confluent' || !has(self.service) || !has(self.service.jmxPort)",message="service.jmxPort requires flavor confluent"
+// +kubebuilder:validation:XValidation:rule="!has(self.flavor) || self.flavor != 'karapace' || !has(self.dualListener) || !self.dualListener",message="dualListener is not supported by flavor karapace"
+// +kubebuilder:validation:XValidation:rule="!has(self.probes) || !has(self.probes.readinessCheck) || self.probes.readinessCheck != 'Subjects' || ((!has(self.authentication) || !has(self.authentication.basic)) && (!has(self.tls) || !has(self.tls.clientAuth) || self.tls.clientAuth != 'Required'))",message="probes.readinessCheck Subjects cannot be used with authentication.basic or tls.clientAuth Required, as probes carry no credentials"
 type StrimziSchemaRegistrySpec struct {
 	// Listener name for Kafka cluster (defaults to "tls")
 	// +kubebuilder:default="tls"
@@ -40,7 +52,9 @@ type StrimziSchemaRegistrySpec struct {
 	// +kubebuilder:validation:Enum=SSL;SASL_SSL;PLAINTEXT;SASL_PLAINTEXT
 	SecurityProtocol string `json:"securityprotocol,omitempty"`
 
-	// CompatibilityLevel defines the schema compatibility level for Schema Registry.
+	// CompatibilityLevel defines the global schema compatibility level for Schema Registry.
+	// It is applied through the REST API once the registry is ready, without restarting pods,
+	// and levels changed outside of the operator are reverted.
 	// Valid values: none, backward, backward_transitive, forward, forward_transitive, full, full_transitive.
 	// +kubebuilder:default="forward"
 	// +kubebuilder:validation:Enum=none;backward;backward_transitive;forward;forward_transitive;full;full_transitive
@@ -48,6 +62,12 @@ type StrimziSchemaRegistrySpec struct {
 
 	SecureHTTP bool `json:"securehttp"`
 
+	// DualListener keeps the plain HTTP listener (8081) next to the HTTPS one (8085)
+	// while SecureHTTP is enabled, so clients can migrate to TLS gradually. The Service
+	// then carries both ports and status.listeners lists them.
+	// +optional
+	DualListener bool `json:"dualListener,omitempty"`
+
 	// TLSSecretName is the name of the Kubernetes secret containing TLS certificates.
 	// Must be a valid Kubernetes resource name (DNS subdomain) or empty.
 	// +kubebuilder:default=""
@@ -56,13 +76,545 @@ type StrimziSchemaRegistrySpec struct {
 	// +optional
 	TLSSecretName string `json:"tlssecretname,omitempty"`
 
+	// TLS configures client certificate authentication on the HTTPS REST API.
+	// +optional
+	TLS *TLSSpec `json:"tls,omitempty"`
+
+	// Authentication configures authentication on the REST API.
+	// +optional
+	Authentication *AuthenticationSpec `json:"authentication,omitempty"`
+
 	// HeapOpts sets the JVM heap options for Schema Registry (defaults to "-Xms512M -Xmx512M")
 	// +kubebuilder:validation:Pattern="^(-Xms[a-fA-F0-9]+(m|M|g|G) -Xmx[a-fA-F0-9]+(m|M|g|G))?$"
 	HeapOpts string `json:"heapopts,omitempty"`
 
+	// Replicas is the number of Schema Registry pods (defaults to 1).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Availability makes the operator manage a PodDisruptionBudget and default pod spreading.
+	// +optional
+	Availability *AvailabilitySpec `json:"availability,omitempty"`
+
+	// Autoscaling makes the operator manage a HorizontalPodAutoscaler for the deployment.
+	// While it is set, Replicas is only used as the initial replica count.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// Service customizes the Service in front of Schema Registry.
+	// +optional
+	Service *ServiceSpec `json:"service,omitempty"`
+
+	// Expose makes the operator publish the REST API outside the cluster through an
+	// Ingress, a Gateway API HTTPRoute or an OpenShift Route.
+	// +optional
+	Expose *ExposeSpec `json:"expose,omitempty"`
+
+	// Connection tunes the client connection ConfigMap and Secret ("<name>-connection")
+	// the operator publishes for application teams.
+	// +optional
+	Connection *ConnectionSpec `json:"connection,omitempty"`
+
+	// Probes overrides the probe timings and selects how readiness is checked.
+	// +optional
+	Probes *ProbesSpec `json:"probes,omitempty"`
+
+	// SchemaExport makes the operator export the registered schemas as a file tree
+	// ("<subject>/<version>.avsc|.proto|.json" plus manifest.json) for review.
+	// +optional
+	SchemaExport *SchemaExportSpec `json:"schemaExport,omitempty"`
+
+	// SchemaPolicy lists the checks StrimziSchemas registered into this registry must pass
+	// before the operator calls the registry.
+	// +optional
+	SchemaPolicy *SchemaPolicySpec `json:"schemaPolicy,omitempty"`
+
+	// Tenancy shares the registry with other namespaces, each confined to its own context.
+	// +optional
+	Tenancy *TenancySpec `json:"tenancy,omitempty"`
+
+	// Role is the role of the registry in a multi-datacenter layout: primary instances are
+	// eligible to become the leader that writes schemas, secondary ones never are.
+	// +kubebuilder:default=primary
+	// +optional
+	Role RegistryRole `json:"role,omitempty"`
+
+	// Secondary configures a registry whose role is secondary.
+	// +optional
+	Secondary *SecondarySpec `json:"secondary,omitempty"`
+
+	// Version is the registry version to run. The operator resolves it to an image through its
+	// version table, which overrides the image of the registry container in the template.
+	// Changing it runs pre-flight checks before rolling the pods, and a rollout that does not
+	// become ready is rolled back. When empty, the template image is run as is.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9][A-Za-z0-9._-]*$"
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Upgrade configures how changes of spec.version are rolled out.
+	// +optional
+	Upgrade *UpgradeSpec `json:"upgrade,omitempty"`
+
+	// Flavor is the registry implementation run by the template image: Confluent Schema
+	// Registry, Karapace, or Apicurio Registry with its Kafka storage. It selects how the
+	// container is configured, the format of the Kafka store credentials and the probes.
+	// +kubebuilder:default=confluent
+	// +optional
+	Flavor RegistryFlavor `json:"flavor,omitempty"`
+
+	// Template is the pod template for Schema Registry. The operator manages the
+	// container named "schema-registry" (or the only container when there is just one)
+	// and merges its own env vars, volumes and mounts with the ones defined here;
+	// user entries must not reuse operator-managed names.
 	Template corev1.PodTemplateSpec `json:"template"`
 }
 
+// ClientAuthMode selects whether REST API clients must present a certificate.
+// +kubebuilder:validation:Enum=None;Requested;Required
+type ClientAuthMode string
+
+const (
+	// ClientAuthNone does not ask clients for a certificate.
+	ClientAuthNone ClientAuthMode = "None"
+	// ClientAuthRequested verifies a client certificate when one is presented.
+	ClientAuthRequested ClientAuthMode = "Requested"
+	// ClientAuthRequired rejects clients without a certificate signed by the client CA.
+	ClientAuthRequired ClientAuthMode = "Required"
+)
+
+// TLSSpec configures mutual TLS on the REST API.
+type TLSSpec struct {
+	// ClientAuth selects whether clients must present a certificate (defaults to "None").
+	// +kubebuilder:default=None
+	// +optional
+	ClientAuth ClientAuthMode `json:"clientAuth,omitempty"`
+
+	// ClientCASecretName is a Secret in the same namespace holding the CA that signs client
+	// certificates. Defaults to the Strimzi clients CA ("<cluster>-clients-ca-cert"), so
+	// KafkaUser certificates can also be used as Schema Registry client credentials.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ClientCASecretName string `json:"clientCASecretName,omitempty"`
+
+	// ClientCAKey is the key of the CA certificate in ClientCASecretName (defaults to "ca.crt").
+	// +optional
+	ClientCAKey string `json:"clientCAKey,omitempty"`
+}
+
+// AuthenticationSpec configures authentication on the REST API.
+type AuthenticationSpec struct {
+	// Basic enables HTTP Basic authentication.
+	// +optional
+	Basic *BasicAuthenticationSpec `json:"basic,omitempty"`
+}
+
+// BasicAuthenticationSpec configures HTTP Basic authentication backed by a users Secret.
+type BasicAuthenticationSpec struct {
+	// UsersSecretName is a Secret in the same namespace with one key per user name. Each
+	// value is "<password>[,<role>...]", as in a Jetty password file. The operator renders
+	// the JAAS config and password file from it and restarts the pods when it changes.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	UsersSecretName string `json:"usersSecretName"`
+
+	// Realm is the JAAS realm (defaults to "SchemaRegistry").
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9_.-]*$"
+	// +optional
+	Realm string `json:"realm,omitempty"`
+
+	// Roles lists the roles allowed to use the REST API. Users without one of them are
+	// rejected. Defaults to any authenticated user.
+	// +optional
+	Roles []string `json:"roles,omitempty"`
+}
+
+// ConnectionSpec tunes the published client connection ConfigMap and Secret.
+type ConnectionSpec struct {
+	// CASecretName is a Secret in the same namespace holding the CA (key "ca.crt") that signs
+	// the REST API certificate. Defaults to the Strimzi cluster CA, which signs the certificate
+	// generated by the operator; set it when tlssecretname is signed by another CA.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	CASecretName string `json:"caSecretName,omitempty"`
+
+	// BasicAuthUser is the user of the Basic authentication users secret whose credentials
+	// are published in the connection Secret.
+	// +optional
+	BasicAuthUser string `json:"basicAuthUser,omitempty"`
+
+	// NamespaceSelector copies the ConfigMap and Secret into every namespace matching it,
+	// so applications can mount them directly.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// PodAntiAffinityMode selects the default pod anti-affinity injected by the operator.
+// +kubebuilder:validation:Enum=Preferred;Required;None
+type PodAntiAffinityMode string
+
+const (
+	PodAntiAffinityPreferred PodAntiAffinityMode = "Preferred"
+	PodAntiAffinityRequired  PodAntiAffinityMode = "Required"
+	PodAntiAffinityNone      PodAntiAffinityMode = "None"
+)
+
+// ZoneSpreadMode selects the default zone topology spread constraint injected by the operator.
+// +kubebuilder:validation:Enum=ScheduleAnyway;DoNotSchedule;None
+type ZoneSpreadMode string
+
+const (
+	ZoneSpreadScheduleAnyway ZoneSpreadMode = "ScheduleAnyway"
+	ZoneSpreadDoNotSchedule  ZoneSpreadMode = "DoNotSchedule"
+	ZoneSpreadNone           ZoneSpreadMode = "None"
+)
+
+// AvailabilitySpec configures the PodDisruptionBudget and pod spreading of Schema Registry.
+// +kubebuilder:validation:XValidation:rule="!(has(self.minAvailable) && has(self.maxUnavailable))",message="minAvailable and maxUnavailable are mutually exclusive"
+type AvailabilitySpec struct {
+	// MinAvailable is the PodDisruptionBudget minAvailable. Mutually exclusive with MaxUnavailable.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is the PodDisruptionBudget maxUnavailable. Defaults to 1 when
+	// neither MinAvailable nor MaxUnavailable is set.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// PodAntiAffinity is injected across nodes unless the template already defines pod anti-affinity
+	// (defaults to "Preferred").
+	// +kubebuilder:default=Preferred
+	// +optional
+	PodAntiAffinity PodAntiAffinityMode `json:"podAntiAffinity,omitempty"`
+
+	// ZoneSpread is the topology spread constraint injected across zones unless the template
+	// already defines topology spread constraints (defaults to "ScheduleAnyway").
+	// +kubebuilder:default=ScheduleAnyway
+	// +optional
+	ZoneSpread ZoneSpreadMode `json:"zoneSpread,omitempty"`
+}
+
+// AutoscalingSpec configures the HorizontalPodAutoscaler of Schema Registry.
+// +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must not be greater than maxReplicas"
+type AutoscalingSpec struct {
+	// MinReplicas is the lower replica limit (defaults to 1).
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper replica limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the average CPU utilization target across pods.
+	// Defaults to 80 when no Metrics are given.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// Metrics are additional autoscaling/v2 metric targets (e.g. pod or external metrics).
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Behavior configures the scale up and scale down behavior of the autoscaler.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// ServiceSpec configures the Schema Registry Service.
+// +kubebuilder:validation:XValidation:rule="!has(self.nodePort) || self.type != 'ClusterIP'",message="nodePort requires type NodePort or LoadBalancer"
+// +kubebuilder:validation:XValidation:rule="!has(self.loadBalancerSourceRanges) || self.type == 'LoadBalancer'",message="loadBalancerSourceRanges requires type LoadBalancer"
+type ServiceSpec struct {
+	// Type is the Service type (defaults to "ClusterIP").
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	// +kubebuilder:default=ClusterIP
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Port is the Service port of the REST API (defaults to 80, or 443 with SecureHTTP).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// NodePort pins the node port of the REST API for NodePort and LoadBalancer Services.
+	// A port is allocated by Kubernetes when unset.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	NodePort *int32 `json:"nodePort,omitempty"`
+
+	// JMXPort enables remote JMX in the Schema Registry container on this port and
+	// publishes it on the Service as "jmx", e.g. for a metrics exporter.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	JMXPort *int32 `json:"jmxPort,omitempty"`
+
+	// Annotations are added to the Service.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Labels are added to the Service.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// LoadBalancerSourceRanges restricts the client IPs of a LoadBalancer Service.
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+}
+
+// ExposeType selects the kind of resource used to expose Schema Registry.
+// +kubebuilder:validation:Enum=Ingress;HTTPRoute;Route
+type ExposeType string
+
+const (
+	ExposeIngress   ExposeType = "Ingress"
+	ExposeHTTPRoute ExposeType = "HTTPRoute"
+	ExposeRoute     ExposeType = "Route"
+)
+
+// TLSTermination selects how TLS is handled in front of a SecureHTTP registry.
+// +kubebuilder:validation:Enum=Passthrough;Reencrypt
+type TLSTermination string
+
+const (
+	// TLSTerminationPassthrough forwards the TLS connection untouched to the registry.
+	TLSTerminationPassthrough TLSTermination = "Passthrough"
+	// TLSTerminationReencrypt terminates TLS at the edge and opens a new TLS connection
+	// to the registry, verified against the Strimzi cluster CA.
+	TLSTerminationReencrypt TLSTermination = "Reencrypt"
+)
+
+// ExposeSpec configures how Schema Registry is exposed outside the cluster.
+// +kubebuilder:validation:XValidation:rule="self.type == 'Route' || has(self.host)",message="host is required for Ingress and HTTPRoute"
+// +kubebuilder:validation:XValidation:rule="self.type != 'HTTPRoute' || has(self.gateway)",message="gateway is required for HTTPRoute"
+type ExposeSpec struct {
+	// Type is the kind of resource generated by the operator.
+	Type ExposeType `json:"type"`
+
+	// Host is the external host name. Optional for Route, where OpenShift generates one.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// Annotations are added to the generated resource.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IngressClassName is the IngressClass of the generated Ingress.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// TLSSecretName is the certificate served by the Ingress for Host when TLS is
+	// terminated at the edge.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Gateway is the Gateway the HTTPRoute (or TLSRoute) attaches to.
+	// +optional
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+
+	// TLSTermination selects passthrough or re-encrypt when SecureHTTP is enabled
+	// (defaults to "Passthrough"). Ignored for plain HTTP.
+	// +kubebuilder:default=Passthrough
+	// +optional
+	TLSTermination TLSTermination `json:"tlsTermination,omitempty"`
+}
+
+// GatewayReference points to a Gateway API Gateway listener.
+type GatewayReference struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+
+	// Namespace of the Gateway (defaults to the namespace of the StrimziSchemaRegistry).
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the Gateway listener to attach to.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// ReadinessCheck selects what the readiness and startup probes verify.
+// +kubebuilder:validation:Enum=Listener;Subjects
+type ReadinessCheck string
+
+const (
+	// ReadinessCheckListener only checks that the REST listener answers: an HTTP GET on "/"
+	// for plain HTTP, or a TCP connection when SecureHTTP is enabled.
+	ReadinessCheckListener ReadinessCheck = "Listener"
+	// ReadinessCheckSubjects queries "/subjects", which only succeeds once the registry has
+	// read its schemas topic from Kafka. Over HTTPS the check runs curl inside the container
+	// and trusts the CA of the REST API certificate, falling back to a TCP check when a custom
+	// certificate has no connection.caSecretName. It is rejected with Basic authentication or
+	// tls.clientAuth Required, as the probes carry no credentials.
+	ReadinessCheckSubjects ReadinessCheck = "Subjects"
+)
+
+// ProbesSpec configures the probes of the Schema Registry container.
+type ProbesSpec struct {
+	// ReadinessCheck selects what the readiness and startup probes verify (defaults to "Listener").
+	// +kubebuilder:default=Listener
+	// +optional
+	ReadinessCheck ReadinessCheck `json:"readinessCheck,omitempty"`
+
+	// Readiness overrides the readiness probe timings.
+	// +optional
+	Readiness *ProbeTiming `json:"readiness,omitempty"`
+
+	// Liveness overrides the liveness probe timings.
+	// +optional
+	Liveness *ProbeTiming `json:"liveness,omitempty"`
+
+	// Startup overrides the startup probe timings.
+	// +optional
+	Startup *ProbeTiming `json:"startup,omitempty"`
+}
+
+// ProbeTiming holds probe timing overrides. Unset fields keep the operator defaults.
+type ProbeTiming struct {
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// SchemaExportSpec selects when and where the schema tree is exported.
+type SchemaExportSpec struct {
+	// Schedule is a cron expression in UTC. Exports run at most once a minute.
+	// +kubebuilder:default="*/10 * * * *"
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// PersistentVolumeClaim writes the tree to a volume with a CronJob instead of the
+	// "<name>-schemas" ConfigMap.
+	// +optional
+	PersistentVolumeClaim *PersistentVolumeClaimBackupStorage `json:"persistentVolumeClaim,omitempty"`
+}
+
+// SchemaPolicySpec defines the checks applied to schema definitions before registration.
+type SchemaPolicySpec struct {
+	// RequireDoc requires a doc (Avro), description (JSON Schema) or comment (Protobuf) on the
+	// top-level record or message and on every field.
+	// +optional
+	RequireDoc bool `json:"requireDoc,omitempty"`
+
+	// NamespacePattern is a regular expression the Avro namespace, Protobuf package or JSON
+	// Schema $id must fully match.
+	// +optional
+	NamespacePattern string `json:"namespacePattern,omitempty"`
+
+	// MaxSchemaBytes limits the size of schema definitions.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSchemaBytes *int32 `json:"maxSchemaBytes,omitempty"`
+
+	// ForbidFieldTypeChanges rejects schemas changing the type of a field of the latest version
+	// of their subject.
+	// +optional
+	ForbidFieldTypeChanges bool `json:"forbidFieldTypeChanges,omitempty"`
+
+	// Rules are CEL expressions schemas must satisfy. They see the variables subject, schema and
+	// previous (the latest version of the subject, or null).
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Rules []SchemaPolicyRule `json:"rules,omitempty"`
+
+	// RulesFrom reads more CEL rules from a ConfigMap in the namespace of the registry: each key
+	// is a rule name and its value the expression.
+	// +optional
+	RulesFrom *corev1.LocalObjectReference `json:"rulesFrom,omitempty"`
+}
+
+// RegistryRole is the role of a registry in a multi-datacenter layout.
+// +kubebuilder:validation:Enum=primary;secondary
+type RegistryRole string
+
+const (
+	// RegistryRolePrimary instances are eligible to become the leader.
+	RegistryRolePrimary RegistryRole = "primary"
+	// RegistryRoleSecondary instances are not eligible: they forward writes to the leader of
+	// their group or, reading a replicated schemas topic, serve reads only.
+	RegistryRoleSecondary RegistryRole = "secondary"
+)
+
+// RegistryFlavor is a registry implementation.
+// +kubebuilder:validation:Enum=confluent;karapace;apicurio
+type RegistryFlavor string
+
+const (
+	// RegistryFlavorConfluent is Confluent Schema Registry, configured with SCHEMA_REGISTRY_*
+	// variables and JKS stores.
+	RegistryFlavorConfluent RegistryFlavor = "confluent"
+	// RegistryFlavorKarapace is Karapace, configured with KARAPACE_* variables and PEM files.
+	RegistryFlavorKarapace RegistryFlavor = "karapace"
+	// RegistryFlavorApicurio is Apicurio Registry with KafkaSQL storage, served to the operator
+	// and to clients through its Confluent-compatible API.
+	RegistryFlavorApicurio RegistryFlavor = "apicurio"
+)
+
+// SecondarySpec configures a secondary registry.
+type SecondarySpec struct {
+	// SchemasTopic is the topic the registry reads its schemas from, e.g. the schemas topic of
+	// the primary replicated by MirrorMaker 2 ("primary.registry-schemas" with the default
+	// replication policy). Defaults to the topic of primary registries, "registry-schemas".
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9._-]+$"
+	// +optional
+	SchemasTopic string `json:"schemasTopic,omitempty"`
+
+	// ReadOnly makes the operator treat the registry as READONLY: StrimziSchemas are looked up
+	// instead of registered, and settings, restores and copies into the registry are rejected.
+	// Set it when the registry reads a replicated topic and has no leader to forward writes to.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// TenancySpec maps the namespaces sharing a registry to contexts. StrimziSchemas and
+// StrimziSchemaSubjectConfigs of another namespace only touch the subjects of its context;
+// those of the registry namespace are not confined.
+type TenancySpec struct {
+	// NamespaceSelector selects the namespaces allowed to use the registry besides its own.
+	// Every namespace is allowed when unset.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// ContextPrefix is prepended to the namespace name to name its context, e.g. "tenant-"
+	// confines the namespace "team-a" to the ":.tenant-team-a:" context.
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9_.-]*$"
+	// +optional
+	ContextPrefix string `json:"contextPrefix,omitempty"`
+}
+
+// SchemaPolicyRule is a CEL expression schemas must satisfy.
+type SchemaPolicyRule struct {
+	// Name identifies the rule in violations.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][-A-Za-z0-9_.]*$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Expression is a CEL expression returning true for compliant schemas, e.g.
+	// "schema.fields.all(f, f.path.matches('^[a-z][a-zA-Z0-9.]*$'))".
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+
+	// Message reports a violation. Defaults to the expression.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // StrimziSchemaRegistryStatus defines the observed state of StrimziSchemaRegistry
 type StrimziSchemaRegistryStatus struct {
 	//Conditions represent the observation of Schema Registry's current state
@@ -73,11 +625,146 @@ type StrimziSchemaRegistryStatus struct {
 
 	// Status of the Schema Registry deployment (Ok, Not Ready, etc.)
 	Status string `json:"status"`
+
+	// Listeners lists the REST API listeners currently served through the Service.
+	// +optional
+	Listeners []ListenerStatus `json:"listeners,omitempty"`
+
+	// Autoscaling reports the HorizontalPodAutoscaler state when spec.autoscaling is set.
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// CompatibilityLevel is the global compatibility level last applied through the REST API.
+	// +optional
+	CompatibilityLevel string `json:"compatibilityLevel,omitempty"`
+
+	// LastCompatibilityDriftTime is the last time a global compatibility level changed outside
+	// of the operator was reverted.
+	// +optional
+	LastCompatibilityDriftTime *metav1.Time `json:"lastCompatibilityDriftTime,omitempty"`
+
+	// SchemaExport reports the last schema export when spec.schemaExport is set.
+	// +optional
+	SchemaExport *SchemaExportStatus `json:"schemaExport,omitempty"`
+
+	// Version is the version the pods run, recorded once a rollout of spec.version completed.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Upgrade reports the current or last change of spec.version.
+	// +optional
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+}
+
+// UpgradeSpec configures version upgrades.
+type UpgradeSpec struct {
+	// Backup, when set, takes a snapshot of the registry into this storage before the rollout,
+	// through a StrimziSchemaRegistryBackup named after the registry and the target version.
+	// +optional
+	Backup *BackupStorage `json:"backup,omitempty"`
+
+	// ProgressDeadlineSeconds is how long the rollout may take to become ready before it is
+	// rolled back to the previous ReplicaSet.
+	// +kubebuilder:default=600
+	// +kubebuilder:validation:Minimum=30
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// UpgradePhase is the progress of a version upgrade.
+// +kubebuilder:validation:Enum=PreflightChecks;RollingOut;Succeeded;RolledBack;Unsupported
+type UpgradePhase string
+
+const (
+	// UpgradePhasePreflightChecks waits for the registry to answer and the backup to complete.
+	UpgradePhasePreflightChecks UpgradePhase = "PreflightChecks"
+	// UpgradePhaseRollingOut rolls the pods to the image of the target version.
+	UpgradePhaseRollingOut UpgradePhase = "RollingOut"
+	// UpgradePhaseSucceeded means the pods run the target version.
+	UpgradePhaseSucceeded UpgradePhase = "Succeeded"
+	// UpgradePhaseRolledBack means the rollout did not become ready in time and the previous
+	// ReplicaSet was restored. The target version is not retried until spec.version changes.
+	UpgradePhaseRolledBack UpgradePhase = "RolledBack"
+	// UpgradePhaseUnsupported means the target version is not in the version table.
+	UpgradePhaseUnsupported UpgradePhase = "Unsupported"
+)
+
+// UpgradeStatus reports a version upgrade.
+type UpgradeStatus struct {
+	// FromVersion is the version running when the upgrade started, empty for a new registry
+	// or one whose version was not managed.
+	// +optional
+	FromVersion string `json:"fromVersion,omitempty"`
+
+	// ToVersion is the target version.
+	ToVersion string `json:"toVersion"`
+
+	// Image is the image of the target version.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Phase is the progress of the upgrade.
+	Phase UpgradePhase `json:"phase"`
+
+	// Backup is the StrimziSchemaRegistryBackup taken before the rollout.
+	// +optional
+	Backup string `json:"backup,omitempty"`
+
+	// StartTime is when the current phase started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Message details the phase, e.g. the failed pre-flight check.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SchemaExportStatus reports the last schema export.
+type SchemaExportStatus struct {
+	// LastExportTime is the time of the last successful export.
+	// +optional
+	LastExportTime *metav1.Time `json:"lastExportTime,omitempty"`
+
+	// Subjects is the number of exported subjects. It is not reported for volume exports.
+	// +optional
+	Subjects int32 `json:"subjects,omitempty"`
+
+	// Schemas is the number of exported subject versions. It is not reported for volume exports.
+	// +optional
+	Schemas int32 `json:"schemas,omitempty"`
+}
+
+// ListenerStatus describes an active REST API listener.
+type ListenerStatus struct {
+	// Name is the listener and Service port name ("http" or "https").
+	Name string `json:"name"`
+
+	// Port is the Service port of the listener.
+	Port int32 `json:"port"`
+
+	// URL is the in-cluster address of the listener.
+	URL string `json:"url"`
+}
+
+// AutoscalingStatus mirrors the replica counts of the managed HorizontalPodAutoscaler.
+type AutoscalingStatus struct {
+	// CurrentReplicas is the number of replicas last observed by the autoscaler.
+	CurrentReplicas int32 `json:"currentReplicas"`
+
+	// DesiredReplicas is the number of replicas the autoscaler wants.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+
+	// LastScaleTime is the last time the autoscaler scaled the deployment.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.status",description="The status of Schema Registry"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".spec.role",description="The role of Schema Registry"
+// +kubebuilder:printcolumn:name="Flavor",type="string",JSONPath=".spec.flavor",description="The registry implementation"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version",description="The version the pods run"
 // StrimziSchemaRegistry is the Schema for the strimzischemaregistries API
 type StrimziSchemaRegistry struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BackupStorage is where the snapshots of a backup are stored. Exactly one kind must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.configMap) ? 1 : 0) + (has(self.persistentVolumeClaim) ? 1 : 0) + (has(self.objectStore) ? 1 : 0) == 1",message="exactly one of configMap, persistentVolumeClaim and objectStore must be set"
+type BackupStorage struct {
+	// ConfigMap stores the snapshots in ConfigMaps of the backup namespace, named
+	// "<backup>-<snapshot>-<part>". Suited to small registries.
+	// +optional
+	ConfigMap *ConfigMapBackupStorage `json:"configMap,omitempty"`
+
+	// PersistentVolumeClaim stores the snapshots as files of a volume, written by a Job.
+	// +optional
+	PersistentVolumeClaim *PersistentVolumeClaimBackupStorage `json:"persistentVolumeClaim,omitempty"`
+
+	// ObjectStore stores the snapshots in an S3-compatible bucket.
+	// +optional
+	ObjectStore *ObjectStoreBackupStorage `json:"objectStore,omitempty"`
+}
+
+// ConfigMapBackupStorage stores snapshots in ConfigMaps.
+type ConfigMapBackupStorage struct{}
+
+// PersistentVolumeClaimBackupStorage is a directory of a volume holding backup snapshots
+// ("<snapshot>.json.gz" files) or an exported schema tree.
+type PersistentVolumeClaimBackupStorage struct {
+	// ClaimName is the PersistentVolumeClaim, in the namespace of the resource.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// Path is the directory within the volume. Defaults to the volume root.
+	// +kubebuilder:validation:Pattern="^[^.][A-Za-z0-9_./-]*$"
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// ObjectStoreBackupStorage stores snapshots as "<prefix>/<snapshot>.json.gz" objects of an
+// S3-compatible bucket, addressed path-style.
+type ObjectStoreBackupStorage struct {
+	// Endpoint is the base URL of the object store, e.g. "https://s3.eu-west-1.amazonaws.com".
+	// +kubebuilder:validation:Pattern="^https?://"
+	Endpoint string `json:"endpoint"`
+
+	// Bucket holding the snapshots.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Prefix of the object keys.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Region used to sign the requests.
+	// +kubebuilder:default="us-east-1"
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// CredentialsSecretName is a Secret in the backup namespace holding the AWS_ACCESS_KEY_ID and
+	// AWS_SECRET_ACCESS_KEY keys.
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretName string `json:"credentialsSecretName"`
+}
+
+// BackupRestore selects the snapshot restored into the registry.
+type BackupRestore struct {
+	// Snapshot is the name of the snapshot to restore (e.g. "20261019-030000"). Defaults to the latest one.
+	// +kubebuilder:validation:Pattern="^[a-z0-9][a-z0-9.-]*$"
+	// +optional
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+// StrimziSchemaRegistryBackupSpec defines the desired state of StrimziSchemaRegistryBackup
+type StrimziSchemaRegistryBackupSpec struct {
+	// Registry is the StrimziSchemaRegistry backed up or restored.
+	Registry RegistryReference `json:"registry"`
+
+	// Storage is where the snapshots are stored.
+	Storage BackupStorage `json:"storage"`
+
+	// Schedule is a cron expression in UTC (e.g. "0 3 * * *") taking a snapshot at every tick.
+	// When empty, a single snapshot is taken for every generation of the resource.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Suspend stops the scheduled snapshots.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// KeepLast is the number of snapshots kept in the storage, older ones being deleted.
+	// +kubebuilder:default=7
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepLast int32 `json:"keepLast,omitempty"`
+
+	// Restore, when set, restores a snapshot into the registry once per generation instead of
+	// taking snapshots. The registry must hold no schemas: it is switched to IMPORT mode and the
+	// schemas are registered with their original IDs.
+	// +optional
+	Restore *BackupRestore `json:"restore,omitempty"`
+}
+
+// StrimziSchemaRegistryBackupStatus defines the observed state of StrimziSchemaRegistryBackup
+type StrimziSchemaRegistryBackupStatus struct {
+	// Conditions represent the state of the backup: Ready.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// LastSnapshot is the name of the last snapshot taken.
+	// +optional
+	LastSnapshot string `json:"lastSnapshot,omitempty"`
+
+	// LastBackupTime is the time the last snapshot was taken.
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
+
+	// Subjects is the number of subjects in the last snapshot. It is not reported for snapshots
+	// written by a Job.
+	// +optional
+	Subjects int32 `json:"subjects,omitempty"`
+
+	// Schemas is the number of subject versions in the last snapshot. It is not reported for
+	// snapshots written by a Job.
+	// +optional
+	Schemas int32 `json:"schemas,omitempty"`
+
+	// LastScheduleTime is the last time a scheduled snapshot was started.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the time of the next scheduled snapshot.
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// RestoredSnapshot is the name of the last snapshot restored.
+	// +optional
+	RestoredSnapshot string `json:"restoredSnapshot,omitempty"`
+
+	// RestoreTime is the time the last restore completed.
+	// +optional
+	RestoreTime *metav1.Time `json:"restoreTime,omitempty"`
+
+	// ActiveJob is the Job writing or reading a snapshot on the volume, while it runs.
+	// +optional
+	ActiveJob string `json:"activeJob,omitempty"`
+
+	// ObservedGeneration is the generation last acted on.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Registry",type="string",JSONPath=".spec.registry.name"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Last Snapshot",type="string",JSONPath=".status.lastSnapshot"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// StrimziSchemaRegistryBackup takes snapshots of the subjects, schemas and configuration of a
+// StrimziSchemaRegistry, or restores one
+type StrimziSchemaRegistryBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StrimziSchemaRegistryBackupSpec   `json:"spec,omitempty"`
+	Status StrimziSchemaRegistryBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StrimziSchemaRegistryBackupList contains a list of StrimziSchemaRegistryBackup
+type StrimziSchemaRegistryBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StrimziSchemaRegistryBackup `json:"items"`
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CompatibilityLevel is a Schema Registry compatibility level.
+// +kubebuilder:validation:Enum=NONE;BACKWARD;BACKWARD_TRANSITIVE;FORWARD;FORWARD_TRANSITIVE;FULL;FULL_TRANSITIVE
+type CompatibilityLevel string
+
+// RegistryMode is a Schema Registry mode.
+// +kubebuilder:validation:Enum=READWRITE;READONLY;IMPORT
+type RegistryMode string
+
+const (
+	RegistryModeReadWrite RegistryMode = "READWRITE"
+	RegistryModeReadOnly  RegistryMode = "READONLY"
+	RegistryModeImport    RegistryMode = "IMPORT"
+)
+
+// StrimziSchemaSubjectConfigSpec defines the desired state of StrimziSchemaSubjectConfig
+// +kubebuilder:validation:XValidation:rule="has(self.subject) != has(self.context)",message="exactly one of subject and context must be set"
+// +kubebuilder:validation:XValidation:rule="has(self.compatibility) || has(self.mode) || has(self.normalize)",message="at least one of compatibility, mode and normalize must be set"
+// +kubebuilder:validation:XValidation:rule="self.registry == oldSelf.registry",message="registry is immutable"
+// +kubebuilder:validation:XValidation:rule="(has(self.subject) ? self.subject : '') == (has(oldSelf.subject) ? oldSelf.subject : '')",message="subject is immutable"
+// +kubebuilder:validation:XValidation:rule="(has(self.context) ? self.context : '') == (has(oldSelf.context) ? oldSelf.context : '')",message="context is immutable"
+type StrimziSchemaSubjectConfigSpec struct {
+	// Registry is the StrimziSchemaRegistry the configuration is applied to.
+	Registry RegistryReference `json:"registry"`
+
+	// Subject the configuration applies to.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// Context the configuration applies to, without the surrounding ":." and ":" (e.g. "team-a").
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern="^[A-Za-z0-9_.-]+$"
+	// +optional
+	Context string `json:"context,omitempty"`
+
+	// Compatibility overrides the global compatibility level.
+	// +optional
+	Compatibility CompatibilityLevel `json:"compatibility,omitempty"`
+
+	// Mode overrides the global mode. IMPORT is only accepted on subjects without schemas.
+	// +optional
+	Mode RegistryMode `json:"mode,omitempty"`
+
+	// Normalize makes the registry normalize schemas before registering and looking them up.
+	// +optional
+	Normalize *bool `json:"normalize,omitempty"`
+}
+
+// StrimziSchemaSubjectConfigStatus defines the observed state of StrimziSchemaSubjectConfig
+type StrimziSchemaSubjectConfigStatus struct {
+	// Conditions represent the state of the configuration: Ready.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// Compatibility is the compatibility level last applied by the operator.
+	// +optional
+	Compatibility CompatibilityLevel `json:"compatibility,omitempty"`
+
+	// Mode is the mode last applied by the operator.
+	// +optional
+	Mode RegistryMode `json:"mode,omitempty"`
+
+	// Normalize is the normalize flag last applied by the operator.
+	// +optional
+	Normalize *bool `json:"normalize,omitempty"`
+
+	// LastDriftTime is the last time a configuration changed outside of the operator was reverted.
+	// +optional
+	LastDriftTime *metav1.Time `json:"lastDriftTime,omitempty"`
+
+	// ObservedGeneration is the generation last applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Subject",type="string",JSONPath=".spec.subject"
+// +kubebuilder:printcolumn:name="Context",type="string",JSONPath=".spec.context"
+// +kubebuilder:printcolumn:name="Compatibility",type="string",JSONPath=".status.compatibility"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".status.mode"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// StrimziSchemaSubjectConfig is the compatibility and mode of a subject or context of a StrimziSchemaRegistry
+type StrimziSchemaSubjectConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StrimziSchemaSubjectConfigSpec   `json:"spec,omitempty"`
+	Status StrimziSchemaSubjectConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// StrimziSchemaSubjectConfigList contains a list of StrimziSchemaSubjectConfig
+type StrimziSchemaSubjectConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []StrimziSchemaSubjectConfig `json:"items"`
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 type CertProcessor struct {
@@ -90,7 +91,7 @@ func (cp *CertProcessor) removeIfExists(path string) {
 //         The content of the Kafka cluster CA certificate. You can get this from
 //         a Kubernetes Secret named ``<cluster>-cluster-ca-cert``, and
 //         specifially the secret key named ``ca.crt``. See
-//         `get_cluster_ca_cert`.
+//         `get_cluster_ca_cert`. Every certificate of a PEM bundle is imported.
 
 //	Returns
 //	-------
@@ -116,30 +117,38 @@ func (cp *CertProcessor) CreateTruststore(cert string, password string) ([]byte,
 		}
 	}
 
-	// Write CA certificate to a temp file; removed immediately after keytool consumes it.
-	caCertPath, err := writeTempFile("ca_cert", cert)
-	if err != nil {
-		cp.log.Error(err, "Failed to write temp file", "File", "ca_cert")
-		return nil, "", err
-	}
-	defer cp.removeIfExists(caCertPath)
-
 	// Truststore output path — use a dedicated subdirectory when available to
 	// reduce exposure in shared /tmp. Falls back to os.TempDir().
 	outputPath := filepath.Join(os.TempDir(), "client.truststore.jks")
 	defer cp.removeIfExists(outputPath)
 
-	// Generate truststore
-	cmd := exec.Command("keytool", "-importcert", "-keystore", outputPath, "-alias", "CARoot", "-file",
-		caCertPath, "-storepass", password, "-storetype", "jks", "-trustcacerts", "-noprompt")
-	out, err := cmd.Output()
-	if err != nil {
-		cp.log.Error(err, "Error while exec command", "cmdout", string(out))
-		return nil, "", err
-	}
+	// keytool imports a single certificate per call: each certificate of the bundle is
+	// imported under its own alias.
+	for i, caCert := range pemCertificateBlocks(cert) {
+		alias := "CARoot"
+		if i > 0 {
+			alias = fmt.Sprintf("CARoot-%d", i)
+		}
 
-	// Remove the CA cert temp file now that keytool has consumed it.
-	cp.removeIfExists(caCertPath)
+		// Write CA certificate to a temp file; removed immediately after keytool consumes it.
+		caCertPath, err := writeTempFile("ca_cert", caCert)
+		if err != nil {
+			cp.log.Error(err, "Failed to write temp file", "File", "ca_cert")
+			return nil, "", err
+		}
+
+		// Generate truststore
+		cmd := exec.Command("keytool", "-importcert", "-keystore", outputPath, "-alias", alias, "-file",
+			caCertPath, "-storepass", password, "-storetype", "jks", "-trustcacerts", "-noprompt")
+		out, err := cmd.Output()
+
+		// Remove the CA cert temp file now that keytool has consumed it.
+		cp.removeIfExists(caCertPath)
+		if err != nil {
+			cp.log.Error(err, "Error while exec command", "cmdout", string(out))
+			return nil, "", err
+		}
+	}
 
 	// Check if truststore exists
 	if _, err := os.Stat(outputPath); os.IsNotExist(err) {
@@ -160,6 +169,73 @@ func (cp *CertProcessor) CreateTruststore(cert string, password string) ([]byte,
 	return b, password, nil
 }
 
+// pemCertificateBlocks splits a PEM bundle into its distinct certificates, one PEM string
+// each. A bundle without PEM certificates is returned as is, for keytool to report.
+func pemCertificateBlocks(bundle string) []string {
+	var certs []string
+	seen := map[string]bool{}
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" || seen[string(block.Bytes)] {
+			continue
+		}
+		seen[string(block.Bytes)] = true
+		certs = append(certs, string(pem.EncodeToMemory(block)))
+	}
+	if len(certs) == 0 {
+		return []string{bundle}
+	}
+	return certs
+}
+
+// CreatePKCS12Truststore creates a PKCS12 truststore holding every certificate of the PEM
+// bundle cert (a CA secret may carry several certificates while a CA is being renewed).
+// An empty password is replaced by a generated one, which is returned with the truststore.
+// Unlike CreateTruststore it does not need keytool.
+func (cp *CertProcessor) CreatePKCS12Truststore(cert string, password string) ([]byte, string, error) {
+	if password == "" {
+		var err error
+		password, err = GeneratePassword(24, true, false)
+		if err != nil {
+			cp.log.Error(err, "Failed to generate cryptographically secure random number")
+			return nil, "", err
+		}
+	}
+
+	var certs []*x509.Certificate
+	rest := []byte(cert)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, "", err
+		}
+		certs = append(certs, c)
+	}
+	if len(certs) == 0 {
+		return nil, "", errors.New("no PEM certificate found for the PKCS12 truststore")
+	}
+
+	truststore, err := pkcs12.Modern.EncodeTrustStore(certs, password)
+	if err != nil {
+		cp.log.Error(err, "Failed to encode PKCS12 truststore")
+		return nil, "", err
+	}
+	return truststore, password, nil
+}
+
 // Create a JKS-formatted keystore using the client's CA certificate,
 // certificate, and key.
 
@@ -434,6 +510,39 @@ func (cp *CertProcessor) GenerateTLSforHTTP(caCert string, caKey string, passwor
 	return b, password, nil
 }
 
+// GenerateTLSPEMforHTTP signs a REST API server certificate with the cluster CA and returns
+// the certificate chain and its private key in PEM format, for registries that do not read
+// Java keystores.
+func (cp *CertProcessor) GenerateTLSPEMforHTTP(caCert string, caKey string, cn string) ([]byte, []byte, error) {
+	if cn == "" {
+		return nil, nil, fmt.Errorf("common name (CN) cannot be empty")
+	}
+	serverKey, csr, err := cp.generateCSR(cn)
+	if err != nil {
+		cp.log.Error(err, "Failed to generate CSR")
+		return nil, nil, err
+	}
+	ca, err := StringToCertificate(caCert)
+	if err != nil {
+		cp.log.Error(err, "Failed to convert from string to x509 certificate")
+		return nil, nil, err
+	}
+	key, err := StringToPrivateKey(caKey)
+	if err != nil {
+		cp.log.Error(err, "Failed to convert from string to x509 private key")
+		return nil, nil, err
+	}
+	serverCert, err := signCSR(ca, key, csr)
+	if err != nil {
+		cp.log.Error(err, "Failed to sign CSR")
+		return nil, nil, err
+	}
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Raw})
+	chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})...)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(serverKey)})
+	return chain, keyPEM, nil
+}
+
 func (cp *CertProcessor) generateCSR(cn string) (*rsa.PrivateKey, *x509.CertificateRequest, error) {
 	// Generate server private key
 	serverKey, err := rsa.GenerateKey(cryptorand.Reader, 4096)
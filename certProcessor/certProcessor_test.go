@@ -1,11 +1,15 @@
 package certprocessor
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	"unicode"
 
 	"github.com/go-logr/logr"
 	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 // TestGeneratePassword consolidates all password generation tests into subtests (M6).
@@ -226,3 +230,91 @@ func TestGenerateTLSforHTTP_EmptyCN(t *testing.T) {
 		}
 	}
 }
+
+// TestCreatePKCS12Truststore verifies that every certificate of the PEM bundle ends up
+// in the PKCS12 truststore, which needs no external tool.
+func TestCreatePKCS12Truststore(t *testing.T) {
+	oldCA, err := testutil.GenerateClusterCACert("STIMZI-SR-TEST")
+	if err != nil {
+		t.Fatalf("Failed to generate cluster CA: %v", err)
+	}
+	newCA, err := testutil.GenerateClusterCACert("STIMZI-SR-TEST")
+	if err != nil {
+		t.Fatalf("Failed to generate cluster CA: %v", err)
+	}
+
+	cp := NewCertProcessor(logr.Logger{})
+	truststore, password, err := cp.CreatePKCS12Truststore(oldCA.CACertPEM+newCA.CACertPEM, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if password == "" {
+		t.Error("expected a generated password")
+	}
+	certs, err := pkcs12.DecodeTrustStore(truststore, password)
+	if err != nil {
+		t.Fatalf("failed to decode truststore: %v", err)
+	}
+	if len(certs) != 2 {
+		t.Errorf("expected 2 certificates, got %d", len(certs))
+	}
+
+	if _, _, err := cp.CreatePKCS12Truststore("not a valid PEM certificate data", ""); err == nil {
+		t.Error("expected error for invalid PEM data, got nil")
+	}
+}
+
+// TestGenerateTLSPEMforHTTP verifies that the PEM server certificate is signed by the CA
+// and covers the service hostname, without any external tool.
+func TestGenerateTLSPEMforHTTP(t *testing.T) {
+	ca, err := testutil.GenerateTestCA()
+	if err != nil {
+		t.Fatalf("failed to generate CA: %v", err)
+	}
+
+	cp := NewCertProcessor(logr.Logger{})
+	chain, key, err := cp.GenerateTLSPEMforHTTP(ca.CACertPEM, ca.CAKeyPEM, "confluent-schema-registry.kafka")
+	if err != nil {
+		t.Fatalf("GenerateTLSPEMforHTTP() error = %v", err)
+	}
+	if _, err := tls.X509KeyPair(chain, key); err != nil {
+		t.Fatalf("certificate and key do not match: %v", err)
+	}
+
+	block, _ := pem.Decode(chain)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse server certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM([]byte(ca.CACertPEM))
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "confluent-schema-registry.kafka.svc"}); err != nil {
+		t.Errorf("server certificate does not verify against the CA: %v", err)
+	}
+}
+
+// TestPEMCertificateBlocks verifies that a CA bundle is split into its distinct certificates,
+// each imported by CreateTruststore under its own alias.
+func TestPEMCertificateBlocks(t *testing.T) {
+	clientsCA, err := testutil.GenerateClusterCACert("STIMZI-SR-TEST")
+	if err != nil {
+		t.Fatalf("Failed to generate clients CA: %v", err)
+	}
+	clusterCA, err := testutil.GenerateClusterCACert("STIMZI-SR-TEST")
+	if err != nil {
+		t.Fatalf("Failed to generate cluster CA: %v", err)
+	}
+
+	blocks := pemCertificateBlocks(clientsCA.CACertPEM + clusterCA.CACertPEM + clientsCA.CACertPEM)
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 distinct certificates, got %d", len(blocks))
+	}
+	for i, block := range blocks {
+		if p, _ := pem.Decode([]byte(block)); p == nil || p.Type != "CERTIFICATE" {
+			t.Errorf("block %d is not a PEM certificate: %q", i, block)
+		}
+	}
+	if blocks := pemCertificateBlocks("not a valid PEM certificate data"); len(blocks) != 1 {
+		t.Errorf("expected invalid data to be passed through, got %d blocks", len(blocks))
+	}
+}
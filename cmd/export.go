@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/controller"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+// runExportCommand writes the schema tree of a registry (<subject>/<version>.avsc|.proto|.json
+// and manifest.json) to a directory. With --registry, the tree is read from the ConfigMap the
+// operator exports for that StrimziSchemaRegistry, through the cluster of the kubeconfig;
+// otherwise the registry is called directly with the files of --registry-dir, as mounted in
+// the schema export CronJob.
+func runExportCommand(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	dir := flags.String("dir", "", "The directory the schema tree is written to.")
+	registryDir := flags.String("registry-dir", "/etc/registry",
+		"The directory holding the registry url and the optional ca.crt, tls.crt, tls.key, username and password files.")
+	registry := flags.String("registry", "",
+		"The StrimziSchemaRegistry whose exported schema tree is read from the cluster.")
+	namespace := flags.String("namespace", "",
+		"The namespace of the StrimziSchemaRegistry. Defaults to the namespace of the kubeconfig context.")
+	kubeconfig := flags.String("kubeconfig", "",
+		"The kubeconfig file. Defaults to $KUBECONFIG or ~/.kube/config.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errors.New("--dir is required")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	var files map[string][]byte
+	var err error
+	if *registry != "" {
+		files, err = readExportedTree(ctx, *kubeconfig, *namespace, *registry)
+	} else {
+		files, err = readRegistryTree(ctx, *registryDir)
+	}
+	if err != nil {
+		return err
+	}
+	if err := backup.WriteTree(*dir, files); err != nil {
+		return err
+	}
+	fmt.Printf("exported %d files to %s\n", len(files), *dir)
+	return nil
+}
+
+// readRegistryTree builds the schema tree from the REST API of the registry.
+func readRegistryTree(ctx context.Context, registryDir string) (map[string][]byte, error) {
+	cfg, err := backup.ReadRegistryAccess(registryDir)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := registryclient.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := backup.Export(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	return backup.Tree(snapshot)
+}
+
+// readExportedTree reads the schema tree from the export ConfigMap of a StrimziSchemaRegistry.
+func readExportedTree(ctx context.Context, kubeconfig, namespace, registry string) (map[string][]byte, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = kubeconfig
+	overrides := &clientcmd.ConfigOverrides{}
+	overrides.Context.Namespace = namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if namespace, _, err = clientConfig.Namespace(); err != nil {
+		return nil, err
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	configMap := &v1.ConfigMap{}
+	key := types.NamespacedName{Name: controller.SchemaExportName(registry), Namespace: namespace}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		return nil, fmt.Errorf("reading the schemas exported for %s/%s (is spec.schemaExport set?): %w",
+			namespace, registry, err)
+	}
+	return backup.TreeFromConfigMapData(configMap.Data)
+}
@@ -19,6 +19,7 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -28,6 +29,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
@@ -35,9 +37,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
 	"github.com/randsw/schema-registry-operator-strimzi/internal/controller"
+	webhookv1 "github.com/randsw/schema-registry-operator-strimzi/internal/webhook/v1"
+	webhookv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/internal/webhook/v1alpha1"
 	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
 	kafka "github.com/scholzj/strimzi-go/pkg/apis/kafka.strimzi.io/v1"
 	// +kubebuilder:scaffold:imports
@@ -52,15 +57,35 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(strimziregistryoperatorv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(kafka.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.Install(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
+	// The backup Jobs of StrimziSchemaRegistryBackup resources run the operator image with the
+	// backup and restore commands, the schema export CronJobs with the export command.
+	commands := map[string]func(args []string) error{
+		"backup":  func(args []string) error { return runSnapshotCommand("backup", args) },
+		"restore": func(args []string) error { return runSnapshotCommand("restore", args) },
+		"export":  runExportCommand,
+	}
+	if len(os.Args) > 1 && commands[os.Args[1]] != nil {
+		if err := commands[os.Args[1]](os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableWebhooks bool
+	var webhookCertPath string
+	var jobImage string
+	var versionTablePath string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -72,6 +97,17 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the webhooks injecting Schema Registry connection settings into annotated pods and "+
+			"checking the compatibility of StrimziSchemas are served.")
+	flag.StringVar(&webhookCertPath, "webhook-cert-path", "",
+		"The directory that contains the webhook server certificate (tls.crt and tls.key).")
+	flag.StringVar(&jobImage, "job-image", os.Getenv("OPERATOR_IMAGE"),
+		"The operator image run by the Jobs of backups and schema exports stored on persistent volumes. "+
+			"Defaults to $OPERATOR_IMAGE.")
+	flag.StringVar(&versionTablePath, "version-table", "",
+		"A YAML file mapping the versions of each registry flavor to their images, for spec.version. "+
+			"Defaults to the version table built into the operator.")
 	opts := zap.Options{
 		Development:     false,
 		DestWriter:      os.Stdout,
@@ -112,6 +148,7 @@ func main() {
 
 	webhookServer := webhook.NewServer(webhook.Options{
 		TLSOpts: tlsOpts,
+		CertDir: webhookCertPath,
 	})
 
 	// Metrics endpoint is enabled in 'config/default/kustomization.yaml'. The Metrics options configure the server.
@@ -162,13 +199,78 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Detect the optional APIs spec.expose can generate resources for. Installing one of
+	// them later requires an operator restart.
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	availableAPIs, err := controller.DetectAvailableAPIs(discoveryClient)
+	if err != nil {
+		setupLog.Error(err, "unable to detect available APIs")
+		os.Exit(1)
+	}
+	setupLog.Info("detected optional APIs", "ingress", availableAPIs.Ingress, "httpRoute", availableAPIs.HTTPRoute,
+		"tlsRoute", availableAPIs.TLSRoute, "backendTLSPolicy", availableAPIs.BackendTLSPolicy, "route", availableAPIs.Route)
+
+	var versionTable controller.VersionTable
+	if versionTablePath != "" {
+		if versionTable, err = controller.LoadVersionTable(versionTablePath); err != nil {
+			setupLog.Error(err, "unable to load version table")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.StrimziSchemaRegistryReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		AvailableAPIs: availableAPIs,
+		JobImage:      jobImage,
+		Versions:      versionTable,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StrimziSchemaRegistry")
+		os.Exit(1)
+	}
+	if err = (&controller.StrimziSchemaReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "StrimziSchemaRegistry")
+		setupLog.Error(err, "unable to create controller", "controller", "StrimziSchema")
+		os.Exit(1)
+	}
+	if err = (&controller.StrimziSchemaSubjectConfigReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StrimziSchemaSubjectConfig")
 		os.Exit(1)
 	}
+	if err = (&controller.StrimziSchemaRegistryBackupReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		JobImage: jobImage,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StrimziSchemaRegistryBackup")
+		os.Exit(1)
+	}
+	if err = (&controller.StrimziSchemaExporterReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "StrimziSchemaExporter")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = webhookv1.SetupPodWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "Pod")
+			os.Exit(1)
+		}
+		if err = webhookv1alpha1.SetupStrimziSchemaWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "StrimziSchema")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+// runSnapshotCommand runs the backup or restore command against the snapshots of a directory.
+// The registry is reached with the files of --registry-dir, as mounted from the registry access
+// Secret of a StrimziSchemaRegistryBackup.
+func runSnapshotCommand(command string, args []string) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	registryDir := flags.String("registry-dir", "/etc/registry",
+		"The directory holding the registry url and the optional ca.crt, tls.crt, tls.key, username and password files.")
+	dir := flags.String("dir", "", "The directory of the snapshots.")
+	snapshot := flags.String("snapshot", "",
+		"The snapshot to write (defaults to the current time) or to restore (defaults to the latest one).")
+	keepLast := flags.Int("keep-last", 7, "The number of snapshots kept in the directory after a backup.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errors.New("--dir is required")
+	}
+	cfg, err := backup.ReadRegistryAccess(*registryDir)
+	if err != nil {
+		return err
+	}
+	rc, err := registryclient.New(cfg)
+	if err != nil {
+		return err
+	}
+	store := &backup.FileStore{Dir: *dir}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if command == "restore" {
+		name, err := backup.RestoreSnapshot(ctx, rc, store, *snapshot)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("restored snapshot %s\n", name)
+		return nil
+	}
+
+	name := *snapshot
+	if name == "" {
+		name = backup.SnapshotName(time.Now())
+	}
+	taken, err := backup.TakeSnapshot(ctx, rc, store, name, max(*keepLast, 1))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("took snapshot %s: %d subjects, %d schemas\n", name, len(taken.Subjects), taken.SchemaCount())
+	return nil
+}
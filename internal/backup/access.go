@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+// Keys of the registry access Secret mounted into the Jobs of volume backups.
+const (
+	accessURLKey        = "url"
+	accessCAKey         = "ca.crt"
+	accessClientCertKey = "tls.crt"
+	accessClientKeyKey  = "tls.key"
+	accessUsernameKey   = "username"
+	accessPasswordKey   = "password"
+)
+
+// RegistryAccessData returns the data of a Secret describing how to reach a registry, as read
+// back by ReadRegistryAccess from the directory the Secret is mounted in.
+func RegistryAccessData(cfg registryclient.Config) map[string][]byte {
+	data := map[string][]byte{accessURLKey: []byte(cfg.URL)}
+	for key, value := range map[string][]byte{
+		accessCAKey:         cfg.CACert,
+		accessClientCertKey: cfg.ClientCert,
+		accessClientKeyKey:  cfg.ClientKey,
+		accessUsernameKey:   []byte(cfg.Username),
+		accessPasswordKey:   []byte(cfg.Password),
+	} {
+		if len(value) > 0 {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// ReadRegistryAccess reads the registry access files written from RegistryAccessData in dir.
+func ReadRegistryAccess(dir string) (registryclient.Config, error) {
+	read := func(key string) ([]byte, error) {
+		data, err := os.ReadFile(filepath.Join(dir, key))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return data, err
+	}
+	values := map[string][]byte{}
+	for _, key := range []string{accessURLKey, accessCAKey, accessClientCertKey, accessClientKeyKey,
+		accessUsernameKey, accessPasswordKey} {
+		value, err := read(key)
+		if err != nil {
+			return registryclient.Config{}, err
+		}
+		values[key] = value
+	}
+	return registryclient.Config{
+		URL:        string(values[accessURLKey]),
+		CACert:     values[accessCAKey],
+		ClientCert: values[accessClientCertKey],
+		ClientKey:  values[accessClientKeyKey],
+		Username:   string(values[accessUsernameKey]),
+		Password:   string(values[accessPasswordKey]),
+	}, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+func TestRegistryAccess(t *testing.T) {
+	cfg := registryclient.Config{URL: "https://sr.default.svc:443", CACert: []byte("ca"), Username: "backup", Password: "secret"}
+	data := RegistryAccessData(cfg)
+	if _, ok := data[accessClientCertKey]; ok {
+		t.Errorf("empty settings must not be written, got %v", data)
+	}
+
+	dir := t.TempDir()
+	for key, value := range data {
+		if err := os.WriteFile(filepath.Join(dir, key), value, 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	read, err := ReadRegistryAccess(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if read.URL != cfg.URL || string(read.CACert) != "ca" || read.Username != "backup" || read.Password != "secret" ||
+		read.ClientCert != nil {
+		t.Errorf("unexpected registry access %+v", read)
+	}
+}
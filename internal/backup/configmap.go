@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelBackup and LabelSnapshot identify the ConfigMaps holding a snapshot.
+	LabelBackup   = "strimziregistryoperator.randsw.code/backup"
+	LabelSnapshot = "strimziregistryoperator.randsw.code/snapshot"
+	// annotationPart and annotationParts order the ConfigMaps of a snapshot split in parts.
+	annotationPart  = "strimziregistryoperator.randsw.code/part"
+	annotationParts = "strimziregistryoperator.randsw.code/parts"
+
+	// configMapDataKey is the binaryData key holding a part of a snapshot.
+	configMapDataKey = "snapshot" + snapshotExtension
+	// maxConfigMapPart keeps every ConfigMap below the 1MiB object size limit.
+	maxConfigMapPart = 900 * 1024
+)
+
+// ConfigMapStore keeps the snapshots of a backup in ConfigMaps named "<backup>-<snapshot>-<part>".
+// A snapshot larger than a ConfigMap is split into several parts. The ConfigMaps have no owner,
+// so they outlive the backup resource.
+type ConfigMapStore struct {
+	Client    client.Client
+	Namespace string
+	Backup    string
+}
+
+func (s *ConfigMapStore) parts(ctx context.Context, name string) ([]v1.ConfigMap, error) {
+	list := &v1.ConfigMapList{}
+	if err := s.Client.List(ctx, list, client.InNamespace(s.Namespace),
+		client.MatchingLabels{LabelBackup: s.Backup, LabelSnapshot: name}); err != nil {
+		return nil, err
+	}
+	parts := list.Items
+	sort.Slice(parts, func(i, j int) bool {
+		pi, _ := strconv.Atoi(parts[i].Annotations[annotationPart])
+		pj, _ := strconv.Atoi(parts[j].Annotations[annotationPart])
+		return pi < pj
+	})
+	return parts, nil
+}
+
+func (s *ConfigMapStore) Save(ctx context.Context, name string, data []byte) error {
+	if err := s.Delete(ctx, name); err != nil {
+		return err
+	}
+	count := (len(data) + maxConfigMapPart - 1) / maxConfigMapPart
+	for part := 0; part < count; part++ {
+		end := min((part+1)*maxConfigMapPart, len(data))
+		configMap := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s-%d", s.Backup, name, part),
+				Namespace: s.Namespace,
+				Labels:    map[string]string{LabelBackup: s.Backup, LabelSnapshot: name},
+				Annotations: map[string]string{
+					annotationPart:  strconv.Itoa(part),
+					annotationParts: strconv.Itoa(count),
+				},
+			},
+			BinaryData: map[string][]byte{configMapDataKey: data[part*maxConfigMapPart : end]},
+		}
+		if err := s.Client.Create(ctx, configMap); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reassembles the parts of a snapshot. A snapshot with missing parts is reported as not found.
+func (s *ConfigMapStore) Load(ctx context.Context, name string) ([]byte, error) {
+	parts, err := s.parts(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 || strconv.Itoa(len(parts)) != parts[0].Annotations[annotationParts] {
+		return nil, ErrNotFound
+	}
+	var data []byte
+	for _, part := range parts {
+		data = append(data, part.BinaryData[configMapDataKey]...)
+	}
+	return data, nil
+}
+
+func (s *ConfigMapStore) List(ctx context.Context) ([]string, error) {
+	list := &v1.ConfigMapList{}
+	if err := s.Client.List(ctx, list, client.InNamespace(s.Namespace), client.MatchingLabels{LabelBackup: s.Backup}); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, configMap := range list.Items {
+		if name := configMap.Labels[LabelSnapshot]; name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *ConfigMapStore) Delete(ctx context.Context, name string) error {
+	parts, err := s.parts(ctx, name)
+	if err != nil {
+		return err
+	}
+	for i := range parts {
+		if err := s.Client.Delete(ctx, &parts[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup exports the content of a registry into snapshots, stores them and replays them
+// into a registry with their original IDs.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+// FormatVersion is the version of the snapshot format written by Encode.
+const FormatVersion = 1
+
+// Snapshot is the content of a registry at a point in time.
+type Snapshot struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	// Compatibility and Mode are the global settings of the registry.
+	Compatibility string    `json:"compatibility,omitempty"`
+	Mode          string    `json:"mode,omitempty"`
+	Subjects      []Subject `json:"subjects"`
+}
+
+// Subject is a subject of a snapshot with its own settings and its versions. Deleted lists the
+// numbers of the soft-deleted ones.
+type Subject struct {
+	Name          string                          `json:"name"`
+	Compatibility string                          `json:"compatibility,omitempty"`
+	Normalize     *bool                           `json:"normalize,omitempty"`
+	Mode          string                          `json:"mode,omitempty"`
+	Versions      []registryclient.SubjectVersion `json:"versions"`
+	Deleted       []int32                         `json:"deleted,omitempty"`
+}
+
+// SchemaCount returns the number of subject versions in the snapshot.
+func (s *Snapshot) SchemaCount() int {
+	count := 0
+	for _, subject := range s.Subjects {
+		count += len(subject.Versions)
+	}
+	return count
+}
+
+// Export reads the global settings, the subjects with their settings and every version of the
+// registry. Soft-deleted versions are exported too, since their IDs may still be referenced by
+// serialized records, and flagged as such.
+func Export(ctx context.Context, rc *registryclient.Client) (*Snapshot, error) {
+	snapshot := &Snapshot{FormatVersion: FormatVersion, CreatedAt: time.Now().UTC()}
+	config, err := rc.GetConfig(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the global configuration: %w", err)
+	}
+	snapshot.Compatibility = config.Compatibility
+	if snapshot.Mode, err = rc.GetMode(ctx, ""); err != nil {
+		return nil, fmt.Errorf("failed to get the global mode: %w", err)
+	}
+
+	names, err := rc.ListSubjects(ctx, "", true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subjects: %w", err)
+	}
+	for _, name := range names {
+		subject, err := exportSubject(ctx, rc, name)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Subjects = append(snapshot.Subjects, *subject)
+	}
+	return snapshot, nil
+}
+
+// exportSubject reads the settings and versions of a subject, soft-deleted ones included.
+func exportSubject(ctx context.Context, rc *registryclient.Client, name string) (*Subject, error) {
+	subject := &Subject{Name: name}
+	config, err := rc.GetConfig(ctx, name)
+	switch {
+	case err == nil:
+		subject.Compatibility, subject.Normalize = config.Compatibility, config.Normalize
+	case !registryclient.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get the configuration of subject %s: %w", name, err)
+	}
+	mode, err := rc.GetMode(ctx, name)
+	switch {
+	case err == nil:
+		subject.Mode = mode
+	case !registryclient.IsNotFound(err):
+		return nil, fmt.Errorf("failed to get the mode of subject %s: %w", name, err)
+	}
+
+	versions, err := rc.ListVersions(ctx, name, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the versions of subject %s: %w", name, err)
+	}
+	live, err := rc.ListVersions(ctx, name, false)
+	if err != nil && !registryclient.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list the versions of subject %s: %w", name, err)
+	}
+	for _, number := range versions {
+		version, err := rc.GetVersion(ctx, name, strconv.Itoa(int(number)), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get version %d of subject %s: %w", number, name, err)
+		}
+		subject.Versions = append(subject.Versions, *version)
+		if !slices.Contains(live, number) {
+			subject.Deleted = append(subject.Deleted, number)
+		}
+	}
+	return subject, nil
+}
+
+// Restore replays snapshot into rc. The registry is switched to IMPORT mode, which it only
+// accepts while it holds no schemas, and the versions are registered in ID order with their
+// original IDs and version numbers so that references resolve and serialized records stay
+// readable. Versions soft-deleted in the snapshot are then soft-deleted again, and the subject
+// settings and the global settings are restored last. A failed restore leaves the registry in
+// IMPORT mode; running it again skips the version numbers its subjects already hold.
+func Restore(ctx context.Context, rc *registryclient.Client, snapshot *Snapshot) error {
+	mode, err := rc.GetMode(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to get the global mode: %w", err)
+	}
+	if mode != registryclient.ModeImport {
+		if err := rc.SetMode(ctx, "", registryclient.ModeImport); err != nil {
+			return fmt.Errorf("failed to switch the registry to IMPORT mode: %w", err)
+		}
+	}
+
+	var versions []registryclient.SubjectVersion
+	for _, subject := range snapshot.Subjects {
+		existing, err := rc.ListVersions(ctx, subject.Name, true)
+		if err != nil && !registryclient.IsNotFound(err) {
+			return fmt.Errorf("failed to list the versions of subject %s: %w", subject.Name, err)
+		}
+		for _, version := range subject.Versions {
+			if slices.Contains(existing, version.Version) {
+				continue
+			}
+			version.Subject = subject.Name
+			versions = append(versions, version)
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].ID < versions[j].ID })
+	for _, version := range versions {
+		if _, err := rc.ImportSchema(ctx, version.Subject, version); err != nil {
+			return fmt.Errorf("failed to import version %d of subject %s (ID %d): %w",
+				version.Version, version.Subject, version.ID, err)
+		}
+	}
+
+	for _, subject := range snapshot.Subjects {
+		for _, number := range subject.Deleted {
+			_, err := rc.DeleteVersion(ctx, subject.Name, strconv.Itoa(int(number)), false)
+			if err != nil && !registryclient.IsNotFound(err) {
+				return fmt.Errorf("failed to soft-delete version %d of subject %s: %w", number, subject.Name, err)
+			}
+		}
+	}
+	for _, subject := range snapshot.Subjects {
+		if subject.Compatibility != "" || subject.Normalize != nil {
+			config := registryclient.CompatibilityConfig{Compatibility: subject.Compatibility, Normalize: subject.Normalize}
+			if err := rc.SetConfig(ctx, subject.Name, config); err != nil {
+				return fmt.Errorf("failed to restore the configuration of subject %s: %w", subject.Name, err)
+			}
+		}
+		if subject.Mode != "" && subject.Mode != registryclient.ModeImport {
+			if err := rc.SetMode(ctx, subject.Name, subject.Mode); err != nil {
+				return fmt.Errorf("failed to restore the mode of subject %s: %w", subject.Name, err)
+			}
+		}
+	}
+	if snapshot.Compatibility != "" {
+		config := registryclient.CompatibilityConfig{Compatibility: snapshot.Compatibility}
+		if err := rc.SetConfig(ctx, "", config); err != nil {
+			return fmt.Errorf("failed to restore the global configuration: %w", err)
+		}
+	}
+	mode = snapshot.Mode
+	if mode == "" || mode == registryclient.ModeImport {
+		mode = registryclient.ModeReadWrite
+	}
+	if err := rc.SetMode(ctx, "", mode); err != nil {
+		return fmt.Errorf("failed to restore the global mode: %w", err)
+	}
+	return nil
+}
+
+// Encode serializes snapshot as gzipped JSON.
+func Encode(snapshot *Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode parses a snapshot written by Encode.
+func Decode(data []byte) (*Snapshot, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	payload, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(payload, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if snapshot.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("snapshot format %d is newer than the supported format %d", snapshot.FormatVersion, FormatVersion)
+	}
+	return snapshot, nil
+}
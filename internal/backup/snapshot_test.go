@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	"k8s.io/utils/ptr"
+)
+
+func newClient(t *testing.T, registry *testutil.FakeRegistry) *registryclient.Client {
+	t.Helper()
+	rc, err := registryclient.New(registryclient.Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rc
+}
+
+func TestExportRestore(t *testing.T) {
+	ctx := context.Background()
+	source := testutil.NewFakeRegistry()
+	defer source.Close()
+	rc := newClient(t, source)
+
+	for _, register := range []struct {
+		subject string
+		schema  registryclient.Schema
+	}{
+		{"orders-value", registryclient.Schema{Schema: `{"type":"string"}`}},
+		{"customers-value", registryclient.Schema{Schema: `{"type":"int"}`}},
+		{"orders-value", registryclient.Schema{Schema: `{"type":"long"}`}},
+		{"payments-value", registryclient.Schema{Schema: `syntax = "proto3";`, SchemaType: "PROTOBUF",
+			References: []registryclient.Reference{{Name: "orders", Subject: "orders-value", Version: 2}}}},
+	} {
+		if _, err := rc.RegisterSchema(ctx, register.subject, register.schema); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := rc.DeleteVersion(ctx, "orders-value", "1", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	source.SetCompatibility("", "FULL")
+	source.SetCompatibility("orders-value", "NONE")
+	source.SetMode("customers-value", "READONLY")
+	if err := rc.SetConfig(ctx, "payments-value", registryclient.CompatibilityConfig{Normalize: ptr.To(true)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot, err := Export(ctx, rc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshot.Subjects) != 3 || snapshot.SchemaCount() != 4 || snapshot.Compatibility != "FULL" {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if orders := snapshot.Subjects[1]; orders.Name != "orders-value" || !slices.Equal(orders.Deleted, []int32{1}) {
+		t.Errorf("expected the soft-deleted version to be flagged, got %+v", orders)
+	}
+
+	data, err := Encode(snapshot)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	target := testutil.NewFakeRegistry()
+	defer target.Close()
+	targetClient := newClient(t, target)
+
+	t.Run("snapshot is replayed with the original IDs", func(t *testing.T) {
+		if err := Restore(ctx, targetClient, decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		orders := target.Versions("orders-value")
+		if len(orders) != 2 || orders[0].ID != 1 || !orders[0].Deleted || orders[1].ID != 3 || orders[1].Version != 2 {
+			t.Errorf("expected version 1 to be restored soft-deleted, got %+v", orders)
+		}
+		payments := target.Versions("payments-value")
+		if len(payments) != 1 || payments[0].ID != 4 || len(payments[0].References) != 1 {
+			t.Errorf("unexpected payments-value versions %+v", payments)
+		}
+		if level, _ := target.Compatibility("orders-value"); level != "NONE" {
+			t.Errorf("expected the subject compatibility to be restored, got %q", level)
+		}
+		if mode, _ := target.Mode("customers-value"); mode != "READONLY" {
+			t.Errorf("expected the subject mode to be restored, got %q", mode)
+		}
+		if level, _ := target.Compatibility(""); level != "FULL" {
+			t.Errorf("expected the global compatibility to be restored, got %q", level)
+		}
+		if mode, _ := target.Mode(""); mode != "READWRITE" {
+			t.Errorf("expected the registry to leave IMPORT mode, got %q", mode)
+		}
+	})
+
+	t.Run("restore into a registry holding schemas is rejected", func(t *testing.T) {
+		if err := Restore(ctx, targetClient, decoded); !registryclient.IsClientError(err) {
+			t.Errorf("expected the registry to reject IMPORT mode, got %v", err)
+		}
+	})
+
+	t.Run("restore resumes in IMPORT mode", func(t *testing.T) {
+		target.SetMode("", "IMPORT")
+		if err := Restore(ctx, targetClient, decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if versions := target.Versions("orders-value"); len(versions) != 2 {
+			t.Errorf("expected imported versions to be skipped, got %+v", versions)
+		}
+	})
+
+	t.Run("versions already imported are not sent again", func(t *testing.T) {
+		partial := testutil.NewFakeRegistry()
+		defer partial.Close()
+		partial.SetMode("", "IMPORT")
+		partialClient := newClient(t, partial)
+		// The registry stores schemas in canonical form, which may not match the snapshot text.
+		imported := registryclient.SubjectVersion{ID: 3, Version: 2, Schema: `{"type": "long"}`}
+		if _, err := partialClient.ImportSchema(ctx, "orders-value", imported); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := Restore(ctx, partialClient, decoded); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if versions := partial.Versions("orders-value"); len(versions) != 2 || versions[0].Schema != imported.Schema {
+			t.Errorf("expected version 2 to be skipped, got %+v", versions)
+		}
+		if versions := partial.Versions("payments-value"); len(versions) != 1 {
+			t.Errorf("expected the other versions to be imported, got %+v", versions)
+		}
+	})
+}
+
+func TestDecodeRejectsNewerFormat(t *testing.T) {
+	data, err := Encode(&Snapshot{FormatVersion: FormatVersion + 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Decode(data); err == nil {
+		t.Error("expected a newer format to be rejected")
+	}
+	if _, err := Decode([]byte("not gzip")); err == nil {
+		t.Error("expected invalid data to be rejected")
+	}
+}
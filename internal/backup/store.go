@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+// ErrNotFound is returned by Store.Load for an unknown snapshot.
+var ErrNotFound = errors.New("snapshot not found")
+
+// snapshotExtension is the file extension of encoded snapshots.
+const snapshotExtension = ".json.gz"
+
+// Store keeps encoded snapshots by name.
+type Store interface {
+	// Save stores data as snapshot name, replacing it if it exists.
+	Save(ctx context.Context, name string, data []byte) error
+	// Load returns snapshot name or ErrNotFound.
+	Load(ctx context.Context, name string) ([]byte, error)
+	// List returns the names of the snapshots, oldest first.
+	List(ctx context.Context) ([]string, error)
+	// Delete removes snapshot name.
+	Delete(ctx context.Context, name string) error
+}
+
+// SnapshotName returns the name of a snapshot taken at t. Names sort in chronological order.
+func SnapshotName(t time.Time) string {
+	return t.UTC().Format("20060102-150405")
+}
+
+// Latest returns the name of the newest snapshot of store or ErrNotFound.
+func Latest(ctx context.Context, store Store) (string, error) {
+	names, err := store.List(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", ErrNotFound
+	}
+	return names[len(names)-1], nil
+}
+
+// Prune deletes the oldest snapshots of store to keep the newest keep ones and returns the
+// names of the deleted snapshots.
+func Prune(ctx context.Context, store Store, keep int) ([]string, error) {
+	names, err := store.List(ctx)
+	if err != nil || len(names) <= keep {
+		return nil, err
+	}
+	deleted := names[:len(names)-keep]
+	for _, name := range deleted {
+		if err := store.Delete(ctx, name); err != nil {
+			return nil, fmt.Errorf("failed to delete snapshot %s: %w", name, err)
+		}
+	}
+	return deleted, nil
+}
+
+// TakeSnapshot exports the registry into store as snapshot name, then prunes store to keep the
+// newest keep snapshots.
+func TakeSnapshot(ctx context.Context, rc *registryclient.Client, store Store, name string, keep int) (*Snapshot, error) {
+	snapshot, err := Export(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	data, err := Encode(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Save(ctx, name, data); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot %s: %w", name, err)
+	}
+	if _, err := Prune(ctx, store, keep); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot restores snapshot name of store into the registry, the latest one when name
+// is empty, and returns the name of the restored snapshot.
+func RestoreSnapshot(ctx context.Context, rc *registryclient.Client, store Store, name string) (string, error) {
+	if name == "" {
+		latest, err := Latest(ctx, store)
+		if err != nil {
+			return "", err
+		}
+		name = latest
+	}
+	data, err := store.Load(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to load snapshot %s: %w", name, err)
+	}
+	snapshot, err := Decode(data)
+	if err != nil {
+		return "", err
+	}
+	return name, Restore(ctx, rc, snapshot)
+}
+
+// FileStore keeps snapshots as "<name>.json.gz" files of a directory.
+type FileStore struct {
+	Dir string
+}
+
+// path returns the file of snapshot name. Names that would resolve outside of Dir, or to the
+// hidden temporary files of Save, are rejected.
+func (s *FileStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return filepath.Join(s.Dir, name+snapshotExtension), nil
+}
+
+// Save writes the snapshot to a temporary file renamed into place, so that a partial write
+// never replaces a complete snapshot.
+func (s *FileStore) Save(_ context.Context, name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *FileStore) Load(_ context.Context, name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *FileStore) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), snapshotExtension)
+		if ok && entry.Type().IsRegular() && !strings.HasPrefix(name, ".") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *FileStore) Delete(_ context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testStore saves three snapshots to store, then checks loading, listing and pruning.
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	large := bytes.Repeat([]byte("x"), 2*maxConfigMapPart+10)
+	for name, data := range map[string][]byte{
+		"20261019-010000": []byte("first"),
+		"20261019-020000": large,
+		"20261019-030000": []byte("third"),
+	} {
+		if err := store.Save(ctx, name, data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	names, err := store.List(ctx)
+	if err != nil || !slices.Equal(names, []string{"20261019-010000", "20261019-020000", "20261019-030000"}) {
+		t.Fatalf("unexpected snapshots %v (err: %v)", names, err)
+	}
+	data, err := store.Load(ctx, "20261019-020000")
+	if err != nil || !bytes.Equal(data, large) {
+		t.Errorf("unexpected snapshot of %d bytes (err: %v)", len(data), err)
+	}
+	if _, err := store.Load(ctx, "20261019-040000"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if latest, err := Latest(ctx, store); err != nil || latest != "20261019-030000" {
+		t.Errorf("unexpected latest snapshot %q (err: %v)", latest, err)
+	}
+
+	deleted, err := Prune(ctx, store, 1)
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("unexpected pruned snapshots %v (err: %v)", deleted, err)
+	}
+	names, err = store.List(ctx)
+	if err != nil || !slices.Equal(names, []string{"20261019-030000"}) {
+		t.Errorf("unexpected snapshots after pruning %v (err: %v)", names, err)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, &FileStore{Dir: t.TempDir() + "/backups"})
+
+	t.Run("names outside of the directory are rejected", func(t *testing.T) {
+		dir := t.TempDir()
+		outside := filepath.Join(dir, "outside"+snapshotExtension)
+		if err := os.WriteFile(outside, []byte("secret"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		store := &FileStore{Dir: filepath.Join(dir, "backups")}
+		for _, name := range []string{"../outside", "a/../../outside", "..", ".hidden", ""} {
+			if _, err := store.Load(context.Background(), name); err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("expected %q to be rejected, got %v", name, err)
+			}
+			if err := store.Save(context.Background(), name, []byte("x")); err == nil {
+				t.Errorf("expected %q to be rejected on save", name)
+			}
+			if err := store.Delete(context.Background(), name); err == nil {
+				t.Errorf("expected %q to be rejected on delete", name)
+			}
+		}
+		if _, err := os.Stat(outside); err != nil {
+			t.Errorf("expected the file outside of the directory to be kept: %v", err)
+		}
+	})
+}
+
+func TestConfigMapStore(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	testStore(t, &ConfigMapStore{Client: c, Namespace: "default", Backup: "nightly"})
+
+	other := &ConfigMapStore{Client: c, Namespace: "default", Backup: "hourly"}
+	if names, err := other.List(context.Background()); err != nil || len(names) != 0 {
+		t.Errorf("expected the snapshots of another backup to be ignored, got %v (err: %v)", names, err)
+	}
+}
+
+// fakeObjectStore is a minimal S3 API keeping objects in memory.
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access/") ||
+		r.Header.Get("x-amz-content-sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != "backups" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		// Pages of two keys exercise the continuation token.
+		var keys []string
+		for name := range f.objects {
+			if strings.HasPrefix(name, r.URL.Query().Get("prefix")) && name > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, name)
+			}
+		}
+		sort.Strings(keys)
+		type content struct {
+			Key string `xml:"Key"`
+		}
+		result := struct {
+			XMLName               xml.Name  `xml:"ListBucketResult"`
+			Contents              []content `xml:"Contents"`
+			IsTruncated           bool      `xml:"IsTruncated"`
+			NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		}{}
+		for i, name := range keys {
+			if i == 2 {
+				result.IsTruncated, result.NextContinuationToken = true, keys[1]
+				break
+			}
+			result.Contents = append(result.Contents, content{Key: name})
+		}
+		_ = xml.NewEncoder(w).Encode(result)
+	case r.Method == http.MethodPut:
+		f.objects[key], _ = io.ReadAll(r.Body)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestObjectStore(t *testing.T) {
+	fakeStore := &fakeObjectStore{objects: map[string][]byte{"other/unrelated.json.gz": []byte("x")}}
+	server := httptest.NewServer(fakeStore)
+	defer server.Close()
+
+	testStore(t, &ObjectStore{Endpoint: server.URL, Bucket: "backups", Prefix: "/registry/prod/",
+		AccessKeyID: "access", SecretAccessKey: "secret"})
+	if _, ok := fakeStore.objects["registry/prod/20261019-030000.json.gz"]; !ok {
+		t.Errorf("expected the objects to be stored under the prefix, got %v", fakeStore.objects)
+	}
+
+	wrongBucket := &ObjectStore{Endpoint: server.URL, Bucket: "missing", AccessKeyID: "access"}
+	if err := wrongBucket.Save(context.Background(), "20261019-010000", []byte("x")); err == nil {
+		t.Error("expected an error for a missing bucket")
+	}
+}
+
+func TestObjectStoreSignature(t *testing.T) {
+	store := &ObjectStore{Endpoint: "https://minio.storage:9000", Bucket: "backups", Region: "eu-west-1",
+		AccessKeyID: "access", SecretAccessKey: "secret"}
+	req, _ := http.NewRequest(http.MethodGet, "https://minio.storage:9000/backups/a%20b.json.gz?list-type=2", nil)
+	store.sign(req, nil, time.Date(2026, 10, 19, 1, 2, 3, 0, time.UTC))
+
+	if req.Header.Get("x-amz-date") != "20261019T010203Z" {
+		t.Errorf("unexpected date header %q", req.Header.Get("x-amz-date"))
+	}
+	// The SHA-256 of an empty payload.
+	if req.Header.Get("x-amz-content-sha256") != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("unexpected payload hash %q", req.Header.Get("x-amz-content-sha256"))
+	}
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=access/20261019/eu-west-1/s3/aws4_request, "+
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		t.Errorf("unexpected authorization header %q", authorization)
+	}
+
+	again, _ := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	store.sign(again, nil, time.Date(2026, 10, 19, 1, 2, 3, 0, time.UTC))
+	if again.Header.Get("Authorization") != authorization {
+		t.Error("expected the signature to be deterministic")
+	}
+	store.SecretAccessKey = "other"
+	store.sign(again, nil, time.Date(2026, 10, 19, 1, 2, 3, 0, time.UTC))
+	if again.Header.Get("Authorization") == authorization {
+		t.Error("expected the signature to depend on the secret key")
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	query := canonicalQuery(map[string][]string{"prefix": {"registry/prod a"}, "list-type": {"2"}})
+	if query != "list-type=2&prefix=registry%2Fprod%20a" {
+		t.Errorf("unexpected canonical query %q", query)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+// ManifestFile is the file of an export tree listing its schemas.
+const ManifestFile = "manifest.json"
+
+// Manifest lists the schemas of an export tree with their IDs.
+type Manifest struct {
+	Compatibility string            `json:"compatibility,omitempty"`
+	Mode          string            `json:"mode,omitempty"`
+	Subjects      []ManifestSubject `json:"subjects"`
+}
+
+// ManifestSubject is a subject of a Manifest.
+type ManifestSubject struct {
+	Subject       string            `json:"subject"`
+	Compatibility string            `json:"compatibility,omitempty"`
+	Mode          string            `json:"mode,omitempty"`
+	Versions      []ManifestVersion `json:"versions"`
+}
+
+// ManifestVersion is a subject version of a Manifest and the path of its schema file.
+type ManifestVersion struct {
+	Version    int32                      `json:"version"`
+	ID         int32                      `json:"id"`
+	SchemaType string                     `json:"schemaType"`
+	Path       string                     `json:"path"`
+	References []registryclient.Reference `json:"references,omitempty"`
+}
+
+// SchemaPath returns the path of a schema in an export tree: "<subject>/<version>.<extension>",
+// the subject being escaped so that it is a single, safe path element.
+func SchemaPath(subject string, version int32, schemaType string) string {
+	dir := url.PathEscape(subject)
+	if strings.HasPrefix(dir, ".") {
+		dir = "%2E" + dir[1:]
+	}
+	extension := "avsc"
+	switch schemaType {
+	case "PROTOBUF":
+		extension = "proto"
+	case "JSON":
+		extension = "json"
+	}
+	return dir + "/" + strconv.Itoa(int(version)) + "." + extension
+}
+
+// Tree renders snapshot as an export tree: one file per subject version plus the manifest,
+// keyed by path. The tree only depends on the registry content, so an unchanged registry gives
+// identical files and a diff of two trees shows the registered, deleted and changed schemas.
+func Tree(snapshot *Snapshot) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	manifest := Manifest{Compatibility: snapshot.Compatibility, Mode: snapshot.Mode, Subjects: []ManifestSubject{}}
+	subjects := append([]Subject(nil), snapshot.Subjects...)
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].Name < subjects[j].Name })
+	for _, subject := range subjects {
+		entry := ManifestSubject{Subject: subject.Name, Compatibility: subject.Compatibility, Mode: subject.Mode}
+		versions := append([]registryclient.SubjectVersion(nil), subject.Versions...)
+		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+		for _, version := range versions {
+			schemaType := version.SchemaType
+			if schemaType == "" {
+				schemaType = "AVRO"
+			}
+			schemaPath := SchemaPath(subject.Name, version.Version, schemaType)
+			files[schemaPath] = formatSchema(version.Schema, schemaType)
+			entry.Versions = append(entry.Versions, ManifestVersion{
+				Version: version.Version, ID: version.ID, SchemaType: schemaType, Path: schemaPath,
+				References: version.References,
+			})
+		}
+		manifest.Subjects = append(manifest.Subjects, entry)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files[ManifestFile] = append(data, '\n')
+	return files, nil
+}
+
+// formatSchema indents Avro and JSON schemas for line-based diffs and ends every schema with a newline.
+func formatSchema(schema, schemaType string) []byte {
+	if schemaType != "PROTOBUF" {
+		var buf bytes.Buffer
+		if json.Indent(&buf, []byte(schema), "", "  ") == nil {
+			return append(buf.Bytes(), '\n')
+		}
+	}
+	if !strings.HasSuffix(schema, "\n") {
+		schema += "\n"
+	}
+	return []byte(schema)
+}
+
+// manifestPaths returns the schema paths listed by a manifest.
+func manifestPaths(data []byte) ([]string, error) {
+	manifest := Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	var paths []string
+	for _, subject := range manifest.Subjects {
+		for _, version := range subject.Versions {
+			if !filepath.IsLocal(version.Path) {
+				return nil, fmt.Errorf("invalid manifest: path %q is outside of the tree", version.Path)
+			}
+			paths = append(paths, version.Path)
+		}
+	}
+	return paths, nil
+}
+
+// WriteTree writes files under dir. The schema files listed by the manifest already in dir and
+// missing from files are removed, so that dir always mirrors the registry.
+func WriteTree(dir string, files map[string][]byte) error {
+	if previous, err := os.ReadFile(filepath.Join(dir, ManifestFile)); err == nil {
+		paths, err := manifestPaths(previous)
+		if err != nil {
+			return err
+		}
+		for _, stale := range paths {
+			if _, ok := files[stale]; ok {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, stale)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			// Remove the subject directory once empty.
+			_ = os.Remove(filepath.Join(dir, path.Dir(stale)))
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("path %q is outside of the tree", name)
+		}
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, files[name], 0o640); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConfigMapKey returns the ConfigMap key holding the file at path: the characters not allowed
+// in keys, "/" included, are written as "_XX" with their hexadecimal code, "_" included.
+func ConfigMapKey(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// TreeConfigMapData returns the ConfigMap data holding the export tree files.
+func TreeConfigMapData(files map[string][]byte) map[string]string {
+	data := make(map[string]string, len(files))
+	for name, content := range files {
+		data[ConfigMapKey(name)] = string(content)
+	}
+	return data
+}
+
+// TreeFromConfigMapData returns the export tree held by ConfigMap data, as listed by its manifest.
+func TreeFromConfigMapData(data map[string]string) (map[string][]byte, error) {
+	manifest, ok := data[ConfigMapKey(ManifestFile)]
+	if !ok {
+		return nil, fmt.Errorf("no %s found", ManifestFile)
+	}
+	paths, err := manifestPaths([]byte(manifest))
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{ManifestFile: []byte(manifest)}
+	for _, schemaPath := range paths {
+		content, ok := data[ConfigMapKey(schemaPath)]
+		if !ok {
+			return nil, fmt.Errorf("schema file %s listed by the manifest is missing", schemaPath)
+		}
+		files[schemaPath] = []byte(content)
+	}
+	return files, nil
+}
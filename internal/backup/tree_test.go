@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+)
+
+func newTreeSnapshot() *Snapshot {
+	return &Snapshot{
+		FormatVersion: FormatVersion,
+		Compatibility: "BACKWARD",
+		Subjects: []Subject{
+			{Name: "orders-value", Compatibility: "FULL", Versions: []registryclient.SubjectVersion{
+				{Subject: "orders-value", ID: 3, Version: 2, Schema: `{"type":"long"}`},
+				{Subject: "orders-value", ID: 1, Version: 1, Schema: `{"type":"string"}`},
+			}},
+			{Name: ":.team-a:payments/value", Versions: []registryclient.SubjectVersion{
+				{ID: 2, Version: 1, Schema: `syntax = "proto3";`, SchemaType: "PROTOBUF"},
+			}},
+			{Name: "..", Versions: []registryclient.SubjectVersion{
+				{ID: 4, Version: 1, Schema: `{"type":"object"}`, SchemaType: "JSON"},
+			}},
+		},
+	}
+}
+
+func TestTree(t *testing.T) {
+	files, err := Tree(newTreeSnapshot())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("schemas are laid out per subject and version", func(t *testing.T) {
+		for path, content := range map[string]string{
+			"orders-value/1.avsc":               "{\n  \"type\": \"string\"\n}\n",
+			"orders-value/2.avsc":               "{\n  \"type\": \"long\"\n}\n",
+			":.team-a:payments%2Fvalue/1.proto": "syntax = \"proto3\";\n",
+			"%2E./1.json":                       "{\n  \"type\": \"object\"\n}\n",
+		} {
+			if string(files[path]) != content {
+				t.Errorf("unexpected %s: %q", path, files[path])
+			}
+		}
+		if len(files) != 5 {
+			t.Errorf("expected 4 schemas and the manifest, got %d files", len(files))
+		}
+	})
+
+	t.Run("tree is deterministic", func(t *testing.T) {
+		snapshot := newTreeSnapshot()
+		snapshot.Subjects[0], snapshot.Subjects[2] = snapshot.Subjects[2], snapshot.Subjects[0]
+		again, err := Tree(snapshot)
+		if err != nil || !reflect.DeepEqual(again, files) {
+			t.Errorf("expected the same tree regardless of the order of the snapshot (err: %v)", err)
+		}
+	})
+
+	t.Run("tree round-trips through ConfigMap data", func(t *testing.T) {
+		data := TreeConfigMapData(files)
+		if _, ok := data["orders-value_2F1.avsc"]; !ok {
+			t.Errorf("unexpected ConfigMap keys %v", data)
+		}
+		read, err := TreeFromConfigMapData(data)
+		if err != nil || !reflect.DeepEqual(read, files) {
+			t.Errorf("unexpected tree %v (err: %v)", read, err)
+		}
+		delete(data, "orders-value_2F2.avsc")
+		if _, err := TreeFromConfigMapData(data); err == nil {
+			t.Error("expected a missing schema file to be reported")
+		}
+	})
+
+	t.Run("written tree drops deleted schemas", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("kept"), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := WriteTree(dir, files); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		snapshot := newTreeSnapshot()
+		snapshot.Subjects = snapshot.Subjects[:1]
+		smaller, _ := Tree(snapshot)
+		if err := WriteTree(dir, smaller); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "%2E.")); !os.IsNotExist(err) {
+			t.Errorf("expected the deleted subject to be removed, got %v", err)
+		}
+		for _, kept := range []string{"README.md", "orders-value/2.avsc", ManifestFile} {
+			if _, err := os.Stat(filepath.Join(dir, kept)); err != nil {
+				t.Errorf("expected %s to be kept: %v", kept, err)
+			}
+		}
+	})
+}
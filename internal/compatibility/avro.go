@@ -0,0 +1,370 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compatibility checks schemas against the previous versions of their subject without a
+// registry, following the compatibility levels of Schema Registry. Only Avro is supported.
+package compatibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Version is a previous version of a subject.
+type Version struct {
+	Version int32
+	Schema  string
+}
+
+// Avro checks an Avro schema against the versions of its subject (oldest first) for a
+// compatibility level and returns the reasons it is incompatible, naming the fields involved.
+func Avro(level, schema string, versions []Version) ([]string, error) {
+	level = strings.ToUpper(level)
+	if level == "" {
+		level = "BACKWARD"
+	}
+	if level == "NONE" || len(versions) == 0 {
+		return nil, nil
+	}
+	backward := strings.HasPrefix(level, "BACKWARD") || strings.HasPrefix(level, "FULL")
+	forward := strings.HasPrefix(level, "FORWARD") || strings.HasPrefix(level, "FULL")
+	if !backward && !forward {
+		return nil, fmt.Errorf("unknown compatibility level %q", level)
+	}
+	if !strings.HasSuffix(level, "_TRANSITIVE") {
+		versions = versions[len(versions)-1:]
+	}
+
+	current, err := parseAvro(schema)
+	if err != nil {
+		return nil, err
+	}
+	var messages []string
+	for i := len(versions) - 1; i >= 0; i-- {
+		previous, err := parseAvro(versions[i].Schema)
+		if err != nil {
+			return nil, fmt.Errorf("version %d: %w", versions[i].Version, err)
+		}
+		if backward {
+			for _, problem := range newAvroChecker().check(current, previous, "") {
+				messages = append(messages, fmt.Sprintf("the new schema cannot read version %d (%s): %s",
+					versions[i].Version, level, problem))
+			}
+		}
+		if forward {
+			for _, problem := range newAvroChecker().check(previous, current, "") {
+				messages = append(messages, fmt.Sprintf("version %d cannot read the new schema (%s): %s",
+					versions[i].Version, level, problem))
+			}
+		}
+	}
+	return messages, nil
+}
+
+// avroSchema is a parsed Avro schema. Named types referenced by name share the same node.
+type avroSchema struct {
+	// typ is a primitive type, record, enum, array, map, fixed, union, or ref for a named type
+	// defined outside of the schema (a schema reference).
+	typ         string
+	name        string
+	aliases     []string
+	fields      []avroField
+	symbols     []string
+	enumDefault bool
+	items       *avroSchema
+	size        int
+	branches    []*avroSchema
+}
+
+type avroField struct {
+	name       string
+	aliases    []string
+	typ        *avroSchema
+	hasDefault bool
+}
+
+var avroPrimitives = []string{"null", "boolean", "int", "long", "float", "double", "bytes", "string"}
+
+func parseAvro(schema string) (*avroSchema, error) {
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	p := &avroParser{names: map[string]*avroSchema{}}
+	s := p.parse(root, "")
+	if p.err != nil {
+		return nil, p.err
+	}
+	return s, nil
+}
+
+type avroParser struct {
+	names map[string]*avroSchema
+	err   error
+}
+
+func (p *avroParser) parse(schema any, namespace string) *avroSchema {
+	switch s := schema.(type) {
+	case string:
+		if slices.Contains(avroPrimitives, s) {
+			return &avroSchema{typ: s}
+		}
+		name := avroQualify(s, namespace)
+		if named, ok := p.names[name]; ok {
+			return named
+		}
+		if named, ok := p.names[s]; ok {
+			return named
+		}
+		return &avroSchema{typ: "ref", name: name}
+	case []any:
+		union := &avroSchema{typ: "union"}
+		for _, branch := range s {
+			union.branches = append(union.branches, p.parse(branch, namespace))
+		}
+		return union
+	case map[string]any:
+		typ, _ := s["type"].(string)
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			return p.parseNamed(s, typ, namespace)
+		case "array":
+			return &avroSchema{typ: "array", items: p.parse(s["items"], namespace)}
+		case "map":
+			return &avroSchema{typ: "map", items: p.parse(s["values"], namespace)}
+		}
+		return p.parse(s["type"], namespace)
+	}
+	p.err = fmt.Errorf("invalid Avro schema: unexpected %v", schema)
+	return &avroSchema{typ: "null"}
+}
+
+func (p *avroParser) parseNamed(s map[string]any, typ, namespace string) *avroSchema {
+	name, _ := s["name"].(string)
+	if ns, ok := s["namespace"].(string); ok && ns != "" {
+		namespace = ns
+	}
+	named := &avroSchema{typ: typ, name: avroQualify(name, namespace)}
+	if typ == "error" {
+		named.typ = "record"
+	}
+	if i := strings.LastIndex(named.name, "."); i >= 0 {
+		namespace = named.name[:i]
+	}
+	named.aliases = avroAliases(s, namespace)
+	p.names[named.name] = named
+
+	switch named.typ {
+	case "record":
+		fields, _ := s["fields"].([]any)
+		for _, f := range fields {
+			field, ok := f.(map[string]any)
+			if !ok {
+				continue
+			}
+			fieldName, _ := field["name"].(string)
+			_, hasDefault := field["default"]
+			named.fields = append(named.fields, avroField{
+				name:       fieldName,
+				aliases:    avroAliases(field, ""),
+				typ:        p.parse(field["type"], namespace),
+				hasDefault: hasDefault,
+			})
+		}
+	case "enum":
+		symbols, _ := s["symbols"].([]any)
+		for _, symbol := range symbols {
+			if symbol, ok := symbol.(string); ok {
+				named.symbols = append(named.symbols, symbol)
+			}
+		}
+		_, named.enumDefault = s["default"]
+	case "fixed":
+		size, _ := s["size"].(float64)
+		named.size = int(size)
+	}
+	return named
+}
+
+func avroAliases(s map[string]any, namespace string) []string {
+	aliases, _ := s["aliases"].([]any)
+	var names []string
+	for _, alias := range aliases {
+		if alias, ok := alias.(string); ok {
+			names = append(names, avroQualify(alias, namespace))
+		}
+	}
+	return names
+}
+
+func avroQualify(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// avroPromotions lists the writer types each reader type can read.
+var avroPromotions = map[string][]string{
+	"long":   {"int"},
+	"float":  {"int", "long"},
+	"double": {"int", "long", "float"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// avroChecker applies the Avro schema resolution rules to a reader and a writer schema.
+type avroChecker struct {
+	seen map[[2]*avroSchema]bool
+}
+
+func newAvroChecker() *avroChecker {
+	return &avroChecker{seen: map[[2]*avroSchema]bool{}}
+}
+
+// check returns the reasons data written with writer cannot be read with reader; path is the
+// dotted path of the field being checked.
+func (c *avroChecker) check(reader, writer *avroSchema, path string) []string {
+	key := [2]*avroSchema{reader, writer}
+	if c.seen[key] {
+		return nil
+	}
+	c.seen[key] = true
+	defer delete(c.seen, key)
+
+	if writer.typ == "union" {
+		var problems []string
+		for _, branch := range writer.branches {
+			problems = append(problems, c.check(reader, branch, path)...)
+		}
+		return problems
+	}
+	if reader.typ == "union" {
+		var closest []string
+		for _, branch := range reader.branches {
+			problems := c.check(branch, writer, path)
+			if len(problems) == 0 {
+				return nil
+			}
+			if branch.typ == writer.typ && closest == nil {
+				closest = problems
+			}
+		}
+		if closest != nil {
+			return closest
+		}
+		return []string{fmt.Sprintf("%s: the union has no branch reading %s", avroWhere(path), avroTypeName(writer))}
+	}
+
+	if reader.typ == "ref" || writer.typ == "ref" {
+		if reader.name == writer.name {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: type %s cannot be read as %s", avroWhere(path), avroTypeName(writer), avroTypeName(reader))}
+	}
+	if reader.typ != writer.typ {
+		if slices.Contains(avroPromotions[reader.typ], writer.typ) {
+			return nil
+		}
+		return []string{fmt.Sprintf("%s: type %s cannot be read as %s", avroWhere(path), avroTypeName(writer), avroTypeName(reader))}
+	}
+
+	switch reader.typ {
+	case "record", "enum", "fixed":
+		if !avroNamesMatch(reader, writer) {
+			return []string{fmt.Sprintf("%s: %s %s cannot be read as %s", avroWhere(path), reader.typ, writer.name, reader.name)}
+		}
+	}
+	switch reader.typ {
+	case "record":
+		return c.checkRecord(reader, writer, path)
+	case "enum":
+		var missing []string
+		for _, symbol := range writer.symbols {
+			if !slices.Contains(reader.symbols, symbol) {
+				missing = append(missing, symbol)
+			}
+		}
+		if len(missing) > 0 && !reader.enumDefault {
+			return []string{fmt.Sprintf("%s: enum %s has no symbol %s and no default", avroWhere(path), reader.name,
+				strings.Join(missing, ", "))}
+		}
+	case "fixed":
+		if reader.size != writer.size {
+			return []string{fmt.Sprintf("%s: fixed %s changes size from %d to %d", avroWhere(path), reader.name,
+				writer.size, reader.size)}
+		}
+	case "array":
+		return c.check(reader.items, writer.items, path+"[]")
+	case "map":
+		return c.check(reader.items, writer.items, path+"{}")
+	}
+	return nil
+}
+
+func (c *avroChecker) checkRecord(reader, writer *avroSchema, path string) []string {
+	var problems []string
+	for _, field := range reader.fields {
+		fieldPath := field.name
+		if path != "" {
+			fieldPath = path + "." + field.name
+		}
+		written := avroWriterField(writer, field)
+		switch {
+		case written != nil:
+			problems = append(problems, c.check(field.typ, written.typ, fieldPath)...)
+		case !field.hasDefault:
+			problems = append(problems, fmt.Sprintf("%s: the reader field has no default and the writer does not write it",
+				avroWhere(fieldPath)))
+		}
+	}
+	return problems
+}
+
+// avroWriterField returns the writer field read by a reader field, matched by name or alias.
+func avroWriterField(writer *avroSchema, field avroField) *avroField {
+	for i := range writer.fields {
+		if writer.fields[i].name == field.name || slices.Contains(field.aliases, writer.fields[i].name) {
+			return &writer.fields[i]
+		}
+	}
+	return nil
+}
+
+// avroNamesMatch compares named types by unqualified name, or by the aliases of the reader.
+func avroNamesMatch(reader, writer *avroSchema) bool {
+	return avroShortName(reader.name) == avroShortName(writer.name) || slices.Contains(reader.aliases, writer.name)
+}
+
+func avroShortName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+func avroTypeName(s *avroSchema) string {
+	if s.name != "" {
+		return s.name
+	}
+	return s.typ
+}
+
+func avroWhere(path string) string {
+	if path == "" {
+		return "the schema"
+	}
+	return fmt.Sprintf("field %q", path)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compatibility
+
+import (
+	"reflect"
+	"testing"
+)
+
+const orderV1 = `{"type":"record","name":"Order","namespace":"com.example","fields":[
+	{"name":"id","type":"int"},
+	{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID"]}},
+	{"name":"lines","type":{"type":"array","items":{"type":"record","name":"Line","fields":[
+		{"name":"sku","type":"string"}]}}}]}`
+
+func TestAvro(t *testing.T) {
+	v1 := []Version{{Version: 1, Schema: orderV1}}
+	for _, tc := range []struct {
+		name     string
+		level    string
+		schema   string
+		versions []Version
+		expected []string
+	}{
+		{
+			name:  "optional field added",
+			level: "FULL",
+			schema: `{"type":"record","name":"Order","namespace":"com.example","fields":[
+				{"name":"id","type":"long"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW","PAID"]}},
+				{"name":"lines","type":{"type":"array","items":{"type":"record","name":"Line","fields":[
+					{"name":"sku","type":"string"}]}}},
+				{"name":"note","type":["null","string"],"default":null}]}`,
+			versions: v1,
+			expected: []string{`version 1 cannot read the new schema (FULL): field "id": type long cannot be read as int`},
+		},
+		{
+			name:  "required field added",
+			level: "BACKWARD",
+			schema: `{"type":"record","name":"Order","namespace":"com.example","fields":[
+				{"name":"id","type":"int"},{"name":"customer","type":"string"}]}`,
+			versions: v1,
+			expected: []string{`the new schema cannot read version 1 (BACKWARD): field "customer": the reader field has no default and the writer does not write it`},
+		},
+		{
+			name:  "nested field type and enum symbol removed",
+			level: "backward",
+			schema: `{"type":"record","name":"Order","namespace":"com.example","fields":[
+				{"name":"id","type":"int"},
+				{"name":"status","type":{"type":"enum","name":"Status","symbols":["NEW"]}},
+				{"name":"lines","type":{"type":"array","items":{"type":"record","name":"Line","fields":[
+					{"name":"sku","type":"int"}]}}}]}`,
+			versions: v1,
+			expected: []string{
+				`the new schema cannot read version 1 (BACKWARD): field "status": enum com.example.Status has no symbol PAID and no default`,
+				`the new schema cannot read version 1 (BACKWARD): field "lines[].sku": type string cannot be read as int`,
+			},
+		},
+		{
+			name:     "renamed field with alias and removed field with default",
+			level:    "FORWARD",
+			schema:   `{"type":"record","name":"Order","namespace":"com.example","fields":[{"name":"orderId","type":"int","aliases":["id"]}]}`,
+			versions: []Version{{Version: 1, Schema: `{"type":"record","name":"Order","fields":[{"name":"orderId","type":"int"},{"name":"note","type":"string","default":""}]}`}},
+		},
+		{
+			name:     "non-transitive levels only check the latest version",
+			level:    "BACKWARD",
+			schema:   `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"},{"name":"total","type":"double"}]}`,
+			versions: []Version{{Version: 1, Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`}, {Version: 2, Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"},{"name":"total","type":"float"}]}`}},
+		},
+		{
+			name:     "transitive levels check every version",
+			level:    "BACKWARD_TRANSITIVE",
+			schema:   `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"},{"name":"total","type":"double"}]}`,
+			versions: []Version{{Version: 1, Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"}]}`}, {Version: 2, Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"int"},{"name":"total","type":"float"}]}`}},
+			expected: []string{`the new schema cannot read version 1 (BACKWARD_TRANSITIVE): field "total": the reader field has no default and the writer does not write it`},
+		},
+		{
+			name:     "union widening",
+			level:    "FULL",
+			schema:   `{"type":"record","name":"Order","fields":[{"name":"id","type":["null","string"],"default":null}]}`,
+			versions: []Version{{Version: 1, Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`}},
+			expected: []string{`version 1 cannot read the new schema (FULL): field "id": type null cannot be read as string`},
+		},
+		{
+			name:     "recursive record",
+			level:    "FULL",
+			schema:   `{"type":"record","name":"Node","fields":[{"name":"next","type":["null","Node"],"default":null}]}`,
+			versions: []Version{{Version: 1, Schema: `{"type":"record","name":"Node","fields":[{"name":"next","type":["null","Node"],"default":null}]}`}},
+		},
+		{
+			name:     "renamed record",
+			level:    "BACKWARD",
+			schema:   `{"type":"record","name":"Purchase","fields":[]}`,
+			versions: v1,
+			expected: []string{`the new schema cannot read version 1 (BACKWARD): the schema: record com.example.Order cannot be read as Purchase`},
+		},
+		{
+			name:     "NONE",
+			level:    "NONE",
+			schema:   `"string"`,
+			versions: v1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			messages, err := Avro(tc.level, tc.schema, tc.versions)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(messages, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, messages)
+			}
+		})
+	}
+
+	t.Run("invalid schema", func(t *testing.T) {
+		if _, err := Avro("BACKWARD", `{"type":`, []Version{{Version: 1, Schema: orderV1}}); err == nil {
+			t.Error("expected an error")
+		}
+		if _, err := Avro("SIDEWAYS", orderV1, []Version{{Version: 1, Schema: orderV1}}); err == nil {
+			t.Error("expected an unknown level error")
+		}
+	})
+}
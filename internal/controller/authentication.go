@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	basicAuthSuffix    = "-basic-auth"
+	basicAuthMountPath = "/var/rest-api-auth"
+	defaultAuthRealm   = "SchemaRegistry"
+	// usersSecretVersionKey records, on the rendered secret, which users secret version it was built from.
+	usersSecretVersionKey = keyPrefix + "/usersSecretVersion"
+	// basicAuthVersionKey on the pod template rolls the pods when the rendered secret changes.
+	basicAuthVersionKey = keyPrefix + "/basicAuthVersion"
+)
+
+// basicAuthEnabled reports whether HTTP Basic authentication is configured.
+func basicAuthEnabled(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return instance.Spec.Authentication != nil && instance.Spec.Authentication.Basic != nil
+}
+
+// basicAuthRealm returns the JAAS realm used for HTTP Basic authentication.
+func basicAuthRealm(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if realm := instance.Spec.Authentication.Basic.Realm; realm != "" {
+		return realm
+	}
+	return defaultAuthRealm
+}
+
+// basicAuthEnv returns the env vars enabling HTTP Basic authentication on the REST API.
+func basicAuthEnv(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) []v1.EnvVar {
+	if !basicAuthEnabled(instance) {
+		return nil
+	}
+	env := []v1.EnvVar{
+		{Name: "SCHEMA_REGISTRY_AUTHENTICATION_METHOD", Value: "BASIC"},
+		{Name: "SCHEMA_REGISTRY_AUTHENTICATION_REALM", Value: basicAuthRealm(instance)},
+	}
+	if roles := instance.Spec.Authentication.Basic.Roles; len(roles) > 0 {
+		env = append(env, v1.EnvVar{Name: "SCHEMA_REGISTRY_AUTHENTICATION_ROLES", Value: strings.Join(roles, ",")})
+	}
+	return env
+}
+
+// basicAuthJavaOpts returns the JVM flag loading the rendered JAAS config, or "" when
+// Basic authentication is disabled. It is appended to SCHEMA_REGISTRY_OPTS rather than
+// set as a managed variable, so users can still pass their own JVM options.
+func basicAuthJavaOpts(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if !basicAuthEnabled(instance) {
+		return ""
+	}
+	return "-Djava.security.auth.login.config=" + basicAuthMountPath + "/jaas.conf"
+}
+
+// renderJAASConfig returns the JAAS config reading users from the mounted password file.
+func renderJAASConfig(realm string) string {
+	return fmt.Sprintf(`%s {
+  org.eclipse.jetty.jaas.spi.PropertyFileLoginModule required
+  file="%s/password.properties"
+  debug="false";
+};
+`, realm, basicAuthMountPath)
+}
+
+// renderPasswordFile turns the users secret (user name -> "<password>[,<role>...]") into a
+// Jetty password file, sorted by user name so the output is stable.
+func renderPasswordFile(users map[string][]byte) (string, error) {
+	if len(users) == 0 {
+		return "", fmt.Errorf("users secret has no users")
+	}
+	names := make([]string, 0, len(users))
+	for name := range users {
+		if strings.ContainsAny(name, ":= \t\\#!") {
+			return "", fmt.Errorf("user name %q contains a character not allowed in a password file", name)
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		entry := strings.TrimRight(string(users[name]), "\r\n")
+		if strings.ContainsAny(entry, "\r\n") {
+			return "", fmt.Errorf("entry of user %q spans several lines", name)
+		}
+		if password, _, _ := strings.Cut(entry, ","); password == "" {
+			return "", fmt.Errorf("user %q has an empty password", name)
+		}
+		// The file is loaded as Java properties, where backslashes are escapes.
+		fmt.Fprintf(&b, "%s: %s\n", name, strings.ReplaceAll(entry, `\`, `\\`))
+	}
+	return b.String(), nil
+}
+
+// reconcileBasicAuthSecret renders the JAAS config and password file from the users secret.
+// The rendered secret is rebuilt whenever the users secret changes, and deleted once Basic
+// authentication is disabled. It returns the ResourceVersion of the rendered secret, or ""
+// when Basic authentication is disabled.
+func (r *StrimziSchemaRegistryReconciler) reconcileBasicAuthSecret(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) (string, error) {
+	rendered := &v1.Secret{}
+	rendered.Name = instance.Name + basicAuthSuffix
+	rendered.Namespace = instance.Namespace
+	if !basicAuthEnabled(instance) {
+		return "", r.deleteOwnedNamed(ctx, instance, logger, rendered)
+	}
+
+	usersName := instance.Spec.Authentication.Basic.UsersSecretName
+	users := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: usersName, Namespace: instance.Namespace}, users); err != nil {
+		return "", fmt.Errorf("failed to get users secret %s: %w", usersName, err)
+	}
+	// The realm is part of the rendered JAAS config, so it is part of the version as well.
+	usersVersion := usersName + "/" + users.ResourceVersion + "/" + basicAuthRealm(instance)
+
+	err := r.Get(ctx, types.NamespacedName{Name: rendered.Name, Namespace: rendered.Namespace}, rendered)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && rendered.Annotations[usersSecretVersionKey] == usersVersion {
+		return rendered.ResourceVersion, nil
+	}
+
+	passwordFile, err := renderPasswordFile(users.Data)
+	if err != nil {
+		return "", fmt.Errorf("invalid users secret %s: %w", usersName, err)
+	}
+	logger.Info("Rendering REST API Basic authentication config", "Secret.Name", rendered.Name, "Users", usersName)
+	err = r.applyOwned(ctx, instance, logger, rendered, func() {
+		rendered.Labels = map[string]string{
+			"app":  "strimzi-schema-registry",
+			"user": instance.Name,
+		}
+		if rendered.Annotations == nil {
+			rendered.Annotations = map[string]string{}
+		}
+		rendered.Annotations[usersSecretVersionKey] = usersVersion
+		rendered.Type = v1.SecretTypeOpaque
+		rendered.Data = map[string][]byte{
+			"jaas.conf":           []byte(renderJAASConfig(basicAuthRealm(instance))),
+			"password.properties": []byte(passwordFile),
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return rendered.ResourceVersion, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newBasicAuthInstance() *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	inst := newTestInstance()
+	inst.UID = types.UID("test-uid")
+	inst.Spec.Authentication = &strimziregistryoperatorv1alpha1.AuthenticationSpec{
+		Basic: &strimziregistryoperatorv1alpha1.BasicAuthenticationSpec{
+			UsersSecretName: "registry-users",
+			Roles:           []string{"admin", "developer"},
+		},
+	}
+	return inst
+}
+
+func TestRenderPasswordFile(t *testing.T) {
+	got, err := renderPasswordFile(map[string][]byte{
+		"ci":    []byte("s3cr\\t,developer\n"),
+		"alice": []byte("pw,admin,developer"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "alice: pw,admin,developer\nci: s3cr\\\\t,developer\n"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	for name, users := range map[string]map[string][]byte{
+		"no users":         {},
+		"invalid name":     {"bad:name": []byte("pw")},
+		"empty password":   {"bob": []byte(",admin")},
+		"multi-line entry": {"bob": []byte("pw\nmallory: pw,admin")},
+	} {
+		if _, err := renderPasswordFile(users); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBasicAuthPodSpec(t *testing.T) {
+	inst := newBasicAuthInstance()
+	env := buildPodEnv(inst, "kafka:9093", "")
+	for _, name := range []string{"SCHEMA_REGISTRY_AUTHENTICATION_METHOD", "SCHEMA_REGISTRY_AUTHENTICATION_REALM",
+		"SCHEMA_REGISTRY_AUTHENTICATION_ROLES"} {
+		if !hasEnv(env, name) {
+			t.Errorf("expected %s env var", name)
+		}
+	}
+	if hasEnv(env, "SCHEMA_REGISTRY_OPTS") {
+		t.Error("SCHEMA_REGISTRY_OPTS must stay available to the template")
+	}
+	if got := basicAuthJavaOpts(inst); got != "-Djava.security.auth.login.config="+basicAuthMountPath+"/jaas.conf" {
+		t.Errorf("unexpected JVM options %q", got)
+	}
+	volumes, _ := buildPodVolumes(inst, "", "kafka")
+	if last := volumes[len(volumes)-1]; last.Secret == nil || last.Secret.SecretName != "test-sr"+basicAuthSuffix {
+		t.Errorf("expected rendered auth volume, got %+v", last)
+	}
+	readiness, _, _ := buildProbes(inst)
+	if readiness.TCPSocket == nil {
+		t.Errorf("expected TCP readiness probe with Basic authentication, got %+v", readiness.ProbeHandler)
+	}
+	if got := renderJAASConfig(basicAuthRealm(inst)); !strings.HasPrefix(got, "SchemaRegistry {") ||
+		!strings.Contains(got, basicAuthMountPath+"/password.properties") {
+		t.Errorf("unexpected JAAS config %q", got)
+	}
+
+	withAuth, _ := computeSpecHash(inst)
+	inst.Spec.Authentication = nil
+	without, _ := computeSpecHash(inst)
+	if withAuth == without {
+		t.Error("enabling Basic authentication must change the spec hash")
+	}
+}
+
+func TestReconcileBasicAuthSecret(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	inst := newBasicAuthInstance()
+	users := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-users", Namespace: "default"},
+		Data:       map[string][]byte{"alice": []byte("pw,admin")},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(users).Build()
+	key := types.NamespacedName{Name: "test-sr" + basicAuthSuffix, Namespace: "default"}
+
+	first, err := r.reconcileBasicAuthSecret(ctx, inst, logr.Discard())
+	if err != nil || first == "" {
+		t.Fatalf("unexpected result %q (err: %v)", first, err)
+	}
+	rendered := &corev1.Secret{}
+	if err := r.Get(ctx, key, rendered); err != nil {
+		t.Fatalf("expected rendered secret: %v", err)
+	}
+	if string(rendered.Data["password.properties"]) != "alice: pw,admin\n" || !metav1.IsControlledBy(rendered, inst) {
+		t.Errorf("unexpected rendered secret %+v", rendered)
+	}
+
+	t.Run("unchanged users keep the rendered secret", func(t *testing.T) {
+		again, err := r.reconcileBasicAuthSecret(ctx, inst, logr.Discard())
+		if err != nil || again != first {
+			t.Errorf("expected version %q, got %q (err: %v)", first, again, err)
+		}
+	})
+
+	t.Run("a users secret change renders a new version", func(t *testing.T) {
+		users.Data["bob"] = []byte("pw2,developer")
+		if err := r.Update(ctx, users); err != nil {
+			t.Fatal(err)
+		}
+		next, err := r.reconcileBasicAuthSecret(ctx, inst, logr.Discard())
+		if err != nil || next == first {
+			t.Fatalf("expected a new version, got %q (err: %v)", next, err)
+		}
+		versions, err := r.podTemplateVersions(ctx, inst)
+		if err != nil || versions[basicAuthVersionKey] != next {
+			t.Errorf("unexpected pod template versions %v (err: %v)", versions, err)
+		}
+	})
+
+	t.Run("disabling Basic authentication deletes the rendered secret", func(t *testing.T) {
+		inst := inst.DeepCopy()
+		inst.Spec.Authentication = nil
+		if _, err := r.reconcileBasicAuthSecret(ctx, inst, logr.Discard()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Get(ctx, key, &corev1.Secret{}); !errors.IsNotFound(err) {
+			t.Errorf("expected rendered secret to be deleted, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// defaultTargetCPUUtilization is the CPU target used when spec.autoscaling sets no metrics.
+const defaultTargetCPUUtilization int32 = 80
+
+// buildHorizontalPodAutoscaler builds the autoscaling/v2 HorizontalPodAutoscaler targeting
+// the Schema Registry deployment. It returns nil when spec.autoscaling is not set.
+func (r *StrimziSchemaRegistryReconciler) buildHorizontalPodAutoscaler(
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
+) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	autoscaling := instance.Spec.Autoscaling
+	if autoscaling == nil {
+		return nil, nil
+	}
+
+	var metrics []autoscalingv2.MetricSpec
+	if autoscaling.TargetCPUUtilizationPercentage != nil || len(autoscaling.Metrics) == 0 {
+		target := defaultTargetCPUUtilization
+		if autoscaling.TargetCPUUtilizationPercentage != nil {
+			target = *autoscaling.TargetCPUUtilizationPercentage
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: v1.ResourceCPU,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: ptr.To(target),
+				},
+			},
+		})
+	}
+	for i := range autoscaling.Metrics {
+		metrics = append(metrics, *autoscaling.Metrics[i].DeepCopy())
+	}
+
+	minReplicas := int32(1)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+			Labels:    podSelectorLabels(instance.Name),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       instance.Name + deploySuffix,
+			},
+			MinReplicas: ptr.To(minReplicas),
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+			Behavior:    autoscaling.Behavior.DeepCopy(),
+		},
+	}
+	if err := ctrl.SetControllerReference(instance, hpa, r.Scheme); err != nil {
+		return nil, err
+	}
+	return hpa, nil
+}
+
+// reconcileHorizontalPodAutoscaler creates or updates the HorizontalPodAutoscaler when
+// spec.autoscaling is set, and removes the one owned by the CR otherwise. It returns the
+// current autoscaler, or nil when autoscaling is disabled.
+func (r *StrimziSchemaRegistryReconciler) reconcileHorizontalPodAutoscaler(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger,
+) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	desired, err := r.buildHorizontalPodAutoscaler(instance)
+	if err != nil {
+		return nil, err
+	}
+	if desired == nil {
+		existing := &autoscalingv2.HorizontalPodAutoscaler{}
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, existing)
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !metav1.IsControlledBy(existing, instance) {
+			return nil, nil
+		}
+		logger.Info("Autoscaling disabled, deleting HorizontalPodAutoscaler", "HorizontalPodAutoscaler.Name", existing.Name)
+		return nil, client.IgnoreNotFound(r.Delete(ctx, existing))
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, hpa, func() error {
+		hpa.Labels = desired.Labels
+		hpa.Spec = desired.Spec
+		return ctrl.SetControllerReference(instance, hpa, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if op != controllerutil.OperationResultNone {
+		logger.Info("HorizontalPodAutoscaler reconciled", "HorizontalPodAutoscaler.Name", hpa.Name, "Operation", op)
+	}
+	return hpa, nil
+}
+
+// autoscalingStatus converts the HorizontalPodAutoscaler status into the CR status field.
+func autoscalingStatus(hpa *autoscalingv2.HorizontalPodAutoscaler) *strimziregistryoperatorv1alpha1.AutoscalingStatus {
+	if hpa == nil {
+		return nil
+	}
+	return &strimziregistryoperatorv1alpha1.AutoscalingStatus{
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		LastScaleTime:   hpa.Status.LastScaleTime.DeepCopy(),
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestBuildHorizontalPodAutoscaler(t *testing.T) {
+	r := newTestReconciler()
+
+	t.Run("no autoscaling means no HPA", func(t *testing.T) {
+		hpa, err := r.buildHorizontalPodAutoscaler(newTestInstance())
+		if err != nil || hpa != nil {
+			t.Errorf("expected nil HPA, got %+v (err: %v)", hpa, err)
+		}
+	})
+
+	t.Run("defaults to CPU utilization target", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Autoscaling = &strimziregistryoperatorv1alpha1.AutoscalingSpec{MaxReplicas: 5}
+		hpa, err := r.buildHorizontalPodAutoscaler(inst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hpa.Spec.ScaleTargetRef.Kind != "Deployment" || hpa.Spec.ScaleTargetRef.Name != "test-sr-deploy" {
+			t.Errorf("unexpected scale target: %+v", hpa.Spec.ScaleTargetRef)
+		}
+		if *hpa.Spec.MinReplicas != 1 || hpa.Spec.MaxReplicas != 5 {
+			t.Errorf("unexpected replica bounds: min=%d max=%d", *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas)
+		}
+		if len(hpa.Spec.Metrics) != 1 || hpa.Spec.Metrics[0].Resource.Name != corev1.ResourceCPU ||
+			*hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != defaultTargetCPUUtilization {
+			t.Errorf("expected default CPU metric, got %+v", hpa.Spec.Metrics)
+		}
+		if len(hpa.OwnerReferences) != 1 {
+			t.Error("expected HPA to be owned by the CR")
+		}
+	})
+
+	t.Run("custom metrics replace the default CPU target", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Autoscaling = &strimziregistryoperatorv1alpha1.AutoscalingSpec{
+			MinReplicas: ptr.To[int32](2),
+			MaxReplicas: 10,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.PodsMetricSourceType,
+				Pods: &autoscalingv2.PodsMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+				},
+			}},
+		}
+		hpa, err := r.buildHorizontalPodAutoscaler(inst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(hpa.Spec.Metrics) != 1 || hpa.Spec.Metrics[0].Type != autoscalingv2.PodsMetricSourceType {
+			t.Errorf("expected only the custom metric, got %+v", hpa.Spec.Metrics)
+		}
+		if desiredReplicas(inst) != 2 {
+			t.Errorf("expected initial replicas from minReplicas, got %d", desiredReplicas(inst))
+		}
+	})
+
+	t.Run("explicit CPU target is combined with custom metrics", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Autoscaling = &strimziregistryoperatorv1alpha1.AutoscalingSpec{
+			MaxReplicas:                    4,
+			TargetCPUUtilizationPercentage: ptr.To[int32](60),
+			Metrics:                        []autoscalingv2.MetricSpec{{Type: autoscalingv2.ExternalMetricSourceType}},
+		}
+		hpa, err := r.buildHorizontalPodAutoscaler(inst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(hpa.Spec.Metrics) != 2 || *hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != 60 {
+			t.Errorf("expected CPU target 60 followed by the custom metric, got %+v", hpa.Spec.Metrics)
+		}
+	})
+}
+
+func TestAutoscalingStatus(t *testing.T) {
+	if autoscalingStatus(nil) != nil {
+		t.Error("expected nil status without an HPA")
+	}
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, DesiredReplicas: 4},
+	}
+	status := autoscalingStatus(hpa)
+	if status.CurrentReplicas != 3 || status.DesiredReplicas != 4 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+}
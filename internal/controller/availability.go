@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Topology keys used for the default pod spreading.
+const (
+	hostnameTopologyKey = "kubernetes.io/hostname"
+	zoneTopologyKey     = "topology.kubernetes.io/zone"
+)
+
+// podSelectorLabels returns the subset of pod labels that stays stable across
+// image upgrades, for use in PodDisruptionBudget and affinity selectors.
+func podSelectorLabels(name string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/instance": name,
+		"app.kubernetes.io/name":     "strimzischemaregistry",
+	}
+}
+
+// desiredReplicas returns the replica count requested in the CR (defaults to 1, or to
+// the autoscaling minimum when autoscaling is enabled).
+func desiredReplicas(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) int32 {
+	if instance.Spec.Replicas != nil {
+		return *instance.Spec.Replicas
+	}
+	if instance.Spec.Autoscaling != nil && instance.Spec.Autoscaling.MinReplicas != nil {
+		return *instance.Spec.Autoscaling.MinReplicas
+	}
+	return 1
+}
+
+// applyPodSpreading injects the default pod anti-affinity and zone topology spread
+// constraint configured in spec.availability. Anything the template already defines
+// is left untouched.
+func applyPodSpreading(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, podSpec *v1.PodSpec) {
+	availability := instance.Spec.Availability
+	if availability == nil {
+		return
+	}
+	selector := &metav1.LabelSelector{MatchLabels: podSelectorLabels(instance.Name)}
+
+	if podSpec.Affinity == nil || podSpec.Affinity.PodAntiAffinity == nil {
+		term := v1.PodAffinityTerm{LabelSelector: selector, TopologyKey: hostnameTopologyKey}
+		var antiAffinity *v1.PodAntiAffinity
+		switch availability.PodAntiAffinity {
+		case strimziregistryoperatorv1alpha1.PodAntiAffinityNone:
+		case strimziregistryoperatorv1alpha1.PodAntiAffinityRequired:
+			antiAffinity = &v1.PodAntiAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{term},
+			}
+		default:
+			antiAffinity = &v1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+					{Weight: 100, PodAffinityTerm: term},
+				},
+			}
+		}
+		if antiAffinity != nil {
+			if podSpec.Affinity == nil {
+				podSpec.Affinity = &v1.Affinity{}
+			}
+			podSpec.Affinity.PodAntiAffinity = antiAffinity
+		}
+	}
+
+	if len(podSpec.TopologySpreadConstraints) == 0 {
+		var whenUnsatisfiable v1.UnsatisfiableConstraintAction
+		switch availability.ZoneSpread {
+		case strimziregistryoperatorv1alpha1.ZoneSpreadNone:
+		case strimziregistryoperatorv1alpha1.ZoneSpreadDoNotSchedule:
+			whenUnsatisfiable = v1.DoNotSchedule
+		default:
+			whenUnsatisfiable = v1.ScheduleAnyway
+		}
+		if whenUnsatisfiable != "" {
+			podSpec.TopologySpreadConstraints = []v1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       zoneTopologyKey,
+				WhenUnsatisfiable: whenUnsatisfiable,
+				LabelSelector:     selector,
+			}}
+		}
+	}
+}
+
+// buildPodDisruptionBudget builds the PodDisruptionBudget for the Schema Registry pods.
+// It returns nil when spec.availability is not set.
+func (r *StrimziSchemaRegistryReconciler) buildPodDisruptionBudget(
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
+) (*policyv1.PodDisruptionBudget, error) {
+	availability := instance.Spec.Availability
+	if availability == nil {
+		return nil, nil
+	}
+	if availability.MinAvailable != nil && availability.MaxUnavailable != nil {
+		return nil, fmt.Errorf("spec.availability: minAvailable and maxUnavailable are mutually exclusive")
+	}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+			Labels:    podSelectorLabels(instance.Name),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector:       &metav1.LabelSelector{MatchLabels: podSelectorLabels(instance.Name)},
+			MinAvailable:   availability.MinAvailable,
+			MaxUnavailable: availability.MaxUnavailable,
+		},
+	}
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		maxUnavailable := intstr.FromInt32(1)
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+	if err := ctrl.SetControllerReference(instance, pdb, r.Scheme); err != nil {
+		return nil, err
+	}
+	return pdb, nil
+}
+
+// reconcilePodDisruptionBudget creates or updates the PodDisruptionBudget when
+// spec.availability is set, and removes the one owned by the CR otherwise.
+func (r *StrimziSchemaRegistryReconciler) reconcilePodDisruptionBudget(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) error {
+	desired, err := r.buildPodDisruptionBudget(instance)
+	if err != nil {
+		return err
+	}
+	if desired == nil {
+		existing := &policyv1.PodDisruptionBudget{}
+		err := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, existing)
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !metav1.IsControlledBy(existing, instance) {
+			return nil
+		}
+		logger.Info("Availability disabled, deleting PodDisruptionBudget", "PodDisruptionBudget.Name", existing.Name)
+		return client.IgnoreNotFound(r.Delete(ctx, existing))
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+		pdb.Labels = desired.Labels
+		pdb.Spec = desired.Spec
+		return ctrl.SetControllerReference(instance, pdb, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		logger.Info("PodDisruptionBudget reconciled", "PodDisruptionBudget.Name", pdb.Name, "Operation", op)
+	}
+	return nil
+}
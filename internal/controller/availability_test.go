@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestBuildPodDisruptionBudget(t *testing.T) {
+	r := newTestReconciler()
+
+	t.Run("no availability means no PDB", func(t *testing.T) {
+		pdb, err := r.buildPodDisruptionBudget(newTestInstance())
+		if err != nil || pdb != nil {
+			t.Errorf("expected nil PDB, got %+v (err: %v)", pdb, err)
+		}
+	})
+
+	t.Run("defaults to maxUnavailable 1", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{}
+		pdb, err := r.buildPodDisruptionBudget(inst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pdb.Spec.MaxUnavailable == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 || pdb.Spec.MinAvailable != nil {
+			t.Errorf("expected maxUnavailable=1, got %+v", pdb.Spec)
+		}
+		if pdb.Spec.Selector.MatchLabels["app.kubernetes.io/instance"] != inst.Name {
+			t.Errorf("unexpected selector: %+v", pdb.Spec.Selector)
+		}
+		if len(pdb.OwnerReferences) != 1 {
+			t.Error("expected PDB to be owned by the CR")
+		}
+	})
+
+	t.Run("minAvailable is passed through", func(t *testing.T) {
+		inst := newTestInstance()
+		minAvailable := intstr.FromString("50%")
+		inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{MinAvailable: &minAvailable}
+		pdb, err := r.buildPodDisruptionBudget(inst)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if pdb.Spec.MinAvailable == nil || pdb.Spec.MinAvailable.StrVal != "50%" || pdb.Spec.MaxUnavailable != nil {
+			t.Errorf("expected minAvailable=50%%, got %+v", pdb.Spec)
+		}
+	})
+
+	t.Run("both bounds returns error", func(t *testing.T) {
+		inst := newTestInstance()
+		one := intstr.FromInt32(1)
+		inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{MinAvailable: &one, MaxUnavailable: &one}
+		if _, err := r.buildPodDisruptionBudget(inst); err == nil {
+			t.Error("expected error when both minAvailable and maxUnavailable are set")
+		}
+	})
+}
+
+func TestApplyPodSpreading(t *testing.T) {
+	t.Run("defaults inject preferred anti-affinity and zone spread", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{}
+		podSpec := &corev1.PodSpec{}
+		applyPodSpreading(inst, podSpec)
+		if podSpec.Affinity == nil || len(podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+			t.Fatalf("expected preferred anti-affinity, got %+v", podSpec.Affinity)
+		}
+		if len(podSpec.TopologySpreadConstraints) != 1 ||
+			podSpec.TopologySpreadConstraints[0].WhenUnsatisfiable != corev1.ScheduleAnyway {
+			t.Errorf("expected ScheduleAnyway zone spread, got %+v", podSpec.TopologySpreadConstraints)
+		}
+	})
+
+	t.Run("required and none modes", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{
+			PodAntiAffinity: strimziregistryoperatorv1alpha1.PodAntiAffinityRequired,
+			ZoneSpread:      strimziregistryoperatorv1alpha1.ZoneSpreadNone,
+		}
+		podSpec := &corev1.PodSpec{}
+		applyPodSpreading(inst, podSpec)
+		if podSpec.Affinity == nil || len(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
+			t.Errorf("expected required anti-affinity, got %+v", podSpec.Affinity)
+		}
+		if len(podSpec.TopologySpreadConstraints) != 0 {
+			t.Errorf("expected no zone spread, got %+v", podSpec.TopologySpreadConstraints)
+		}
+	})
+
+	t.Run("template settings take precedence", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{}
+		userAffinity := &corev1.PodAntiAffinity{}
+		userSpread := []corev1.TopologySpreadConstraint{{MaxSkew: 2, TopologyKey: "rack"}}
+		podSpec := &corev1.PodSpec{
+			Affinity:                  &corev1.Affinity{PodAntiAffinity: userAffinity},
+			TopologySpreadConstraints: userSpread,
+		}
+		applyPodSpreading(inst, podSpec)
+		if podSpec.Affinity.PodAntiAffinity != userAffinity {
+			t.Error("template anti-affinity must not be replaced")
+		}
+		if len(podSpec.TopologySpreadConstraints) != 1 || podSpec.TopologySpreadConstraints[0].TopologyKey != "rack" {
+			t.Errorf("template topology spread must not be replaced, got %+v", podSpec.TopologySpreadConstraints)
+		}
+	})
+}
+
+func TestReplicasDoNotChangeSpecHash(t *testing.T) {
+	inst := newTestInstance()
+	before, _ := computeSpecHash(inst)
+	inst.Spec.Replicas = ptr.To[int32](3)
+	after, _ := computeSpecHash(inst)
+	if before != after {
+		t.Error("changing replicas must not change the spec hash")
+	}
+	if desiredReplicas(inst) != 3 || desiredReplicas(newTestInstance()) != 1 {
+		t.Error("unexpected desired replica count")
+	}
+
+	inst.Spec.Availability = &strimziregistryoperatorv1alpha1.AvailabilitySpec{}
+	withAvailability, _ := computeSpecHash(inst)
+	if withAvailability == after {
+		t.Error("enabling availability must change the spec hash")
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// backupJobPollPeriod is how often a running backup Job is checked.
+	backupJobPollPeriod = 10 * time.Second
+	// backupJobTTL is how long finished backup Jobs are kept for inspection.
+	backupJobTTL = 24 * 60 * 60
+	// backupAccessSuffix names the Secret holding the registry access of the backup Jobs.
+	backupAccessSuffix = "-registry-access"
+	// backupRegistryDir and backupVolumeDir are where the volume Jobs mount the registry access
+	// Secret and where the backup Jobs mount the snapshot volume.
+	backupRegistryDir = "/etc/registry"
+	backupVolumeDir   = "/backup"
+	// backupJobUser is the user of the operator image, which owns the snapshot files.
+	backupJobUser = 65532
+
+	// annotationBackupOperation and annotationBackupSnapshot record what a backup Job does.
+	annotationBackupOperation = keyPrefix + "/operation"
+	annotationBackupSnapshot  = keyPrefix + "/snapshot"
+)
+
+// startBackupJob starts the Job taking or restoring a snapshot on the volume of instance. The
+// Job runs the operator image with the backup or restore command, reaching the registry with
+// the settings of the registry access Secret.
+func (r *StrimziSchemaRegistryBackupReconciler) startBackupJob(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, cfg registryclient.Config, now time.Time,
+	logger logr.Logger) error {
+	if r.JobImage == "" {
+		return errJobImageNotSet
+	}
+	if err := applyRegistryAccessSecret(ctx, r.Client, r.Scheme, instance, instance.Name+backupAccessSuffix, cfg); err != nil {
+		return err
+	}
+
+	job := buildBackupJob(instance, r.JobImage, now)
+	if err := ctrl.SetControllerReference(instance, job, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, job); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create Job %s: %w", job.Name, err)
+	}
+	logger.Info("Started backup Job", "Job", job.Name, "Operation", job.Annotations[annotationBackupOperation])
+	instance.Status.ActiveJob = job.Name
+	setBackupReady(instance, metav1.ConditionUnknown, "JobRunning", fmt.Sprintf("Job %s is running", job.Name))
+	return nil
+}
+
+// buildBackupJob builds the Job of a volume backup or restore.
+func buildBackupJob(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, image string,
+	now time.Time) *batchv1.Job {
+	storage := instance.Spec.Storage.PersistentVolumeClaim
+	dir := path.Join(backupVolumeDir, storage.Path)
+	operation, snapshot := "backup", backup.SnapshotName(now)
+	name := instance.Name + "-" + snapshot
+	args := []string{"--registry-dir=" + backupRegistryDir, "--dir=" + dir}
+	if restore := instance.Spec.Restore; restore != nil {
+		operation, snapshot = "restore", restore.Snapshot
+		name = instance.Name + "-restore-" + strconv.FormatInt(instance.Generation, 10)
+		if snapshot != "" {
+			args = append(args, "--snapshot="+snapshot)
+		}
+	} else {
+		args = append(args, "--snapshot="+snapshot, "--keep-last="+strconv.Itoa(keepLast(instance)))
+	}
+
+	labels := map[string]string{backup.LabelBackup: instance.Name}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				annotationBackupOperation: operation,
+				annotationBackupSnapshot:  snapshot,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            ptr.To[int32](2),
+			TTLSecondsAfterFinished: ptr.To[int32](backupJobTTL),
+			Template: volumeJobPodTemplate(labels, image, instance.Name+backupAccessSuffix, storage.ClaimName,
+				backupVolumeDir, append([]string{operation}, args...)),
+		},
+	}
+}
+
+// applyRegistryAccessSecret publishes cfg in the Secret name owned by owner, in the format read
+// by the backup and export commands of the volume Jobs.
+func applyRegistryAccessSecret(ctx context.Context, c client.Client, scheme *runtime.Scheme, owner client.Object,
+	name string, cfg registryclient.Config) error {
+	access := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: owner.GetNamespace()}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, c, access, func() error {
+		access.Data = backup.RegistryAccessData(cfg)
+		return ctrl.SetControllerReference(owner, access, scheme)
+	}); err != nil {
+		return fmt.Errorf("failed to apply registry access secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// volumeJobPodTemplate returns the pod of a Job running the operator image with args, the
+// registry access Secret accessSecret mounted in backupRegistryDir and the volume claimName
+// mounted in volumeDir.
+func volumeJobPodTemplate(labels map[string]string, image, accessSecret, claimName, volumeDir string,
+	args []string) v1.PodTemplateSpec {
+	return v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: labels},
+		Spec: v1.PodSpec{
+			RestartPolicy: v1.RestartPolicyNever,
+			SecurityContext: &v1.PodSecurityContext{
+				RunAsNonRoot: ptr.To(true),
+				RunAsUser:    ptr.To[int64](backupJobUser),
+				FSGroup:      ptr.To[int64](backupJobUser),
+			},
+			Containers: []v1.Container{{
+				Name:    args[0],
+				Image:   image,
+				Command: []string{"/manager"},
+				Args:    args,
+				VolumeMounts: []v1.VolumeMount{
+					{Name: "registry-access", MountPath: backupRegistryDir, ReadOnly: true},
+					{Name: "data", MountPath: volumeDir},
+				},
+				SecurityContext: &v1.SecurityContext{
+					AllowPrivilegeEscalation: ptr.To(false),
+					Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}},
+				},
+			}},
+			Volumes: []v1.Volume{
+				{Name: "registry-access", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{
+					SecretName: accessSecret, DefaultMode: ptr.To[int32](0o440)}}},
+				{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+					ClaimName: claimName}}},
+			},
+		},
+	}
+}
+
+// trackBackupJob reports the outcome of status.activeJob once it has finished and clears it.
+// It returns whether the Job has finished.
+func (r *StrimziSchemaRegistryBackupReconciler) trackBackupJob(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, logger logr.Logger) (bool, error) {
+	status := &instance.Status
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: status.ActiveJob, Namespace: instance.Namespace}, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		setBackupReady(instance, metav1.ConditionFalse, "JobNotFound", fmt.Sprintf("Job %s was deleted before it finished", status.ActiveJob))
+		status.ActiveJob = ""
+		return true, nil
+	}
+
+	var finished *batchv1.JobCondition
+	for i, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == v1.ConditionTrue {
+			finished = &job.Status.Conditions[i]
+		}
+	}
+	if finished == nil {
+		return false, nil
+	}
+	status.ActiveJob = ""
+	if finished.Type == batchv1.JobFailed {
+		logger.Info("Backup Job failed", "Job", job.Name, "Reason", finished.Reason)
+		setBackupReady(instance, metav1.ConditionFalse, "JobFailed", fmt.Sprintf("Job %s failed: %s", job.Name, finished.Message))
+		return true, nil
+	}
+
+	snapshot, completed := job.Annotations[annotationBackupSnapshot], finished.LastTransitionTime
+	if job.Annotations[annotationBackupOperation] == "restore" {
+		if snapshot == "" {
+			snapshot = "latest"
+		}
+		status.RestoredSnapshot = snapshot
+		status.RestoreTime = &completed
+		setBackupReady(instance, metav1.ConditionTrue, "Restored", fmt.Sprintf("snapshot %s restored by Job %s", snapshot, job.Name))
+		return true, nil
+	}
+	status.LastSnapshot = snapshot
+	status.LastBackupTime = &completed
+	status.Subjects, status.Schemas = 0, 0
+	setBackupReady(instance, metav1.ConditionTrue, "SnapshotTaken", fmt.Sprintf("snapshot %s written by Job %s", snapshot, job.Name))
+	return true, nil
+}
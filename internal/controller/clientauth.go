@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	certprocessor "github.com/randsw/schema-registry-operator-strimzi/certProcessor"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	clientsCASuffix           = "-clients-ca-cert"
+	clientTruststoreSuffix    = "-client-truststore"
+	clientTruststoreMountPath = "/var/rest-api-client-truststore"
+	// clientCAVersionKey records, on the truststore secret, which CA secret version it was built from.
+	clientCAVersionKey = keyPrefix + "/clientCAVersion"
+	// clientTruststoreVersionKey on the pod template rolls the pods when the truststore changes.
+	clientTruststoreVersionKey = keyPrefix + "/clientTruststoreVersion"
+)
+
+// clientAuthMode returns the effective REST API client authentication mode.
+// Client certificates are only asked for on the HTTPS listener.
+func clientAuthMode(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) strimziregistryoperatorv1alpha1.ClientAuthMode {
+	if !instance.Spec.SecureHTTP || instance.Spec.TLS == nil || instance.Spec.TLS.ClientAuth == "" {
+		return strimziregistryoperatorv1alpha1.ClientAuthNone
+	}
+	return instance.Spec.TLS.ClientAuth
+}
+
+// clientAuthEnabled reports whether the REST API verifies client certificates.
+func clientAuthEnabled(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return clientAuthMode(instance) != strimziregistryoperatorv1alpha1.ClientAuthNone
+}
+
+// clientCASecretRef returns the name and key of the CA certificate that signs client
+// certificates: spec.tls.clientCASecretName, or the Strimzi clients CA by default.
+func clientCASecretRef(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaClusterName string) (string, string) {
+	name, key := kafkaClusterName+clientsCASuffix, "ca.crt"
+	if instance.Spec.TLS != nil {
+		if instance.Spec.TLS.ClientCASecretName != "" {
+			name = instance.Spec.TLS.ClientCASecretName
+		}
+		if instance.Spec.TLS.ClientCAKey != "" {
+			key = instance.Spec.TLS.ClientCAKey
+		}
+	}
+	return name, key
+}
+
+// clientAuthEnv returns the env vars enabling client certificate verification on the REST API.
+func clientAuthEnv(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) []v1.EnvVar {
+	if !clientAuthEnabled(instance) {
+		return nil
+	}
+	return []v1.EnvVar{
+		{Name: "SCHEMA_REGISTRY_SSL_CLIENT_AUTHENTICATION", Value: strings.ToUpper(string(clientAuthMode(instance)))},
+		{Name: "SCHEMA_REGISTRY_SSL_TRUSTSTORE_LOCATION", Value: clientTruststoreMountPath + "/truststore.jks"},
+		{Name: "SCHEMA_REGISTRY_SSL_TRUSTSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: instance.Name + clientTruststoreSuffix,
+				},
+				Key: "truststore_password",
+			},
+		}},
+	}
+}
+
+// reconcileClientTruststore keeps the REST API truststore secret in line with the client CA.
+// The truststore also holds the CA of the REST API certificate: Confluent uses it to forward
+// writes to the leader over HTTPS, whose certificate is signed by that CA. The truststore is
+// rebuilt whenever one of the CA secrets changes, and deleted once client authentication is
+// disabled. It returns the ResourceVersion of the truststore secret,
+// or "" when client authentication is disabled.
+func (r *StrimziSchemaRegistryReconciler) reconcileClientTruststore(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, kafkaClusterName string) (string, error) {
+	truststore := &v1.Secret{}
+	truststore.Name = instance.Name + clientTruststoreSuffix
+	truststore.Namespace = instance.Namespace
+	if !clientAuthEnabled(instance) {
+		return "", r.deleteOwnedNamed(ctx, instance, logger, truststore)
+	}
+
+	caName, caKey := clientCASecretRef(instance, kafkaClusterName)
+	caSecret := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: caName, Namespace: instance.Namespace}, caSecret); err != nil {
+		return "", fmt.Errorf("failed to get client CA secret %s: %w", caName, err)
+	}
+	caCert, ok := caSecret.Data[caKey]
+	if !ok {
+		return "", fmt.Errorf("client CA secret %s has no key %q", caName, caKey)
+	}
+	caVersion := caName + "/" + caSecret.ResourceVersion
+
+	peerCAName := connectionCASecretName(instance, kafkaClusterName)
+	peerCASecret := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: peerCAName, Namespace: instance.Namespace}, peerCASecret); err != nil {
+		return "", fmt.Errorf("failed to get REST API CA secret %s: %w", peerCAName, err)
+	}
+	caVersion += "," + peerCAName + "/" + peerCASecret.ResourceVersion
+
+	err := r.Get(ctx, types.NamespacedName{Name: truststore.Name, Namespace: truststore.Namespace}, truststore)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && truststore.Annotations[clientCAVersionKey] == caVersion {
+		return truststore.ResourceVersion, nil
+	}
+
+	logger.Info("Creating REST API client truststore", "Secret.Name", truststore.Name, "CA", caName)
+	cp := certprocessor.NewCertProcessor(logger)
+	jks, password, err := cp.CreateTruststore(string(caCert)+"\n"+string(peerCASecret.Data["ca.crt"]), "")
+	if err != nil {
+		return "", err
+	}
+	err = r.applyOwned(ctx, instance, logger, truststore, func() {
+		truststore.Labels = map[string]string{
+			"app":  "strimzi-schema-registry",
+			"user": instance.Name,
+		}
+		if truststore.Annotations == nil {
+			truststore.Annotations = map[string]string{}
+		}
+		truststore.Annotations[clientCAVersionKey] = caVersion
+		truststore.Type = v1.SecretTypeOpaque
+		truststore.Data = map[string][]byte{
+			"truststore.jks":      jks,
+			"truststore_password": []byte(password),
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return truststore.ResourceVersion, nil
+}
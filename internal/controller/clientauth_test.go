@@ -0,0 +1,172 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newClientAuthInstance() *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	inst := newTestInstance()
+	inst.UID = types.UID("test-uid")
+	inst.Spec.SecureHTTP = true
+	inst.Spec.TLS = &strimziregistryoperatorv1alpha1.TLSSpec{ClientAuth: strimziregistryoperatorv1alpha1.ClientAuthRequired}
+	return inst
+}
+
+func TestClientAuthPodSpec(t *testing.T) {
+	t.Run("disabled without securehttp", func(t *testing.T) {
+		inst := newClientAuthInstance()
+		inst.Spec.SecureHTTP = false
+		if clientAuthEnabled(inst) || len(clientAuthEnv(inst)) != 0 {
+			t.Error("client authentication must only apply to the HTTPS listener")
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		inst := newClientAuthInstance()
+		env := buildPodEnv(inst, "kafka:9093", "tls")
+		for _, e := range env {
+			if e.Name == "SCHEMA_REGISTRY_SSL_CLIENT_AUTHENTICATION" && e.Value != "REQUIRED" {
+				t.Errorf("unexpected client authentication mode %q", e.Value)
+			}
+		}
+		if !hasEnv(env, "SCHEMA_REGISTRY_SSL_TRUSTSTORE_LOCATION") || !hasEnv(env, "SCHEMA_REGISTRY_SSL_TRUSTSTORE_PASSWORD") {
+			t.Error("expected REST API truststore env vars")
+		}
+		volumes, mounts := buildPodVolumes(inst, "tls", "kafka")
+		last := volumes[len(volumes)-1]
+		if last.Secret == nil || last.Secret.SecretName != "test-sr"+clientTruststoreSuffix ||
+			mounts[len(mounts)-1].MountPath != clientTruststoreMountPath {
+			t.Errorf("expected client truststore volume, got %+v", last)
+		}
+	})
+
+	t.Run("subjects readiness check with requested client certificates uses curl", func(t *testing.T) {
+		inst := newClientAuthInstance()
+		inst.Spec.TLSSecretName = ""
+		inst.Spec.TLS.ClientAuth = strimziregistryoperatorv1alpha1.ClientAuthRequested
+		inst.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects}
+		readiness, _, _ := buildProbes(inst)
+		if readiness.Exec == nil {
+			t.Errorf("expected curl readiness probe, got %+v", readiness.ProbeHandler)
+		}
+	})
+
+	t.Run("spec.tls changes the spec hash", func(t *testing.T) {
+		inst := newClientAuthInstance()
+		required, _ := computeSpecHash(inst)
+		inst.Spec.TLS = nil
+		none, _ := computeSpecHash(inst)
+		if required == none {
+			t.Error("enabling client authentication must change the spec hash")
+		}
+	})
+}
+
+func TestClientCASecretRef(t *testing.T) {
+	inst := newClientAuthInstance()
+	if name, key := clientCASecretRef(inst, "kafka"); name != "kafka-clients-ca-cert" || key != "ca.crt" {
+		t.Errorf("expected the Strimzi clients CA by default, got %s/%s", name, key)
+	}
+	inst.Spec.TLS.ClientCASecretName = "partner-ca"
+	inst.Spec.TLS.ClientCAKey = "ca.pem"
+	if name, key := clientCASecretRef(inst, "kafka"); name != "partner-ca" || key != "ca.pem" {
+		t.Errorf("expected the user CA, got %s/%s", name, key)
+	}
+	if got := referencedSecrets(inst); len(got) != 1 || got[0] != "partner-ca" {
+		t.Errorf("unexpected index value %v", got)
+	}
+}
+
+func TestReconcileClientTruststore(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	inst := newClientAuthInstance()
+	ca := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-clients-ca-cert", Namespace: "default", ResourceVersion: "7"},
+		Data:       map[string][]byte{"ca.crt": []byte("CA-PEM")},
+	}
+	clusterCA := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kafka-cluster-ca-cert", Namespace: "default", ResourceVersion: "3"},
+		Data:       map[string][]byte{"ca.crt": []byte("CLUSTER-CA-PEM")},
+	}
+	truststore := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-sr" + clientTruststoreSuffix, Namespace: "default",
+			Annotations:     map[string]string{clientCAVersionKey: "kafka-clients-ca-cert/7,kafka-cluster-ca-cert/3"},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "strimziregistryoperator.randsw.code/v1alpha1", Kind: "StrimziSchemaRegistry", Name: "test-sr", UID: inst.UID, Controller: ptr.To(true)}},
+		},
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(ca, clusterCA, truststore).Build()
+
+	version, err := r.reconcileClientTruststore(ctx, inst, logr.Discard(), "kafka")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version == "" {
+		t.Fatal("expected the version of the up-to-date truststore")
+	}
+
+	t.Run("a new truststore version rolls the pods", func(t *testing.T) {
+		versions, err := r.podTemplateVersions(ctx, inst)
+		if err != nil || versions[clientTruststoreVersionKey] != version {
+			t.Fatalf("unexpected versions %v (err: %v)", versions, err)
+		}
+		dep := &appsv1.Deployment{}
+		if !applyPodTemplateVersions(dep, versions) || dep.Spec.Template.Annotations[clientTruststoreVersionKey] != version {
+			t.Errorf("expected pod template annotation, got %v", dep.Spec.Template.Annotations)
+		}
+		if applyPodTemplateVersions(dep, versions) {
+			t.Error("applying the same versions twice must not change the template")
+		}
+		if !applyPodTemplateVersions(dep, map[string]string{clientTruststoreVersionKey: ""}) {
+			t.Error("annotation must be removed once client authentication is disabled")
+		}
+	})
+
+	t.Run("a missing CA key is reported", func(t *testing.T) {
+		inst := inst.DeepCopy()
+		inst.Spec.TLS.ClientCAKey = "missing"
+		if _, err := r.reconcileClientTruststore(ctx, inst, logr.Discard(), "kafka"); err == nil {
+			t.Error("expected an error for a missing CA key")
+		}
+	})
+
+	t.Run("disabling client authentication deletes the truststore", func(t *testing.T) {
+		inst := inst.DeepCopy()
+		inst.Spec.TLS = nil
+		if version, err := r.reconcileClientTruststore(ctx, inst, logr.Discard(), "kafka"); err != nil || version != "" {
+			t.Fatalf("unexpected result %q (err: %v)", version, err)
+		}
+		err := r.Get(ctx, types.NamespacedName{Name: truststore.Name, Namespace: "default"}, &corev1.Secret{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("expected truststore to be deleted, got %v", err)
+		}
+	})
+}
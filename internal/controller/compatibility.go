@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// compatibilityCondition reports whether spec.compatibilitylevel is applied to the registry.
+const compatibilityCondition = "CompatibilityLevelApplied"
+
+// desiredCompatibilityLevel returns spec.compatibilitylevel as spelled by the REST API.
+func desiredCompatibilityLevel(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	level := instance.Spec.CompatibilityLevel
+	if level == "" {
+		level = "forward"
+	}
+	return strings.ToUpper(level)
+}
+
+// reconcileGlobalCompatibility applies spec.compatibilitylevel through PUT /config. The
+// SCHEMA_REGISTRY_SCHEMA_COMPATIBILITY_LEVEL env var only seeds the level of a new registry and
+// is overridden by any level set through the REST API, so the level is pushed on every reconcile
+// of a responsive registry and levels changed by hand are reverted. Failures are reported in the
+// CompatibilityLevelApplied condition rather than failing the reconcile. Read-only secondaries
+// are left alone: their level is replicated from the primary with the schemas.
+func (r *StrimziSchemaRegistryReconciler) reconcileGlobalCompatibility(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, rc *registryclient.Client, logger logr.Logger) {
+	if registryReadOnly(instance) {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, compatibilityCondition)
+		return
+	}
+	desired := desiredCompatibilityLevel(instance)
+	status := &instance.Status
+
+	current, err := rc.GetConfig(ctx, "")
+	if err == nil && current.Compatibility != desired {
+		if status.CompatibilityLevel == desired {
+			logger.Info("Reverting global compatibility level changed outside of the operator",
+				"current", current.Compatibility, "desired", desired)
+			now := metav1.Now()
+			status.LastCompatibilityDriftTime = &now
+		} else {
+			logger.Info("Applying global compatibility level", "level", desired)
+		}
+		err = rc.SetConfig(ctx, "", registryclient.CompatibilityConfig{Compatibility: desired})
+	}
+	if err != nil {
+		logger.Error(err, "Failed to apply global compatibility level")
+		reason := "RegistryUnreachable"
+		if registryclient.IsClientError(err) {
+			reason = "Rejected"
+		}
+		setRegistryCondition(instance, compatibilityCondition, metav1.ConditionFalse, reason, err.Error())
+		return
+	}
+	status.CompatibilityLevel = desired
+	setRegistryCondition(instance, compatibilityCondition, metav1.ConditionTrue, "Applied",
+		fmt.Sprintf("global compatibility level is %s", desired))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+func TestReconcileGlobalCompatibility(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	r := newTestReconciler()
+	rc, err := registryclient.New(registryclient.Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	instance := newTestInstance()
+	instance.Spec.CompatibilityLevel = "full_transitive"
+
+	t.Run("level is applied through the REST API", func(t *testing.T) {
+		r.reconcileGlobalCompatibility(ctx, instance, rc, logr.Discard())
+		if level, _ := registry.Compatibility(""); level != "FULL_TRANSITIVE" {
+			t.Errorf("expected FULL_TRANSITIVE, got %q", level)
+		}
+		if instance.Status.CompatibilityLevel != "FULL_TRANSITIVE" || instance.Status.LastCompatibilityDriftTime != nil {
+			t.Errorf("unexpected status %+v", instance.Status)
+		}
+		if !meta.IsStatusConditionTrue(instance.Status.Conditions, compatibilityCondition) {
+			t.Errorf("expected %s condition, got %+v", compatibilityCondition, instance.Status.Conditions)
+		}
+	})
+
+	t.Run("level changed outside of the operator is reverted", func(t *testing.T) {
+		registry.SetCompatibility("", "NONE")
+		r.reconcileGlobalCompatibility(ctx, instance, rc, logr.Discard())
+		if level, _ := registry.Compatibility(""); level != "FULL_TRANSITIVE" {
+			t.Errorf("expected FULL_TRANSITIVE, got %q", level)
+		}
+		if instance.Status.LastCompatibilityDriftTime == nil {
+			t.Error("expected the drift to be reported")
+		}
+	})
+
+	t.Run("spec change is applied without drift", func(t *testing.T) {
+		instance.Status.LastCompatibilityDriftTime = nil
+		instance.Spec.CompatibilityLevel = ""
+		r.reconcileGlobalCompatibility(ctx, instance, rc, logr.Discard())
+		if level, _ := registry.Compatibility(""); level != "FORWARD" {
+			t.Errorf("expected the FORWARD default, got %q", level)
+		}
+		if instance.Status.LastCompatibilityDriftTime != nil {
+			t.Error("a spec change must not be reported as drift")
+		}
+	})
+}
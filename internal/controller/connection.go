@@ -0,0 +1,343 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	certprocessor "github.com/randsw/schema-registry-operator-strimzi/certProcessor"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	connectionSuffix = "-connection"
+	// connectionSourcesKey records, on the connection Secret, which CA and users secret
+	// versions it was built from, so truststores are only rebuilt on rotation.
+	connectionSourcesKey = keyPrefix + "/connectionSources"
+	// Labels identifying the registry a connection ConfigMap/Secret copy belongs to.
+	connectionSourceNamespaceLabel = keyPrefix + "/source-namespace"
+	connectionSourceNameLabel      = keyPrefix + "/source-name"
+)
+
+// ConnectionName returns the name of the connection ConfigMap and Secret of a registry,
+// in its own namespace as well as in the namespaces they are copied to.
+func ConnectionName(registry string) string {
+	return registry + connectionSuffix
+}
+
+// connectionLabels returns the labels of the connection ConfigMap and Secret and their copies.
+func connectionLabels(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "strimzi-registry-operator",
+		connectionSourceNamespaceLabel: instance.Namespace,
+		connectionSourceNameLabel:      instance.Name,
+	}
+}
+
+// buildConnectionConfigMap returns the ConfigMap with the in-cluster URL of the REST API.
+func buildConnectionConfigMap(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) *v1.ConfigMap {
+	listener := listenerStatuses(instance)[0]
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + connectionSuffix,
+			Namespace: instance.Namespace,
+			Labels:    connectionLabels(instance),
+		},
+		Data: map[string]string{
+			"url":    listener.URL,
+			"scheme": listener.Name,
+			"host":   internalHostname(instance),
+			"port":   strconv.Itoa(int(listener.Port)),
+		},
+	}
+}
+
+// connectionCASecretName returns the secret holding the CA that signs the REST API certificate.
+func connectionCASecretName(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaClusterName string) string {
+	if instance.Spec.Connection != nil && instance.Spec.Connection.CASecretName != "" {
+		return instance.Spec.Connection.CASecretName
+	}
+	return kafkaClusterName + clusterCASuffix
+}
+
+// connectionBasicAuthUser returns the user whose credentials are published, if any.
+func connectionBasicAuthUser(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if instance.Spec.Connection == nil || !basicAuthEnabled(instance) {
+		return ""
+	}
+	return instance.Spec.Connection.BasicAuthUser
+}
+
+// connectionSources reads the secrets the connection Secret is derived from: the CA that
+// signs the REST API certificate (SecureHTTP only) and the Basic authentication users
+// secret (spec.connection.basicAuthUser only). It also returns their combined version.
+func (r *StrimziSchemaRegistryReconciler) connectionSources(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaClusterName string) (*v1.Secret, *v1.Secret, string, error) {
+	var caSecret, users *v1.Secret
+	var sources []string
+
+	if instance.Spec.SecureHTTP {
+		caName := connectionCASecretName(instance, kafkaClusterName)
+		caSecret = &v1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: caName, Namespace: instance.Namespace}, caSecret); err != nil {
+			return nil, nil, "", fmt.Errorf("failed to get CA secret %s: %w", caName, err)
+		}
+		sources = append(sources, caName+"/"+caSecret.ResourceVersion)
+	}
+	if user := connectionBasicAuthUser(instance); user != "" {
+		usersName := instance.Spec.Authentication.Basic.UsersSecretName
+		users = &v1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: usersName, Namespace: instance.Namespace}, users); err != nil {
+			return nil, nil, "", fmt.Errorf("failed to get users secret %s: %w", usersName, err)
+		}
+		sources = append(sources, usersName+"/"+users.ResourceVersion+"/"+user)
+	}
+	return caSecret, users, strings.Join(sources, ","), nil
+}
+
+// buildConnectionSecretData builds the connection Secret data: the CA bundle in PEM, JKS and
+// PKCS12 form when caSecret is set, plus the Basic authentication credentials of user.
+func buildConnectionSecretData(logger logr.Logger, caSecret, users *v1.Secret, user string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	if caSecret != nil {
+		caCert, ok := caSecret.Data["ca.crt"]
+		if !ok {
+			return nil, fmt.Errorf("CA secret %s has no key \"ca.crt\"", caSecret.Name)
+		}
+		cp := certprocessor.NewCertProcessor(logger)
+		jks, password, err := cp.CreateTruststore(string(caCert), "")
+		if err != nil {
+			return nil, err
+		}
+		p12, _, err := cp.CreatePKCS12Truststore(string(caCert), password)
+		if err != nil {
+			return nil, err
+		}
+		data["ca.crt"] = caCert
+		data["truststore.jks"] = jks
+		data["truststore.p12"] = p12
+		data["truststore.password"] = []byte(password)
+	}
+	if users != nil {
+		entry, ok := users.Data[user]
+		if !ok {
+			return nil, fmt.Errorf("user %q not found in users secret %s", user, users.Name)
+		}
+		password := basicAuthPassword(entry)
+		data["username"] = []byte(user)
+		data["password"] = []byte(password)
+		data["basic.auth.user.info"] = []byte(user + ":" + password)
+	}
+	return data, nil
+}
+
+// reconcileConnection publishes the "<name>-connection" ConfigMap (URL) and Secret (CA bundle,
+// credentials) for application teams, and copies them into the namespaces matching
+// spec.connection.namespaceSelector. Labelled KafkaConnect and KafkaBridge resources are then
+// wired to the registry.
+func (r *StrimziSchemaRegistryReconciler) reconcileConnection(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, kafkaClusterName string) error {
+	desired := buildConnectionConfigMap(instance)
+	configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+	err := r.applyOwned(ctx, instance, logger, configMap, func() {
+		configMap.Labels = desired.Labels
+		configMap.Data = desired.Data
+	})
+	if err != nil {
+		return err
+	}
+
+	caSecret, users, version, err := r.connectionSources(ctx, instance, kafkaClusterName)
+	if err != nil {
+		return err
+	}
+	secret := &v1.Secret{}
+	err = r.Get(ctx, types.NamespacedName{Name: instance.Name + connectionSuffix, Namespace: instance.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	// Truststores are only rebuilt when a source secret changed.
+	if errors.IsNotFound(err) || secret.Annotations[connectionSourcesKey] != version {
+		data, err := buildConnectionSecretData(logger, caSecret, users, connectionBasicAuthUser(instance))
+		if err != nil {
+			return err
+		}
+		secret = &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + connectionSuffix, Namespace: instance.Namespace}}
+		err = r.applyOwned(ctx, instance, logger, secret, func() {
+			secret.Labels = connectionLabels(instance)
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[connectionSourcesKey] = version
+			secret.Type = v1.SecretTypeOpaque
+			secret.Data = data
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := r.reconcileConnectionCopies(ctx, instance, logger, configMap, secret); err != nil {
+		return err
+	}
+	return r.reconcileKafkaClients(ctx, instance, logger, configMap, secret)
+}
+
+// reconcileConnectionCopies keeps copies of the connection ConfigMap and Secret in the
+// namespaces matching spec.connection.namespaceSelector and removes the other copies.
+// Objects of the same name not created by the operator are left alone.
+func (r *StrimziSchemaRegistryReconciler) reconcileConnectionCopies(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger,
+	configMap *v1.ConfigMap, secret *v1.Secret) error {
+	targets := map[string]bool{}
+	if instance.Spec.Connection != nil && instance.Spec.Connection.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(instance.Spec.Connection.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid connection.namespaceSelector: %w", err)
+		}
+		namespaces := &v1.NamespaceList{}
+		if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return err
+		}
+		for _, ns := range namespaces.Items {
+			if ns.Name != instance.Namespace && ns.DeletionTimestamp == nil {
+				targets[ns.Name] = true
+			}
+		}
+	}
+
+	for namespace := range targets {
+		configMapCopy := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configMap.Name, Namespace: namespace}}
+		if err := r.applyConnectionCopy(ctx, instance, logger, configMapCopy, func() {
+			configMapCopy.Data = maps.Clone(configMap.Data)
+		}); err != nil {
+			return err
+		}
+		secretCopy := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: namespace}}
+		if err := r.applyConnectionCopy(ctx, instance, logger, secretCopy, func() {
+			secretCopy.Type = v1.SecretTypeOpaque
+			secretCopy.Data = maps.Clone(secret.Data)
+		}); err != nil {
+			return err
+		}
+	}
+	return r.deleteConnectionCopies(ctx, instance, logger, targets)
+}
+
+// applyConnectionCopy creates or updates a copy in another namespace. Copies carry no owner
+// reference (those cannot cross namespaces); the source labels mark them as operator-managed.
+func (r *StrimziSchemaRegistryReconciler) applyConnectionCopy(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, obj client.Object, mutate func()) error {
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, obj)
+	if err == nil && !isConnectionCopyOf(obj, instance) {
+		logger.Info("Skipping connection copy, an object of the same name already exists",
+			"Kind", fmt.Sprintf("%T", obj), "Name", obj.GetName(), "Namespace", obj.GetNamespace())
+		return nil
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		maps.Copy(labels, connectionLabels(instance))
+		obj.SetLabels(labels)
+		mutate()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		logger.Info("Connection copy reconciled", "Kind", fmt.Sprintf("%T", obj), "Namespace", obj.GetNamespace(), "Operation", op)
+	}
+	return nil
+}
+
+// isConnectionCopyOf reports whether obj is a connection copy of the registry.
+func isConnectionCopyOf(obj client.Object, instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	labels := obj.GetLabels()
+	return labels[connectionSourceNamespaceLabel] == instance.Namespace && labels[connectionSourceNameLabel] == instance.Name
+}
+
+// deleteConnectionCopies removes the connection copies outside of the keep namespaces.
+// The ConfigMap and Secret in the registry namespace are owned by the CR and never touched.
+func (r *StrimziSchemaRegistryReconciler) deleteConnectionCopies(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, keep map[string]bool) error {
+	selector := client.MatchingLabels{
+		connectionSourceNamespaceLabel: instance.Namespace,
+		connectionSourceNameLabel:      instance.Name,
+	}
+	var copies []client.Object
+	configMaps := &v1.ConfigMapList{}
+	if err := r.List(ctx, configMaps, selector); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		copies = append(copies, &configMaps.Items[i])
+	}
+	secrets := &v1.SecretList{}
+	if err := r.List(ctx, secrets, selector); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		copies = append(copies, &secrets.Items[i])
+	}
+
+	for _, obj := range copies {
+		if obj.GetNamespace() == instance.Namespace || keep[obj.GetNamespace()] {
+			continue
+		}
+		logger.Info("Deleting connection copy", "Kind", fmt.Sprintf("%T", obj), "Namespace", obj.GetNamespace())
+		if err := client.IgnoreNotFound(r.Delete(ctx, obj)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestsForNamespace returns the CRs copying their connection settings by namespace selector.
+// They are all reconciled, as a namespace may have started or stopped matching.
+func (r *StrimziSchemaRegistryReconciler) requestsForNamespace(ctx context.Context, _ client.Object) []reconcile.Request {
+	list := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryList{}
+	if err := r.List(ctx, list); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, item := range list.Items {
+		if item.Spec.Connection != nil && item.Spec.Connection.NamespaceSelector != nil {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace},
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestBuildConnectionConfigMap(t *testing.T) {
+	inst := newTestInstance()
+	inst.Spec.SecureHTTP = true
+	cm := buildConnectionConfigMap(inst)
+	want := map[string]string{"url": "https://test-sr.default.svc:443", "scheme": "https", "host": "test-sr.default.svc", "port": "443"}
+	for key, value := range want {
+		if cm.Data[key] != value {
+			t.Errorf("expected %s=%q, got %q", key, value, cm.Data[key])
+		}
+	}
+	if cm.Name != "test-sr"+connectionSuffix || !isConnectionCopyOf(cm, inst) {
+		t.Errorf("unexpected metadata %+v", cm.ObjectMeta)
+	}
+}
+
+func TestReconcileConnection(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	inst := newBasicAuthInstance()
+	inst.Spec.Connection = &strimziregistryoperatorv1alpha1.ConnectionSpec{
+		BasicAuthUser:     "ci",
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"schema-registry": "enabled"}},
+	}
+	users := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-users", Namespace: "default"},
+		Data:       map[string][]byte{"ci": []byte("ci-pw,developer")},
+	}
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test-sr" + connectionSuffix, Namespace: "team-c"},
+		Data: map[string]string{"url": "mine"}}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(users, foreign,
+		namespace("default", map[string]string{"schema-registry": "enabled"}),
+		namespace("team-a", map[string]string{"schema-registry": "enabled"}),
+		namespace("team-b", nil),
+		namespace("team-c", map[string]string{"schema-registry": "enabled"}),
+	).Build()
+
+	if err := r.reconcileConnection(ctx, inst, logr.Discard(), "kafka"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "test-sr" + connectionSuffix, Namespace: "default"}, secret); err != nil {
+		t.Fatalf("expected connection secret: %v", err)
+	}
+	if string(secret.Data["basic.auth.user.info"]) != "ci:ci-pw" || string(secret.Data["password"]) != "ci-pw" {
+		t.Errorf("unexpected credentials %v", secret.Data)
+	}
+	if _, ok := secret.Data["ca.crt"]; ok {
+		t.Error("plain HTTP must not publish a CA bundle")
+	}
+	if !metav1.IsControlledBy(secret, inst) {
+		t.Error("connection secret must be owned by the CR")
+	}
+
+	t.Run("copies follow the namespace selector", func(t *testing.T) {
+		copied := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: "team-a"}, copied); err != nil {
+			t.Fatalf("expected copy in team-a: %v", err)
+		}
+		if string(copied.Data["username"]) != "ci" || len(copied.OwnerReferences) != 0 {
+			t.Errorf("unexpected copy %+v", copied)
+		}
+		if err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: "team-b"}, &corev1.Secret{}); !errors.IsNotFound(err) {
+			t.Errorf("team-b does not match the selector, got %v", err)
+		}
+		cm := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Name: foreign.Name, Namespace: "team-c"}, cm); err != nil || cm.Data["url"] != "mine" {
+			t.Errorf("a ConfigMap not created by the operator must be left alone, got %+v (err: %v)", cm.Data, err)
+		}
+	})
+
+	t.Run("a namespace that stops matching loses its copies", func(t *testing.T) {
+		ns := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: "team-a"}, ns); err != nil {
+			t.Fatal(err)
+		}
+		ns.Labels = nil
+		if err := r.Update(ctx, ns); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.reconcileConnection(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: "team-a"}, &corev1.Secret{}); !errors.IsNotFound(err) {
+			t.Errorf("expected copy to be deleted, got %v", err)
+		}
+	})
+
+	t.Run("finalizer removes all copies", func(t *testing.T) {
+		if err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: "team-c"}, &corev1.Secret{}); err != nil {
+			t.Fatalf("expected copy in team-c: %v", err)
+		}
+		if err := r.finalizeApplication(ctx, inst, logr.Discard()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		secrets := &corev1.SecretList{}
+		if err := r.List(ctx, secrets); err != nil {
+			t.Fatal(err)
+		}
+		for _, s := range secrets.Items {
+			if s.Namespace != "default" && isConnectionCopyOf(&s, inst) {
+				t.Errorf("copy left in %s", s.Namespace)
+			}
+		}
+		if err := r.Get(ctx, types.NamespacedName{Name: foreign.Name, Namespace: "team-c"}, &corev1.ConfigMap{}); err != nil {
+			t.Errorf("a ConfigMap not created by the operator must be kept: %v", err)
+		}
+		if err := r.Get(ctx, types.NamespacedName{Name: secret.Name, Namespace: "default"}, &corev1.Secret{}); err != nil {
+			t.Errorf("the owned secret is garbage collected, not deleted by the finalizer: %v", err)
+		}
+	})
+
+	t.Run("an unknown user is reported", func(t *testing.T) {
+		inst := inst.DeepCopy()
+		inst.Spec.Connection.BasicAuthUser = "nobody"
+		if err := r.reconcileConnection(ctx, inst, logr.Discard(), "kafka"); err == nil {
+			t.Error("expected an error for an unknown user")
+		}
+	})
+}
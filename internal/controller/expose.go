@@ -0,0 +1,571 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	kafka "github.com/scholzj/strimzi-go/pkg/apis/kafka.strimzi.io/v1"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// exposedCondition reports whether the resource requested in spec.expose is in place.
+	exposedCondition = "Exposed"
+	// backendCASuffix names the ConfigMap holding the cluster CA for BackendTLSPolicy.
+	backendCASuffix = "-backend-ca"
+	// Annotations recording which labels/annotations the operator set on a generated resource,
+	// so that the ones dropped from spec.expose are removed without touching third-party ones.
+	exposeManagedAnnotationsKey = keyPrefix + "/exposeManagedAnnotations"
+	exposeManagedLabelsKey      = keyPrefix + "/exposeManagedLabels"
+)
+
+// routeGVK is the OpenShift Route kind. Routes are handled as unstructured objects so the
+// operator does not depend on the OpenShift API module.
+var routeGVK = schema.GroupVersionKind{Group: "route.openshift.io", Version: "v1", Kind: "Route"}
+
+// AvailableAPIs records which optional APIs are served by the cluster. It is detected
+// once at startup; resources of a missing API are neither watched nor reconciled.
+type AvailableAPIs struct {
+	Ingress          bool
+	HTTPRoute        bool
+	TLSRoute         bool
+	BackendTLSPolicy bool
+	Route            bool
+	KafkaConnect     bool
+	KafkaBridge      bool
+}
+
+// DetectAvailableAPIs queries the discovery API for the optional resources spec.expose can generate
+// and the Strimzi clients the registry connection can be wired into.
+func DetectAvailableAPIs(dc discovery.DiscoveryInterface) (AvailableAPIs, error) {
+	var apis AvailableAPIs
+	checks := []struct {
+		groupVersion string
+		resource     string
+		found        *bool
+	}{
+		{"networking.k8s.io/v1", "ingresses", &apis.Ingress},
+		{gatewayv1.GroupVersion.String(), "httproutes", &apis.HTTPRoute},
+		{gatewayv1.GroupVersion.String(), "tlsroutes", &apis.TLSRoute},
+		{gatewayv1.GroupVersion.String(), "backendtlspolicies", &apis.BackendTLSPolicy},
+		{routeGVK.GroupVersion().String(), "routes", &apis.Route},
+		{kafka.SchemeGroupVersion.String(), "kafkaconnects", &apis.KafkaConnect},
+		{kafka.SchemeGroupVersion.String(), "kafkabridges", &apis.KafkaBridge},
+	}
+	for _, check := range checks {
+		resources, err := dc.ServerResourcesForGroupVersion(check.groupVersion)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return apis, fmt.Errorf("failed to discover %s: %w", check.groupVersion, err)
+		}
+		for _, res := range resources.APIResources {
+			if res.Name == check.resource {
+				*check.found = true
+				break
+			}
+		}
+	}
+	return apis, nil
+}
+
+// exposeTermination returns the TLS termination to use, or "" for plain HTTP.
+func exposeTermination(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) strimziregistryoperatorv1alpha1.TLSTermination {
+	if !instance.Spec.SecureHTTP {
+		return ""
+	}
+	if instance.Spec.Expose.TLSTermination == "" {
+		return strimziregistryoperatorv1alpha1.TLSTerminationPassthrough
+	}
+	return instance.Spec.Expose.TLSTermination
+}
+
+// internalHostname is the in-cluster host name the registry certificate is issued for.
+func internalHostname(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	return instance.Name + "." + instance.Namespace + ".svc"
+}
+
+// exposeAnnotations merges the operator annotations with the ones from spec.expose.
+// User annotations win so that controller-specific settings can be overridden.
+func exposeAnnotations(managed map[string]string, instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) map[string]string {
+	annotations := map[string]string{}
+	maps.Copy(annotations, managed)
+	maps.Copy(annotations, instance.Spec.Expose.Annotations)
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// buildIngress builds the Ingress for spec.expose. TLS passthrough and re-encrypt are
+// configured with ingress-nginx annotations.
+func buildIngress(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaClusterName string) *networkingv1.Ingress {
+	expose := instance.Spec.Expose
+	portName, _ := servicePort(instance)
+	managed := map[string]string{}
+	switch exposeTermination(instance) {
+	case strimziregistryoperatorv1alpha1.TLSTerminationPassthrough:
+		managed["nginx.ingress.kubernetes.io/ssl-passthrough"] = "true"
+		managed["nginx.ingress.kubernetes.io/backend-protocol"] = "HTTPS"
+	case strimziregistryoperatorv1alpha1.TLSTerminationReencrypt:
+		managed["nginx.ingress.kubernetes.io/backend-protocol"] = "HTTPS"
+		managed["nginx.ingress.kubernetes.io/proxy-ssl-secret"] = instance.Namespace + "/" + connectionCASecretName(instance, kafkaClusterName)
+		managed["nginx.ingress.kubernetes.io/proxy-ssl-verify"] = "on"
+		managed["nginx.ingress.kubernetes.io/proxy-ssl-name"] = internalHostname(instance)
+	}
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instance.Name,
+			Namespace:   instance.Namespace,
+			Labels:      podSelectorLabels(instance.Name),
+			Annotations: exposeAnnotations(managed, instance),
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: expose.IngressClassName,
+			Rules: []networkingv1.IngressRule{{
+				Host: expose.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: ptr.To(networkingv1.PathTypePrefix),
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: instance.Name,
+									Port: networkingv1.ServiceBackendPort{Name: portName},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if expose.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{expose.Host}, SecretName: expose.TLSSecretName}}
+	}
+	return ingress
+}
+
+// gatewayParentRefs converts spec.expose.gateway into a Gateway API parent reference.
+func gatewayParentRefs(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) []gatewayv1.ParentReference {
+	gw := instance.Spec.Expose.Gateway
+	ref := gatewayv1.ParentReference{Name: gatewayv1.ObjectName(gw.Name)}
+	if gw.Namespace != "" {
+		ref.Namespace = ptr.To(gatewayv1.Namespace(gw.Namespace))
+	}
+	if gw.SectionName != "" {
+		ref.SectionName = ptr.To(gatewayv1.SectionName(gw.SectionName))
+	}
+	return []gatewayv1.ParentReference{ref}
+}
+
+// serviceBackendRef points a Gateway API route at the Schema Registry service.
+func serviceBackendRef(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) gatewayv1.BackendRef {
+	_, port := servicePort(instance)
+	return gatewayv1.BackendRef{
+		BackendObjectReference: gatewayv1.BackendObjectReference{
+			Name: gatewayv1.ObjectName(instance.Name),
+			Port: ptr.To(gatewayv1.PortNumber(port)),
+		},
+	}
+}
+
+// buildHTTPRoute builds the HTTPRoute used for plain HTTP and TLS re-encrypt.
+func buildHTTPRoute(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) *gatewayv1.HTTPRoute {
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instance.Name,
+			Namespace:   instance.Namespace,
+			Labels:      podSelectorLabels(instance.Name),
+			Annotations: exposeAnnotations(nil, instance),
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: gatewayParentRefs(instance)},
+			Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(instance.Spec.Expose.Host)},
+			Rules: []gatewayv1.HTTPRouteRule{{
+				BackendRefs: []gatewayv1.HTTPBackendRef{{BackendRef: serviceBackendRef(instance)}},
+			}},
+		},
+	}
+}
+
+// buildTLSRoute builds the TLSRoute used for TLS passthrough through a Gateway.
+func buildTLSRoute(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) *gatewayv1.TLSRoute {
+	return &gatewayv1.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        instance.Name,
+			Namespace:   instance.Namespace,
+			Labels:      podSelectorLabels(instance.Name),
+			Annotations: exposeAnnotations(nil, instance),
+		},
+		Spec: gatewayv1.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: gatewayParentRefs(instance)},
+			Hostnames:       []gatewayv1.Hostname{gatewayv1.Hostname(instance.Spec.Expose.Host)},
+			Rules: []gatewayv1.TLSRouteRule{{
+				BackendRefs: []gatewayv1.BackendRef{serviceBackendRef(instance)},
+			}},
+		},
+	}
+}
+
+// buildBackendTLSPolicy makes the Gateway verify the registry certificate against the
+// cluster CA copied into the backend CA ConfigMap.
+func buildBackendTLSPolicy(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) *gatewayv1.BackendTLSPolicy {
+	return &gatewayv1.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
+			Labels:    podSelectorLabels(instance.Name),
+		},
+		Spec: gatewayv1.BackendTLSPolicySpec{
+			TargetRefs: []gatewayv1.LocalPolicyTargetReferenceWithSectionName{{
+				LocalPolicyTargetReference: gatewayv1.LocalPolicyTargetReference{
+					Kind: "Service",
+					Name: gatewayv1.ObjectName(instance.Name),
+				},
+			}},
+			Validation: gatewayv1.BackendTLSPolicyValidation{
+				CACertificateRefs: []gatewayv1.LocalObjectReference{{
+					Kind: "ConfigMap",
+					Name: gatewayv1.ObjectName(instance.Name + backendCASuffix),
+				}},
+				Hostname: gatewayv1.PreciseHostname(internalHostname(instance)),
+			},
+		},
+	}
+}
+
+// buildBackendCAConfigMap copies the cluster CA certificate into a ConfigMap, the
+// CA reference kind every Gateway API implementation supports.
+func buildBackendCAConfigMap(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, caCert string) *v1.ConfigMap {
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      instance.Name + backendCASuffix,
+			Namespace: instance.Namespace,
+			Labels:    podSelectorLabels(instance.Name),
+		},
+		Data: map[string]string{"ca.crt": caCert},
+	}
+}
+
+// buildRoute builds the OpenShift Route for spec.expose.
+func buildRoute(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, caCert string) *unstructured.Unstructured {
+	portName, _ := servicePort(instance)
+	spec := map[string]any{
+		"to": map[string]any{
+			"kind":   "Service",
+			"name":   instance.Name,
+			"weight": int64(100),
+		},
+		"port": map[string]any{"targetPort": portName},
+	}
+	if instance.Spec.Expose.Host != "" {
+		spec["host"] = instance.Spec.Expose.Host
+	}
+	switch exposeTermination(instance) {
+	case strimziregistryoperatorv1alpha1.TLSTerminationPassthrough:
+		spec["tls"] = map[string]any{
+			"termination":                   "passthrough",
+			"insecureEdgeTerminationPolicy": "Redirect",
+		}
+	case strimziregistryoperatorv1alpha1.TLSTerminationReencrypt:
+		spec["tls"] = map[string]any{
+			"termination":                   "reencrypt",
+			"insecureEdgeTerminationPolicy": "Redirect",
+			"destinationCACertificate":      caCert,
+		}
+	}
+
+	route := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	route.SetGroupVersionKind(routeGVK)
+	route.SetName(instance.Name)
+	route.SetNamespace(instance.Namespace)
+	route.SetLabels(podSelectorLabels(instance.Name))
+	route.SetAnnotations(exposeAnnotations(nil, instance))
+	return route
+}
+
+// routeOwnedFields are the Route spec fields set by the operator. The router defaults
+// spec.host (and wildcardPolicy) when expose.host is not set; those are left alone.
+var routeOwnedFields = []string{"to", "port", "tls"}
+
+// mergeRouteSpec sets the owned fields of the desired Route spec on route, keeping the
+// host generated by the router unless expose.host pins one.
+func mergeRouteSpec(route, desired *unstructured.Unstructured) {
+	spec, ok := route.Object["spec"].(map[string]any)
+	if !ok {
+		spec = map[string]any{}
+	}
+	desiredSpec, _ := desired.Object["spec"].(map[string]any)
+	for _, field := range routeOwnedFields {
+		if value, ok := desiredSpec[field]; ok {
+			spec[field] = value
+		} else {
+			delete(spec, field)
+		}
+	}
+	if host, ok := desiredSpec["host"]; ok {
+		spec["host"] = host
+	}
+	route.Object["spec"] = spec
+}
+
+// mergeExposeMetadata merges the desired labels and annotations into obj. Labels and
+// annotations added by others (cert-manager, the OpenShift router, ...) are kept.
+func mergeExposeMetadata(obj, desired metav1.Object) {
+	labels, annotations := obj.GetLabels(), obj.GetAnnotations()
+	mergeManagedMetadata(&labels, &annotations, exposeManagedLabelsKey, desired.GetLabels())
+	mergeManagedMetadata(&annotations, &annotations, exposeManagedAnnotationsKey, desired.GetAnnotations())
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+}
+
+// newRoute returns an empty Route object usable with the controller-runtime client.
+func newRoute() *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(routeGVK)
+	return route
+}
+
+// reconcileExpose creates, updates and removes the resources generated for spec.expose.
+// Only kinds whose API is served by the cluster are touched. The Exposed condition
+// reports the outcome and is removed when spec.expose is not set.
+func (r *StrimziSchemaRegistryReconciler) reconcileExpose(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, kafkaClusterName string) error {
+	expose := instance.Spec.Expose
+	var exposeType strimziregistryoperatorv1alpha1.ExposeType
+	if expose != nil {
+		exposeType = expose.Type
+	}
+	termination := strimziregistryoperatorv1alpha1.TLSTermination("")
+	if expose != nil {
+		termination = exposeTermination(instance)
+	}
+	reencrypt := termination == strimziregistryoperatorv1alpha1.TLSTerminationReencrypt
+	passthrough := termination == strimziregistryoperatorv1alpha1.TLSTerminationPassthrough
+
+	wantIngress := exposeType == strimziregistryoperatorv1alpha1.ExposeIngress
+	wantRoute := exposeType == strimziregistryoperatorv1alpha1.ExposeRoute
+	wantTLSRoute := exposeType == strimziregistryoperatorv1alpha1.ExposeHTTPRoute && passthrough
+	wantHTTPRoute := exposeType == strimziregistryoperatorv1alpha1.ExposeHTTPRoute && !passthrough
+	wantBackendTLS := exposeType == strimziregistryoperatorv1alpha1.ExposeHTTPRoute && reencrypt
+
+	if expose != nil {
+		if missing := r.missingExposeAPI(wantIngress, wantHTTPRoute, wantTLSRoute, wantBackendTLS, wantRoute); missing != "" {
+			logger.Info("Requested expose type is not available in the cluster", "API", missing)
+			meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+				Type:    exposedCondition,
+				Status:  metav1.ConditionFalse,
+				Reason:  "APINotAvailable",
+				Message: fmt.Sprintf("%s is not served by the cluster (detected at operator startup)", missing),
+			})
+			return nil
+		}
+	}
+
+	caCert := ""
+	if reencrypt && (wantRoute || wantBackendTLS) {
+		caSecret := &v1.Secret{}
+		caName := connectionCASecretName(instance, kafkaClusterName)
+		err := r.Get(ctx, types.NamespacedName{Name: caName, Namespace: instance.Namespace}, caSecret)
+		if err != nil {
+			return fmt.Errorf("failed to get CA secret %s for TLS re-encrypt: %w", caName, err)
+		}
+		caCert = string(caSecret.Data["ca.crt"])
+	}
+
+	if r.AvailableAPIs.Ingress {
+		if wantIngress {
+			desired := buildIngress(instance, kafkaClusterName)
+			ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+			if err := r.applyOwned(ctx, instance, logger, ingress, func() {
+				mergeExposeMetadata(ingress, desired)
+				ingress.Spec = desired.Spec
+			}); err != nil {
+				return err
+			}
+		} else if err := r.deleteOwned(ctx, instance, logger, &networkingv1.Ingress{}); err != nil {
+			return err
+		}
+	}
+
+	if r.AvailableAPIs.HTTPRoute {
+		if wantHTTPRoute {
+			desired := buildHTTPRoute(instance)
+			route := &gatewayv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+			if err := r.applyOwned(ctx, instance, logger, route, func() {
+				mergeExposeMetadata(route, desired)
+				route.Spec = desired.Spec
+			}); err != nil {
+				return err
+			}
+		} else if err := r.deleteOwned(ctx, instance, logger, &gatewayv1.HTTPRoute{}); err != nil {
+			return err
+		}
+	}
+
+	if r.AvailableAPIs.TLSRoute {
+		if wantTLSRoute {
+			desired := buildTLSRoute(instance)
+			route := &gatewayv1.TLSRoute{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+			if err := r.applyOwned(ctx, instance, logger, route, func() {
+				mergeExposeMetadata(route, desired)
+				route.Spec = desired.Spec
+			}); err != nil {
+				return err
+			}
+		} else if err := r.deleteOwned(ctx, instance, logger, &gatewayv1.TLSRoute{}); err != nil {
+			return err
+		}
+	}
+
+	if r.AvailableAPIs.BackendTLSPolicy {
+		caConfigMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + backendCASuffix, Namespace: instance.Namespace}}
+		if wantBackendTLS {
+			desiredCA := buildBackendCAConfigMap(instance, caCert)
+			if err := r.applyOwned(ctx, instance, logger, caConfigMap, func() {
+				mergeExposeMetadata(caConfigMap, desiredCA)
+				caConfigMap.Data = desiredCA.Data
+			}); err != nil {
+				return err
+			}
+			desired := buildBackendTLSPolicy(instance)
+			policy := &gatewayv1.BackendTLSPolicy{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+			if err := r.applyOwned(ctx, instance, logger, policy, func() {
+				mergeExposeMetadata(policy, desired)
+				policy.Spec = desired.Spec
+			}); err != nil {
+				return err
+			}
+		} else {
+			if err := r.deleteOwned(ctx, instance, logger, &gatewayv1.BackendTLSPolicy{}); err != nil {
+				return err
+			}
+			if err := r.deleteOwnedNamed(ctx, instance, logger, caConfigMap); err != nil {
+				return err
+			}
+		}
+	}
+
+	if r.AvailableAPIs.Route {
+		if wantRoute {
+			desired := buildRoute(instance, caCert)
+			route := newRoute()
+			route.SetName(desired.GetName())
+			route.SetNamespace(desired.GetNamespace())
+			if err := r.applyOwned(ctx, instance, logger, route, func() {
+				mergeExposeMetadata(route, desired)
+				mergeRouteSpec(route, desired)
+			}); err != nil {
+				return err
+			}
+		} else if err := r.deleteOwned(ctx, instance, logger, newRoute()); err != nil {
+			return err
+		}
+	}
+
+	if expose == nil {
+		meta.RemoveStatusCondition(&instance.Status.Conditions, exposedCondition)
+		return nil
+	}
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:    exposedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  "Reconciled",
+		Message: fmt.Sprintf("Schema Registry is exposed through %s", exposeType),
+	})
+	return nil
+}
+
+// missingExposeAPI returns the name of the first wanted API that is not available.
+func (r *StrimziSchemaRegistryReconciler) missingExposeAPI(ingress, httpRoute, tlsRoute, backendTLS, route bool) string {
+	switch {
+	case ingress && !r.AvailableAPIs.Ingress:
+		return "networking.k8s.io/v1 Ingress"
+	case httpRoute && !r.AvailableAPIs.HTTPRoute:
+		return "gateway.networking.k8s.io/v1 HTTPRoute"
+	case tlsRoute && !r.AvailableAPIs.TLSRoute:
+		return "gateway.networking.k8s.io/v1 TLSRoute"
+	case backendTLS && !r.AvailableAPIs.BackendTLSPolicy:
+		return "gateway.networking.k8s.io/v1 BackendTLSPolicy"
+	case route && !r.AvailableAPIs.Route:
+		return "route.openshift.io/v1 Route"
+	}
+	return ""
+}
+
+// applyOwned creates or updates obj with the given mutation and sets the CR as its controller.
+func (r *StrimziSchemaRegistryReconciler) applyOwned(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, obj client.Object, mutate func()) error {
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		mutate()
+		return ctrl.SetControllerReference(instance, obj, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if op != controllerutil.OperationResultNone {
+		logger.Info("Owned resource reconciled", "Kind", fmt.Sprintf("%T", obj), "Name", obj.GetName(), "Operation", op)
+	}
+	return nil
+}
+
+// deleteOwned removes the object named after the CR when it is controlled by the CR.
+func (r *StrimziSchemaRegistryReconciler) deleteOwned(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, obj client.Object) error {
+	obj.SetName(instance.Name)
+	obj.SetNamespace(instance.Namespace)
+	return r.deleteOwnedNamed(ctx, instance, logger, obj)
+}
+
+// deleteOwnedNamed removes obj (looked up by its name and namespace) when it is controlled by the CR.
+func (r *StrimziSchemaRegistryReconciler) deleteOwnedNamed(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger, obj client.Object) error {
+	err := r.Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, obj)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(obj, instance) {
+		return nil
+	}
+	logger.Info("Deleting resource no longer requested by the spec", "Kind", fmt.Sprintf("%T", obj), "Name", obj.GetName())
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	discoveryfake "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func newExposeInstance(exposeType strimziregistryoperatorv1alpha1.ExposeType) *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	inst := newTestInstance()
+	inst.Spec.Expose = &strimziregistryoperatorv1alpha1.ExposeSpec{
+		Type:    exposeType,
+		Host:    "registry.example.com",
+		Gateway: &strimziregistryoperatorv1alpha1.GatewayReference{Name: "public", Namespace: "gateways"},
+	}
+	return inst
+}
+
+func TestDetectAvailableAPIs(t *testing.T) {
+	dc := &discoveryfake.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	dc.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "ingresses"}}},
+		{GroupVersion: "gateway.networking.k8s.io/v1", APIResources: []metav1.APIResource{{Name: "httproutes"}, {Name: "gateways"}}},
+	}
+	apis, err := DetectAvailableAPIs(dc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := AvailableAPIs{Ingress: true, HTTPRoute: true}
+	if apis != want {
+		t.Errorf("expected %+v, got %+v", want, apis)
+	}
+}
+
+func TestBuildIngress(t *testing.T) {
+	t.Run("plain HTTP", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeIngress)
+		inst.Spec.Expose.Annotations = map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+		ingress := buildIngress(inst, "kafka")
+		backend := ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service
+		if ingress.Spec.Rules[0].Host != "registry.example.com" || backend.Name != "test-sr" || backend.Port.Name != "http" {
+			t.Errorf("unexpected ingress rule: %+v", ingress.Spec.Rules[0])
+		}
+		if _, ok := ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"]; ok {
+			t.Error("plain HTTP ingress must not set backend-protocol")
+		}
+		if ingress.Annotations["cert-manager.io/cluster-issuer"] != "letsencrypt" {
+			t.Error("user annotations must be kept")
+		}
+	})
+
+	t.Run("passthrough", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeIngress)
+		inst.Spec.SecureHTTP = true
+		ingress := buildIngress(inst, "kafka")
+		if ingress.Annotations["nginx.ingress.kubernetes.io/ssl-passthrough"] != "true" {
+			t.Errorf("expected ssl-passthrough annotation, got %+v", ingress.Annotations)
+		}
+		if ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Name != "https" {
+			t.Error("expected https service port")
+		}
+	})
+
+	t.Run("re-encrypt verifies against the cluster CA", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeIngress)
+		inst.Spec.SecureHTTP = true
+		inst.Spec.Expose.TLSTermination = strimziregistryoperatorv1alpha1.TLSTerminationReencrypt
+		inst.Spec.Expose.TLSSecretName = "registry-example-com"
+		ingress := buildIngress(inst, "kafka")
+		if ingress.Annotations["nginx.ingress.kubernetes.io/proxy-ssl-secret"] != "default/kafka-cluster-ca-cert" ||
+			ingress.Annotations["nginx.ingress.kubernetes.io/proxy-ssl-name"] != "test-sr.default.svc" {
+			t.Errorf("unexpected re-encrypt annotations: %+v", ingress.Annotations)
+		}
+		if len(ingress.Spec.TLS) != 1 || ingress.Spec.TLS[0].SecretName != "registry-example-com" {
+			t.Errorf("expected edge TLS section, got %+v", ingress.Spec.TLS)
+		}
+
+		inst.Spec.Connection = &strimziregistryoperatorv1alpha1.ConnectionSpec{CASecretName: "registry-ca"}
+		ingress = buildIngress(inst, "kafka")
+		if ingress.Annotations["nginx.ingress.kubernetes.io/proxy-ssl-secret"] != "default/registry-ca" {
+			t.Errorf("expected the connection CA, got %+v", ingress.Annotations)
+		}
+	})
+}
+
+func TestBuildRoute(t *testing.T) {
+	inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeRoute)
+	inst.Spec.SecureHTTP = true
+	inst.Spec.Expose.TLSTermination = strimziregistryoperatorv1alpha1.TLSTerminationReencrypt
+	route := buildRoute(inst, "CA-PEM")
+	spec := route.Object["spec"].(map[string]any)
+	tls := spec["tls"].(map[string]any)
+	if tls["termination"] != "reencrypt" || tls["destinationCACertificate"] != "CA-PEM" {
+		t.Errorf("unexpected route TLS: %+v", tls)
+	}
+	if spec["host"] != "registry.example.com" || route.GetKind() != "Route" {
+		t.Errorf("unexpected route: %+v", route.Object)
+	}
+}
+
+func TestReconcileExpose(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	_ = networkingv1.AddToScheme(r.Scheme)
+	_ = gatewayv1.Install(r.Scheme)
+
+	t.Run("missing API sets the Exposed condition to false", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeRoute)
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+		r.AvailableAPIs = AvailableAPIs{Ingress: true}
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cond := meta.FindStatusCondition(inst.Status.Conditions, exposedCondition)
+		if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "APINotAvailable" {
+			t.Errorf("expected APINotAvailable condition, got %+v", cond)
+		}
+	})
+
+	t.Run("switching type replaces the generated resources", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeIngress)
+		inst.UID = types.UID("test-uid")
+		inst.Spec.SecureHTTP = true
+		inst.Spec.Expose.TLSTermination = strimziregistryoperatorv1alpha1.TLSTerminationReencrypt
+		caSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kafka-cluster-ca-cert", Namespace: "default"},
+			Data:       map[string][]byte{"ca.crt": []byte("CA-PEM")},
+		}
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(caSecret).Build()
+		r.AvailableAPIs = AvailableAPIs{Ingress: true, HTTPRoute: true, TLSRoute: true, BackendTLSPolicy: true}
+		key := types.NamespacedName{Name: "test-sr", Namespace: "default"}
+
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Get(ctx, key, &networkingv1.Ingress{}); err != nil {
+			t.Fatalf("expected ingress to be created: %v", err)
+		}
+
+		inst.Spec.Expose.Type = strimziregistryoperatorv1alpha1.ExposeHTTPRoute
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Get(ctx, key, &networkingv1.Ingress{}); !errors.IsNotFound(err) {
+			t.Errorf("expected ingress to be deleted, got %v", err)
+		}
+		if err := r.Get(ctx, key, &gatewayv1.HTTPRoute{}); err != nil {
+			t.Errorf("expected HTTPRoute to be created: %v", err)
+		}
+		policy := &gatewayv1.BackendTLSPolicy{}
+		if err := r.Get(ctx, key, policy); err != nil || string(policy.Spec.Validation.Hostname) != "test-sr.default.svc" {
+			t.Errorf("expected BackendTLSPolicy for the service, got %+v (err: %v)", policy.Spec, err)
+		}
+		caConfigMap := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: "test-sr" + backendCASuffix, Namespace: "default"}, caConfigMap)
+		if err != nil || caConfigMap.Data["ca.crt"] != "CA-PEM" {
+			t.Errorf("expected backend CA ConfigMap, got %+v (err: %v)", caConfigMap.Data, err)
+		}
+		if err := r.Get(ctx, key, &gatewayv1.TLSRoute{}); !errors.IsNotFound(err) {
+			t.Errorf("re-encrypt must not create a TLSRoute, got %v", err)
+		}
+
+		inst.Spec.Expose = nil
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := r.Get(ctx, key, &gatewayv1.HTTPRoute{}); !errors.IsNotFound(err) {
+			t.Errorf("expected HTTPRoute to be deleted, got %v", err)
+		}
+		if meta.FindStatusCondition(inst.Status.Conditions, exposedCondition) != nil {
+			t.Error("expected Exposed condition to be removed")
+		}
+	})
+
+	t.Run("re-encrypt trusts the connection CA", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeHTTPRoute)
+		inst.UID = types.UID("test-uid")
+		inst.Spec.SecureHTTP = true
+		inst.Spec.Expose.TLSTermination = strimziregistryoperatorv1alpha1.TLSTerminationReencrypt
+		inst.Spec.Connection = &strimziregistryoperatorv1alpha1.ConnectionSpec{CASecretName: "registry-ca"}
+		caSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "registry-ca", Namespace: "default"},
+			Data:       map[string][]byte{"ca.crt": []byte("REGISTRY-CA-PEM")},
+		}
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(caSecret).Build()
+		r.AvailableAPIs = AvailableAPIs{HTTPRoute: true, BackendTLSPolicy: true}
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		caConfigMap := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: "test-sr" + backendCASuffix, Namespace: "default"}, caConfigMap)
+		if err != nil || caConfigMap.Data["ca.crt"] != "REGISTRY-CA-PEM" {
+			t.Errorf("expected the connection CA in the backend CA ConfigMap, got %+v (err: %v)", caConfigMap.Data, err)
+		}
+	})
+	t.Run("third-party metadata and the generated route host are kept", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeRoute)
+		inst.UID = types.UID("test-uid")
+		inst.Spec.Expose.Host = ""
+		inst.Spec.Expose.Annotations = map[string]string{"haproxy.router.openshift.io/timeout": "30s"}
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+		r.AvailableAPIs = AvailableAPIs{Route: true}
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The router generates the host, cert-manager adds its own annotation.
+		route := newRoute()
+		key := types.NamespacedName{Name: "test-sr", Namespace: "default"}
+		if err := r.Get(ctx, key, route); err != nil {
+			t.Fatalf("expected route to be created: %v", err)
+		}
+		_ = unstructured.SetNestedField(route.Object, "test-sr-default.apps.example.com", "spec", "host")
+		_ = unstructured.SetNestedField(route.Object, "None", "spec", "wildcardPolicy")
+		annotations := route.GetAnnotations()
+		annotations["openshift.io/host.generated"] = "true"
+		route.SetAnnotations(annotations)
+		if err := r.Update(ctx, route); err != nil {
+			t.Fatal(err)
+		}
+
+		inst.Spec.Expose.Annotations = nil
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		route = newRoute()
+		if err := r.Get(ctx, key, route); err != nil {
+			t.Fatal(err)
+		}
+		if host, _, _ := unstructured.NestedString(route.Object, "spec", "host"); host != "test-sr-default.apps.example.com" {
+			t.Errorf("expected the generated host to be kept, got %q", host)
+		}
+		if policy, _, _ := unstructured.NestedString(route.Object, "spec", "wildcardPolicy"); policy != "None" {
+			t.Errorf("expected the wildcard policy to be kept, got %q", policy)
+		}
+		annotations = route.GetAnnotations()
+		if annotations["openshift.io/host.generated"] != "true" {
+			t.Errorf("expected the router annotation to be kept, got %v", annotations)
+		}
+		if _, ok := annotations["haproxy.router.openshift.io/timeout"]; ok {
+			t.Errorf("expected the annotation dropped from spec.expose to be removed, got %v", annotations)
+		}
+	})
+
+	t.Run("ingress keeps annotations of other controllers", func(t *testing.T) {
+		inst := newExposeInstance(strimziregistryoperatorv1alpha1.ExposeIngress)
+		inst.UID = types.UID("test-uid")
+		inst.Spec.Expose.Annotations = map[string]string{"cert-manager.io/cluster-issuer": "letsencrypt"}
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sr", Namespace: "default",
+				Labels: map[string]string{"team": "platform", "tier": "registry"},
+				Annotations: map[string]string{"acme.cert-manager.io/http01-edit-in-place": "true",
+					exposeManagedLabelsKey: "tier"}},
+		}).Build()
+		r.AvailableAPIs = AvailableAPIs{Ingress: true}
+		if err := r.reconcileExpose(ctx, inst, logr.Discard(), "kafka"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ingress := &networkingv1.Ingress{}
+		if err := r.Get(ctx, types.NamespacedName{Name: "test-sr", Namespace: "default"}, ingress); err != nil {
+			t.Fatal(err)
+		}
+		if ingress.Labels["team"] != "platform" || ingress.Annotations["acme.cert-manager.io/http01-edit-in-place"] != "true" ||
+			ingress.Annotations["cert-manager.io/cluster-issuer"] != "letsencrypt" {
+			t.Errorf("expected merged metadata, got labels %v annotations %v", ingress.Labels, ingress.Annotations)
+		}
+		if _, ok := ingress.Labels["tier"]; ok {
+			t.Errorf("expected the label the operator no longer sets to be removed, got %v", ingress.Labels)
+		}
+	})
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+)
+
+// Where the Kafka store secret and the REST API TLS secret are mounted.
+const (
+	kafkaStoreMountPath = "/var/schemaregistry"
+	restAPITLSMountPath = "/var/rest-api-tls"
+)
+
+// storeFormatKey records, on the Kafka store secret, the format its stores were rendered in.
+const storeFormatKey = keyPrefix + "/storeFormat"
+
+// storeFormat is the format of the Kafka store and generated REST API TLS secrets.
+type storeFormat string
+
+const (
+	// storeFormatJKS holds Java keystores and truststores with their passwords.
+	storeFormatJKS storeFormat = "jks"
+	// storeFormatPEM holds PEM certificates and keys: ca.crt, user.crt and user.key for
+	// Kafka, tls.crt and tls.key for the REST API.
+	storeFormatPEM storeFormat = "pem"
+)
+
+// registryFlavor renders what differs between registry implementations. The Strimzi secrets
+// the stores are built from, their volumes and the rollout on rotation are shared.
+type registryFlavor interface {
+	// env returns the container environment configuring the Kafka store and the listeners.
+	env(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaBootstrapServer, TLSSecretName string) []v1.EnvVar
+	// storeFormat returns the format the registry reads its Kafka and REST API TLS stores in.
+	storeFormat() storeFormat
+	// healthPath returns the path of the default HTTP readiness check.
+	healthPath() string
+	// apiPath returns the path of the Confluent-compatible REST API below the listener root.
+	apiPath() string
+}
+
+// secretStoreFormat returns the format of a Kafka store secret; secrets rendered before
+// flavors existed hold JKS stores.
+func secretStoreFormat(secret *v1.Secret) storeFormat {
+	if format := secret.Annotations[storeFormatKey]; format != "" {
+		return storeFormat(format)
+	}
+	return storeFormatJKS
+}
+
+// flavorFor returns the flavor selected by spec.flavor.
+func flavorFor(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) registryFlavor {
+	switch instance.Spec.Flavor {
+	case strimziregistryoperatorv1alpha1.RegistryFlavorKarapace:
+		return karapaceFlavor{}
+	case strimziregistryoperatorv1alpha1.RegistryFlavorApicurio:
+		return apicurioFlavor{}
+	default:
+		return confluentFlavor{}
+	}
+}
+
+// compatibilityLevel returns the compatibility level a new registry starts with.
+func compatibilityLevel(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if instance.Spec.CompatibilityLevel == "" {
+		return "forward"
+	}
+	return instance.Spec.CompatibilityLevel
+}
+
+// securityProtocol returns the protocol the registry talks to Kafka with.
+func securityProtocol(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if instance.Spec.SecurityProtocol == "" {
+		return "SSL"
+	}
+	return instance.Spec.SecurityProtocol
+}
+
+// heapOpts returns the JVM heap options of the Java flavors.
+func heapOpts(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if instance.Spec.HeapOpts == "" {
+		return "-Xms512M -Xmx512M"
+	}
+	return instance.Spec.HeapOpts
+}
+
+// secretEnv returns a container env var read from a key of a secret.
+func secretEnv(name, secretName, key string) v1.EnvVar {
+	return v1.EnvVar{Name: name, ValueFrom: &v1.EnvVarSource{
+		SecretKeyRef: &v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+		},
+	}}
+}
+
+// confluentFlavor is Confluent Schema Registry.
+type confluentFlavor struct{}
+
+func (confluentFlavor) storeFormat() storeFormat { return storeFormatJKS }
+func (confluentFlavor) healthPath() string       { return "/" }
+func (confluentFlavor) apiPath() string          { return "" }
+
+// env configures Confluent Schema Registry through its SCHEMA_REGISTRY_* variables.
+func (confluentFlavor) env(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaBootstrapServer, TLSSecretName string) []v1.EnvVar {
+	podEnv := []v1.EnvVar{
+		{Name: "SCHEMA_REGISTRY_KAFKASTORE_BOOTSTRAP_SERVERS", Value: kafkaBootstrapServer},
+		{Name: "SCHEMA_REGISTRY_HOST_NAME", ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{
+				FieldPath: "status.podIP",
+			},
+		}},
+	}
+
+	// Compatibility level of a new registry; later changes are applied through the REST API.
+	podEnv = append(podEnv,
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_SCHEMA_COMPATIBILITY_LEVEL", Value: compatibilityLevel(instance)},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SECURITY_PROTOCOL", Value: securityProtocol(instance)},
+	)
+
+	podEnv = append(podEnv,
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_MASTER_ELIGIBILITY", Value: strconv.FormatBool(leaderEligible(instance))},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_HEAP_OPTS", Value: heapOpts(instance)},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_TOPIC", Value: schemasTopic(instance)},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_KEYSTORE_LOCATION", Value: kafkaStoreMountPath + "/keystore.jks"},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_TRUSTSTORE_LOCATION", Value: kafkaStoreMountPath + "/truststore.jks"},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_KEYSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: instance.Name + jksSecretSuffix,
+				},
+				Key: "keystore_password",
+			},
+		}},
+		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_TRUSTSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{
+					Name: instance.Name + jksSecretSuffix,
+				},
+				Key: "truststore_password",
+			},
+		}},
+	)
+
+	// REST API TLS configuration
+	if instance.Spec.SecureHTTP {
+		listeners := "https://0.0.0.0:8085"
+		if instance.Spec.DualListener {
+			listeners = "http://0.0.0.0:8081," + listeners
+		}
+		podEnv = append(podEnv,
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_LISTENERS", Value: listeners},
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_SCHEMA_REGISTRY_INTER_INSTANCE_PROTOCOL", Value: "https"},
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_KEYSTORE_LOCATION", Value: restAPITLSMountPath + "/tls-keystore.jks"},
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_KEYSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: TLSSecretName,
+					},
+					Key: "keystore_password",
+				},
+			}},
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_KEY_PASSWORD", ValueFrom: &v1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{
+						Name: TLSSecretName,
+					},
+					Key: "key_password",
+				},
+			}},
+		)
+		podEnv = append(podEnv, clientAuthEnv(instance)...)
+	} else {
+		podEnv = append(podEnv, v1.EnvVar{Name: "SCHEMA_REGISTRY_LISTENERS", Value: "http://0.0.0.0:8081"})
+	}
+	podEnv = append(podEnv, basicAuthEnv(instance)...)
+
+	// Remote JMX, published on the Service when spec.service.jmxPort is set
+	if instance.Spec.Service != nil && instance.Spec.Service.JMXPort != nil {
+		podEnv = append(podEnv,
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_JMX_PORT", Value: fmt.Sprintf("%d", *instance.Spec.Service.JMXPort)},
+			v1.EnvVar{Name: "SCHEMA_REGISTRY_JMX_HOSTNAME", ValueFrom: &v1.EnvVarSource{
+				FieldRef: &v1.ObjectFieldSelector{FieldPath: "status.podIP"},
+			}},
+		)
+	}
+
+	return podEnv
+}
+
+// karapaceFlavor is Karapace, which reads PEM files and has a single listener.
+type karapaceFlavor struct{}
+
+func (karapaceFlavor) storeFormat() storeFormat { return storeFormatPEM }
+func (karapaceFlavor) healthPath() string       { return "/_health" }
+func (karapaceFlavor) apiPath() string          { return "" }
+
+// env configures Karapace through its KARAPACE_* variables.
+func (karapaceFlavor) env(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaBootstrapServer, _ string) []v1.EnvVar {
+	port, scheme := listenerPortAndScheme(instance)
+	podEnv := []v1.EnvVar{
+		{Name: "KARAPACE_KARAPACE_REGISTRY", Value: "true"},
+		{Name: "KARAPACE_BOOTSTRAP_URI", Value: kafkaBootstrapServer},
+		{Name: "KARAPACE_HOST", Value: "0.0.0.0"},
+		{Name: "KARAPACE_PORT", Value: strconv.Itoa(int(port))},
+		{Name: "KARAPACE_ADVERTISED_HOSTNAME", ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{FieldPath: "status.podIP"},
+		}},
+		{Name: "KARAPACE_ADVERTISED_PROTOCOL", Value: strings.ToLower(string(scheme))},
+		{Name: "KARAPACE_MASTER_ELIGIBILITY", Value: strconv.FormatBool(leaderEligible(instance))},
+		{Name: "KARAPACE_TOPIC_NAME", Value: schemasTopic(instance)},
+		// Compatibility level of a new registry; later changes are applied through the REST API.
+		{Name: "KARAPACE_COMPATIBILITY", Value: strings.ToUpper(compatibilityLevel(instance))},
+		{Name: "KARAPACE_SECURITY_PROTOCOL", Value: securityProtocol(instance)},
+		{Name: "KARAPACE_SSL_CAFILE", Value: kafkaStoreMountPath + "/ca.crt"},
+		{Name: "KARAPACE_SSL_CERTFILE", Value: kafkaStoreMountPath + "/user.crt"},
+		{Name: "KARAPACE_SSL_KEYFILE", Value: kafkaStoreMountPath + "/user.key"},
+	}
+	if instance.Spec.SecureHTTP {
+		podEnv = append(podEnv,
+			v1.EnvVar{Name: "KARAPACE_SERVER_TLS_CERTFILE", Value: restAPITLSMountPath + "/tls.crt"},
+			v1.EnvVar{Name: "KARAPACE_SERVER_TLS_KEYFILE", Value: restAPITLSMountPath + "/tls.key"},
+		)
+	}
+	return podEnv
+}
+
+// apicurioFlavor is Apicurio Registry with KafkaSQL storage. The operator and clients use its
+// Confluent-compatible API; all instances consume the journal topic, so there is no leader.
+type apicurioFlavor struct{}
+
+func (apicurioFlavor) storeFormat() storeFormat { return storeFormatJKS }
+func (apicurioFlavor) healthPath() string       { return "/health/ready" }
+func (apicurioFlavor) apiPath() string          { return "/apis/ccompat/v7" }
+
+// env configures Apicurio Registry through its REGISTRY_* and QUARKUS_* variables.
+func (apicurioFlavor) env(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaBootstrapServer, TLSSecretName string) []v1.EnvVar {
+	jksSecretName := instance.Name + jksSecretSuffix
+	podEnv := []v1.EnvVar{
+		{Name: "REGISTRY_KAFKASQL_BOOTSTRAP_SERVERS", Value: kafkaBootstrapServer},
+		{Name: "REGISTRY_KAFKASQL_TOPIC", Value: schemasTopic(instance)},
+		{Name: "REGISTRY_KAFKA_COMMON_SECURITY_PROTOCOL", Value: securityProtocol(instance)},
+		{Name: "REGISTRY_KAFKA_COMMON_SSL_KEYSTORE_TYPE", Value: "JKS"},
+		{Name: "REGISTRY_KAFKA_COMMON_SSL_KEYSTORE_LOCATION", Value: kafkaStoreMountPath + "/keystore.jks"},
+		secretEnv("REGISTRY_KAFKA_COMMON_SSL_KEYSTORE_PASSWORD", jksSecretName, "keystore_password"),
+		{Name: "REGISTRY_KAFKA_COMMON_SSL_TRUSTSTORE_TYPE", Value: "JKS"},
+		{Name: "REGISTRY_KAFKA_COMMON_SSL_TRUSTSTORE_LOCATION", Value: kafkaStoreMountPath + "/truststore.jks"},
+		secretEnv("REGISTRY_KAFKA_COMMON_SSL_TRUSTSTORE_PASSWORD", jksSecretName, "truststore_password"),
+		{Name: "JAVA_OPTS_APPEND", Value: heapOpts(instance)},
+		{Name: "QUARKUS_HTTP_HOST", Value: "0.0.0.0"},
+		{Name: "QUARKUS_HTTP_PORT", Value: "8081"},
+	}
+	if instance.Spec.SecureHTTP {
+		insecureRequests := "disabled"
+		if instance.Spec.DualListener {
+			insecureRequests = "enabled"
+		}
+		podEnv = append(podEnv,
+			v1.EnvVar{Name: "QUARKUS_HTTP_SSL_PORT", Value: "8085"},
+			v1.EnvVar{Name: "QUARKUS_HTTP_INSECURE_REQUESTS", Value: insecureRequests},
+			v1.EnvVar{Name: "QUARKUS_HTTP_SSL_CERTIFICATE_KEY_STORE_FILE", Value: restAPITLSMountPath + "/tls-keystore.jks"},
+			v1.EnvVar{Name: "QUARKUS_HTTP_SSL_CERTIFICATE_KEY_STORE_FILE_TYPE", Value: "JKS"},
+			secretEnv("QUARKUS_HTTP_SSL_CERTIFICATE_KEY_STORE_PASSWORD", TLSSecretName, "keystore_password"),
+		)
+	}
+	return podEnv
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newFlavorInstance returns a test instance of the given flavor.
+func newFlavorInstance(flavor strimziregistryoperatorv1alpha1.RegistryFlavor) *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	instance := newTestInstance()
+	instance.Spec.Flavor = flavor
+	instance.Labels = map[string]string{"strimzi.io/cluster": "test-cluster"}
+	return instance
+}
+
+func TestFlavorEnv(t *testing.T) {
+	t.Run("confluent is the default", func(t *testing.T) {
+		env := buildPodEnv(newTestInstance(), "kafka:9093", "")
+		if got := envValue(env, "SCHEMA_REGISTRY_KAFKASTORE_SSL_KEYSTORE_LOCATION"); got != "/var/schemaregistry/keystore.jks" {
+			t.Errorf("expected the JKS keystore, got %q", got)
+		}
+	})
+
+	t.Run("karapace reads PEM files", func(t *testing.T) {
+		instance := newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorKarapace)
+		instance.Spec.SecureHTTP = true
+		env := buildPodEnv(instance, "kafka:9093", "")
+		for name, want := range map[string]string{
+			"KARAPACE_BOOTSTRAP_URI":       "kafka:9093",
+			"KARAPACE_PORT":                "8085",
+			"KARAPACE_ADVERTISED_PROTOCOL": "https",
+			"KARAPACE_COMPATIBILITY":       "BACKWARD",
+			"KARAPACE_TOPIC_NAME":          "registry-schemas",
+			"KARAPACE_MASTER_ELIGIBILITY":  "true",
+			"KARAPACE_SSL_CAFILE":          "/var/schemaregistry/ca.crt",
+			"KARAPACE_SSL_KEYFILE":         "/var/schemaregistry/user.key",
+			"KARAPACE_SERVER_TLS_CERTFILE": "/var/rest-api-tls/tls.crt",
+		} {
+			if got := envValue(env, name); got != want {
+				t.Errorf("expected %s=%q, got %q", name, want, got)
+			}
+		}
+		for _, e := range env {
+			if strings.HasPrefix(e.Name, "SCHEMA_REGISTRY_") {
+				t.Errorf("unexpected Confluent variable %s", e.Name)
+			}
+		}
+	})
+
+	t.Run("apicurio serves HTTPS from the keystore", func(t *testing.T) {
+		instance := newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorApicurio)
+		instance.Spec.SecureHTTP = true
+		instance.Spec.DualListener = true
+		env := buildPodEnv(instance, "kafka:9093", "test-sr-tls")
+		for name, want := range map[string]string{
+			"REGISTRY_KAFKASQL_BOOTSTRAP_SERVERS":         "kafka:9093",
+			"REGISTRY_KAFKASQL_TOPIC":                     "registry-schemas",
+			"REGISTRY_KAFKA_COMMON_SSL_KEYSTORE_LOCATION": "/var/schemaregistry/keystore.jks",
+			"QUARKUS_HTTP_PORT":                           "8081",
+			"QUARKUS_HTTP_SSL_PORT":                       "8085",
+			"QUARKUS_HTTP_INSECURE_REQUESTS":              "enabled",
+		} {
+			if got := envValue(env, name); got != want {
+				t.Errorf("expected %s=%q, got %q", name, want, got)
+			}
+		}
+		if !hasEnv(env, "QUARKUS_HTTP_SSL_CERTIFICATE_KEY_STORE_PASSWORD") {
+			t.Error("expected the REST API keystore password")
+		}
+	})
+}
+
+func TestFlavorProbes(t *testing.T) {
+	subjects := &strimziregistryoperatorv1alpha1.ProbesSpec{
+		ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects,
+	}
+	tests := []struct {
+		name   string
+		flavor strimziregistryoperatorv1alpha1.RegistryFlavor
+		probes *strimziregistryoperatorv1alpha1.ProbesSpec
+		path   string
+	}{
+		{"karapace health", strimziregistryoperatorv1alpha1.RegistryFlavorKarapace, nil, "/_health"},
+		{"karapace subjects", strimziregistryoperatorv1alpha1.RegistryFlavorKarapace, subjects, "/subjects"},
+		{"apicurio health", strimziregistryoperatorv1alpha1.RegistryFlavorApicurio, nil, "/health/ready"},
+		{"apicurio subjects", strimziregistryoperatorv1alpha1.RegistryFlavorApicurio, subjects, "/apis/ccompat/v7/subjects"},
+	}
+	for _, test := range tests {
+		instance := newFlavorInstance(test.flavor)
+		instance.Spec.Probes = test.probes
+		readiness, _, _ := buildProbes(instance)
+		if readiness.HTTPGet == nil || readiness.HTTPGet.Path != test.path {
+			t.Errorf("%s: expected readiness on %s, got %+v", test.name, test.path, readiness.ProbeHandler)
+		}
+	}
+}
+
+func TestFlavorListenerURL(t *testing.T) {
+	if got := listenerStatuses(newTestInstance())[0].URL; got != "http://test-sr.default.svc:80" {
+		t.Errorf("unexpected Confluent URL %s", got)
+	}
+	instance := newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorApicurio)
+	if got := listenerStatuses(instance)[0].URL; got != "http://test-sr.default.svc:80/apis/ccompat/v7" {
+		t.Errorf("expected the Confluent-compatible API of Apicurio, got %s", got)
+	}
+}
+
+func TestFlavorSpecHash(t *testing.T) {
+	base, _ := computeSpecHash(newTestInstance())
+	confluent, _ := computeSpecHash(newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorConfluent))
+	karapace, _ := computeSpecHash(newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorKarapace))
+	if base != confluent {
+		t.Error("the default flavor should keep the hash of existing CRs")
+	}
+	if base == karapace {
+		t.Error("changing the flavor should change the hash")
+	}
+}
+
+func TestCreateSecretPEM(t *testing.T) {
+	clusterCA := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster" + clusterCASuffix, Namespace: "default"},
+		Data:       map[string][]byte{"ca.crt": []byte("cluster-ca")},
+	}
+	user := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sr", Namespace: "default"},
+		Data: map[string][]byte{
+			"ca.crt":        []byte("clients-ca"),
+			"user.crt":      []byte("user-cert"),
+			"user.key":      []byte("user-key"),
+			"user.password": []byte("secret"),
+		},
+	}
+	r := newTestReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(clusterCA, user).Build()
+	ctx := context.Background()
+	if err := r.Get(ctx, client.ObjectKeyFromObject(clusterCA), clusterCA); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(user), user); err != nil {
+		t.Fatal(err)
+	}
+
+	// A JKS secret of the same Strimzi versions, rendered for the Confluent flavor.
+	jks := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sr" + jksSecretSuffix,
+			Namespace: "default",
+			Annotations: map[string]string{
+				CAVersionKey:   clusterCA.ResourceVersion,
+				userVersionKey: user.ResourceVersion,
+			},
+		},
+		Data: map[string][]byte{"keystore.jks": []byte("jks")},
+	}
+	if err := r.Create(ctx, jks); err != nil {
+		t.Fatal(err)
+	}
+
+	instance := newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorKarapace)
+	secret, created, err := r.createSecret(instance, ctx, logr.Discard(), "test-cluster", nil, nil)
+	if err != nil {
+		t.Fatalf("createSecret() error = %v", err)
+	}
+	if !created {
+		t.Fatal("expected the JKS secret to be replaced for the PEM flavor")
+	}
+	if secretStoreFormat(secret) != storeFormatPEM {
+		t.Errorf("expected the PEM store format, got %s", secretStoreFormat(secret))
+	}
+	for key, want := range map[string]string{"ca.crt": "cluster-ca", "user.crt": "user-cert", "user.key": "user-key"} {
+		if got := string(secret.Data[key]); got != want {
+			t.Errorf("expected %s=%q, got %q", key, want, got)
+		}
+	}
+	if _, ok := secret.Data["keystore.jks"]; ok {
+		t.Error("PEM secret should not carry a keystore")
+	}
+}
+
+func TestCreateTLSSecretPEM(t *testing.T) {
+	ca, err := testutil.GenerateTestCA()
+	if err != nil {
+		t.Fatalf("failed to generate CA: %v", err)
+	}
+	r := newTestReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster" + clusterCASuffix, Namespace: "default"},
+			Data:       map[string][]byte{"ca.crt": []byte(ca.CACertPEM)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster" + clusterCAKeySuffix, Namespace: "default"},
+			Data:       map[string][]byte{"ca.key": []byte(ca.CAKeyPEM)},
+		},
+	).Build()
+
+	instance := newFlavorInstance(strimziregistryoperatorv1alpha1.RegistryFlavorKarapace)
+	instance.Spec.SecureHTTP = true
+	secret, err := r.createTLSSecret(instance, context.Background(), logr.Discard(), "test-cluster")
+	if err != nil {
+		t.Fatalf("createTLSSecret() error = %v", err)
+	}
+	if _, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"]); err != nil {
+		t.Errorf("expected a PEM certificate and key: %v", err)
+	}
+}
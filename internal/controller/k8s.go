@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"hash/fnv"
 	"io"
+	"maps"
 	"strings"
 
 	"github.com/go-logr/logr"
 	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
 	certprocessor "github.com/randsw/schema-registry-operator-strimzi/certProcessor"
+	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
 	kafka "github.com/scholzj/strimzi-go/pkg/apis/kafka.strimzi.io/v1"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -20,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -35,7 +38,7 @@ const (
 
 // labelsForStrimziSchemaRegistryOperator returns the standard set of labels
 // for resources managed by the Schema Registry operator.
-func labelsForStrimziSchemaRegistryOperator(name, image, kafkaClusterName string) map[string]string {
+func labelsForStrimziSchemaRegistryOperator(name, version, kafkaClusterName string) map[string]string {
 	return map[string]string{
 		"app":                          name,
 		"strimzi-schema-registry":      name,
@@ -43,18 +46,67 @@ func labelsForStrimziSchemaRegistryOperator(name, image, kafkaClusterName string
 		"app.kubernetes.io/managed-by": "strimzi-registry-operator",
 		"app.kubernetes.io/name":       "strimzischemaregistry",
 		"app.kubernetes.io/part-of":    name,
-		"app.kubernetes.io/version": func() string {
-			parts := strings.Split(image, ":")
-			if len(parts) > 1 {
-				last := parts[len(parts)-1]
-				if !strings.Contains(last, "/") {
-					return last
-				}
-			}
-			return "latest"
-		}(),
-		"strimzi.io/cluster": kafkaClusterName,
+		"app.kubernetes.io/version":    version,
+		"strimzi.io/cluster":           kafkaClusterName,
+	}
+}
+
+// versionFromImage guesses the version of an image run without spec.version from its tag.
+func versionFromImage(image string) string {
+	parts := strings.Split(image, ":")
+	if len(parts) > 1 {
+		last := parts[len(parts)-1]
+		if !strings.Contains(last, "/") {
+			return last
+		}
 	}
+	return "latest"
+}
+
+// deploymentSelector returns the labels selecting the pods of a new Deployment: the standard
+// labels without the version, which changes with every upgrade.
+func deploymentSelector(labels map[string]string) map[string]string {
+	selector := maps.Clone(labels)
+	delete(selector, "app.kubernetes.io/version")
+	return selector
+}
+
+// migrateDeploymentSelector deletes a Deployment created when its selector included the version
+// label, orphaning its ReplicaSets and pods, so that it is recreated with the current selector and
+// adopts them: the selector is immutable, and keeping it would pin the version label of the pods.
+// It is called for pod template changes only, which went through the pre-flight checks of
+// reconcileUpgrade, and reports whether the Deployment is being deleted.
+func (r *StrimziSchemaRegistryReconciler) migrateDeploymentSelector(ctx context.Context,
+	found *apps.Deployment, logger logr.Logger) (bool, error) {
+	if found.Spec.Selector == nil {
+		return false, nil
+	}
+	if _, ok := found.Spec.Selector.MatchLabels["app.kubernetes.io/version"]; !ok {
+		return false, nil
+	}
+	logger.Info("Recreating deployment to drop the version label from its selector", "Deployment.Name", found.Name)
+	if err := r.Delete(ctx, found, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
+}
+
+// recreatingDeployment reports whether the missing Deployment of instance left ReplicaSets
+// behind, orphaned by migrateDeploymentSelector: the registry keeps running and its Deployment
+// is recreated to adopt them, rather than deployed for the first time.
+func (r *StrimziSchemaRegistryReconciler) recreatingDeployment(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (bool, error) {
+	replicaSets := &apps.ReplicaSetList{}
+	if err := r.List(ctx, replicaSets, client.InNamespace(instance.Namespace),
+		client.MatchingLabels(podSelectorLabels(instance.Name))); err != nil {
+		return false, err
+	}
+	for i := range replicaSets.Items {
+		if metav1.GetControllerOf(&replicaSets.Items[i]) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // createDeployment creates a new Deployment along with its dependent secrets.
@@ -116,7 +168,20 @@ func (r *StrimziSchemaRegistryReconciler) createDeployment(instance *strimziregi
 		}
 	}
 
-	return r.buildDeploymentSpec(instance, kafkaBootstrapServer, kafkaClusterName, jksResourceVersion, TLSSecretName, logger)
+	// Rendered secrets (client truststore, Basic authentication) are kept up to date by
+	// Reconcile before the deployment is created
+	versions, err := r.podTemplateVersions(ctx, instance)
+	if err != nil {
+		logger.Error(err, "Failed to get rendered REST API secrets")
+		return nil, err
+	}
+
+	dep, err := r.buildDeploymentSpec(instance, kafkaBootstrapServer, kafkaClusterName, jksResourceVersion, TLSSecretName, logger)
+	if err != nil {
+		return nil, err
+	}
+	applyPodTemplateVersions(dep, versions)
+	return dep, nil
 }
 
 // buildDeploymentSpec builds the Deployment spec from the CR spec and pre-fetched external inputs.
@@ -131,25 +196,36 @@ func (r *StrimziSchemaRegistryReconciler) buildDeploymentSpec(
 	TLSSecretName string,
 	logger logr.Logger,
 ) (*apps.Deployment, error) {
-	podSpec := instance.Spec.Template
-	// Validate that at least one container exists
-	if len(podSpec.Spec.Containers) == 0 {
-		return nil, fmt.Errorf("template must contain at least one container")
+	// Work on a copy so the merge below never leaks operator-managed entries back
+	// into the CR (which would also skew the spec hash).
+	podSpec := *instance.Spec.Template.DeepCopy()
+	idx, err := schemaRegistryContainerIndex(&podSpec.Spec)
+	if err != nil {
+		return nil, err
 	}
+	container := &podSpec.Spec.Containers[idx]
 
 	// Build pod environment, volumes, and probes
 	podEnv := buildPodEnv(instance, kafkaBootstrapServer, TLSSecretName)
-	podVolume, containerVolumeMount := buildPodVolumes(instance, TLSSecretName)
+	podVolume, containerVolumeMount := buildPodVolumes(instance, TLSSecretName, kafkaClusterName)
+	readinessProbe, livenessProbe, startupProbe := buildProbes(instance)
 
-	listenerPort, scheme := listenerPortAndScheme(instance)
-	readinessProbe, livenessProbe, startupProbe := buildProbes(listenerPort, scheme)
+	container.ReadinessProbe = readinessProbe
+	container.LivenessProbe = livenessProbe
+	container.StartupProbe = startupProbe
 
-	podSpec.Spec.Containers[0].ReadinessProbe = readinessProbe
-	podSpec.Spec.Containers[0].LivenessProbe = livenessProbe
-	podSpec.Spec.Containers[0].StartupProbe = startupProbe
+	// A managed version overrides the template image with the one of the version table.
+	version := rolloutVersion(instance)
+	if version != "" {
+		image, ok := r.versionTable().image(instance.Spec.Flavor, version)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errUnsupportedVersion, version)
+		}
+		container.Image = image
+	}
 
-	if podSpec.Spec.Containers[0].Resources.Limits == nil && podSpec.Spec.Containers[0].Resources.Requests == nil {
-		podSpec.Spec.Containers[0].Resources = v1.ResourceRequirements{
+	if container.Resources.Limits == nil && container.Resources.Requests == nil {
+		container.Resources = v1.ResourceRequirements{
 			Requests: v1.ResourceList{
 				v1.ResourceCPU:    mustParseQuantity("250m"),
 				v1.ResourceMemory: mustParseQuantity("512Mi"),
@@ -160,12 +236,31 @@ func (r *StrimziSchemaRegistryReconciler) buildDeploymentSpec(
 			},
 		}
 	}
-	podSpec.Spec.Containers[0].Env = podEnv
-	podSpec.Spec.Containers[0].VolumeMounts = containerVolumeMount
-	podSpec.Spec.Volumes = podVolume
-	ls := labelsForStrimziSchemaRegistryOperator(instance.Name, instance.Spec.Template.Spec.Containers[0].Image, kafkaClusterName)
+
+	// Operator-managed entries come first; user entries from the template are kept.
+	if container.Env, err = mergeEnv(podEnv, container.Env); err != nil {
+		return nil, err
+	}
+	if container.Env, err = appendEnvOpts(container.Env, "SCHEMA_REGISTRY_OPTS", basicAuthJavaOpts(instance)); err != nil {
+		return nil, err
+	}
+	if container.VolumeMounts, err = mergeVolumeMounts(containerVolumeMount, container.VolumeMounts); err != nil {
+		return nil, err
+	}
+	if podSpec.Spec.Volumes, err = mergeVolumes(podVolume, podSpec.Spec.Volumes); err != nil {
+		return nil, err
+	}
+	applyPodSpreading(instance, &podSpec.Spec)
+	labelVersion := version
+	if labelVersion == "" {
+		labelVersion = versionFromImage(container.Image)
+	}
+	ls := labelsForStrimziSchemaRegistryOperator(instance.Name, labelVersion, kafkaClusterName)
 	podSpec.Labels = ls
 	podSpec.Annotations = map[string]string{keyPrefix + "/jksVersion": jksResourceVersion}
+	if version != "" {
+		podSpec.Annotations[versionKey] = version
+	}
 
 	// Compute spec hash and store it in annotation to detect spec changes on subsequent reconciliations.
 	specHash, err := computeSpecHash(instance)
@@ -185,12 +280,14 @@ func (r *StrimziSchemaRegistryReconciler) buildDeploymentSpec(
 			},
 		},
 		Spec: apps.DeploymentSpec{
+			Replicas: ptr.To(desiredReplicas(instance)),
 			Selector: &metav1.LabelSelector{
-				MatchLabels: ls,
+				MatchLabels: deploymentSelector(ls),
 			},
 			Template: podSpec,
 		},
 	}
+	applyUpgradeStrategy(instance, &dep.Spec)
 	// Set StrimziSchemaRegistry instance as the owner and controller
 	err = ctrl.SetControllerReference(instance, dep, r.Scheme)
 	if err != nil {
@@ -200,6 +297,18 @@ func (r *StrimziSchemaRegistryReconciler) buildDeploymentSpec(
 	return dep, nil
 }
 
+// servicePort returns the name and number of the Service port for the REST API.
+func servicePort(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (string, int32) {
+	name, port := "http", int32(80)
+	if instance.Spec.SecureHTTP {
+		name, port = "https", 443
+	}
+	if instance.Spec.Service != nil && instance.Spec.Service.Port != nil {
+		port = *instance.Spec.Service.Port
+	}
+	return name, port
+}
+
 // listenerPortAndScheme returns the container port and URI scheme based on SecureHTTP.
 func listenerPortAndScheme(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (int32, v1.URIScheme) {
 	if instance.Spec.SecureHTTP {
@@ -210,98 +319,18 @@ func listenerPortAndScheme(instance *strimziregistryoperatorv1alpha1.StrimziSche
 
 // buildPodEnv constructs the environment variables for the Schema Registry container.
 func buildPodEnv(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, kafkaBootstrapServer, TLSSecretName string) []v1.EnvVar {
-	podEnv := []v1.EnvVar{
-		{Name: "SCHEMA_REGISTRY_KAFKASTORE_BOOTSTRAP_SERVERS", Value: kafkaBootstrapServer},
-		{Name: "SCHEMA_REGISTRY_HOST_NAME", ValueFrom: &v1.EnvVarSource{
-			FieldRef: &v1.ObjectFieldSelector{
-				FieldPath: "status.podIP",
-			},
-		}},
-	}
-
-	// Compatibility level
-	compatLevel := instance.Spec.CompatibilityLevel
-	if compatLevel == "" {
-		compatLevel = "forward"
-	}
-	podEnv = append(podEnv, v1.EnvVar{Name: "SCHEMA_REGISTRY_SCHEMA_COMPATIBILITY_LEVEL", Value: compatLevel})
-
-	// Security protocol
-	securityProtocol := instance.Spec.SecurityProtocol
-	if securityProtocol == "" {
-		securityProtocol = "SSL"
-	}
-	podEnv = append(podEnv, v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SECURITY_PROTOCOL", Value: securityProtocol})
-
-	// Heap opts
-	heapOpts := instance.Spec.HeapOpts
-	if heapOpts == "" {
-		heapOpts = "-Xms512M -Xmx512M"
-	}
-
-	podEnv = append(podEnv,
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_MASTER_ELIGIBILITY", Value: "true"},
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_HEAP_OPTS", Value: heapOpts},
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_TOPIC", Value: "registry-schemas"},
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_KEYSTORE_LOCATION", Value: "/var/schemaregistry/keystore.jks"},
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_TRUSTSTORE_LOCATION", Value: "/var/schemaregistry/truststore.jks"},
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_KEYSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
-			SecretKeyRef: &v1.SecretKeySelector{
-				LocalObjectReference: v1.LocalObjectReference{
-					Name: instance.Name + jksSecretSuffix,
-				},
-				Key: "keystore_password",
-			},
-		}},
-		v1.EnvVar{Name: "SCHEMA_REGISTRY_KAFKASTORE_SSL_TRUSTSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
-			SecretKeyRef: &v1.SecretKeySelector{
-				LocalObjectReference: v1.LocalObjectReference{
-					Name: instance.Name + jksSecretSuffix,
-				},
-				Key: "truststore_password",
-			},
-		}},
-	)
-
-	// REST API TLS configuration
-	if instance.Spec.SecureHTTP {
-		podEnv = append(podEnv,
-			v1.EnvVar{Name: "SCHEMA_REGISTRY_LISTENERS", Value: "https://0.0.0.0:8085"},
-			v1.EnvVar{Name: "SCHEMA_REGISTRY_SCHEMA_REGISTRY_INTER_INSTANCE_PROTOCOL", Value: "https"},
-			v1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_KEYSTORE_LOCATION", Value: "/var/rest-api-tls/tls-keystore.jks"},
-			v1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_KEYSTORE_PASSWORD", ValueFrom: &v1.EnvVarSource{
-				SecretKeyRef: &v1.SecretKeySelector{
-					LocalObjectReference: v1.LocalObjectReference{
-						Name: TLSSecretName,
-					},
-					Key: "keystore_password",
-				},
-			}},
-			v1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_KEY_PASSWORD", ValueFrom: &v1.EnvVarSource{
-				SecretKeyRef: &v1.SecretKeySelector{
-					LocalObjectReference: v1.LocalObjectReference{
-						Name: TLSSecretName,
-					},
-					Key: "key_password",
-				},
-			}},
-		)
-	} else {
-		podEnv = append(podEnv, v1.EnvVar{Name: "SCHEMA_REGISTRY_LISTENERS", Value: "http://0.0.0.0:8081"})
-	}
-
-	return podEnv
+	return flavorFor(instance).env(instance, kafkaBootstrapServer, TLSSecretName)
 }
 
 // buildPodVolumes constructs the volumes and volume mounts for the deployment.
-func buildPodVolumes(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, TLSSecretName string) ([]v1.Volume, []v1.VolumeMount) {
+func buildPodVolumes(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, TLSSecretName, kafkaClusterName string) ([]v1.Volume, []v1.VolumeMount) {
 	var defaultMode int32 = 420
 
 	// KafkaStore JKS secret volume
 	containerVolumeMount := []v1.VolumeMount{
 		{
 			Name:      "tls",
-			MountPath: "/var/schemaregistry",
+			MountPath: kafkaStoreMountPath,
 			ReadOnly:  true,
 		},
 	}
@@ -321,7 +350,7 @@ func buildPodVolumes(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegi
 	if instance.Spec.SecureHTTP {
 		containerVolumeMount = append(containerVolumeMount, v1.VolumeMount{
 			Name:      "rest-api-tls",
-			MountPath: "/var/rest-api-tls",
+			MountPath: restAPITLSMountPath,
 			ReadOnly:  true,
 		})
 		podVolume = append(podVolume, v1.Volume{
@@ -335,42 +364,135 @@ func buildPodVolumes(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegi
 		})
 	}
 
+	// CA certificate of the REST API for the HTTPS readiness check
+	if needsRESTAPICAVolume(instance) {
+		containerVolumeMount = append(containerVolumeMount, v1.VolumeMount{
+			Name:      "rest-api-ca",
+			MountPath: restAPICAMountPath,
+			ReadOnly:  true,
+		})
+		podVolume = append(podVolume, v1.Volume{
+			Name: "rest-api-ca",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName:  connectionCASecretName(instance, kafkaClusterName),
+					Items:       []v1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+					DefaultMode: &defaultMode,
+				},
+			},
+		})
+	}
+
+	// Truststore with the CA that signs REST API client certificates
+	if clientAuthEnabled(instance) {
+		containerVolumeMount = append(containerVolumeMount, v1.VolumeMount{
+			Name:      "rest-api-client-truststore",
+			MountPath: clientTruststoreMountPath,
+			ReadOnly:  true,
+		})
+		podVolume = append(podVolume, v1.Volume{
+			Name: "rest-api-client-truststore",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName:  instance.Name + clientTruststoreSuffix,
+					DefaultMode: &defaultMode,
+				},
+			},
+		})
+	}
+
+	// JAAS config and password file for HTTP Basic authentication
+	if basicAuthEnabled(instance) {
+		containerVolumeMount = append(containerVolumeMount, v1.VolumeMount{
+			Name:      "rest-api-auth",
+			MountPath: basicAuthMountPath,
+			ReadOnly:  true,
+		})
+		podVolume = append(podVolume, v1.Volume{
+			Name: "rest-api-auth",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName:  instance.Name + basicAuthSuffix,
+					DefaultMode: &defaultMode,
+				},
+			},
+		})
+	}
+
 	return podVolume, containerVolumeMount
 }
 
+// restAPICAMountPath is where the CA certificate signing the REST API certificate is mounted
+// when the HTTPS readiness check needs it.
+const restAPICAMountPath = "/var/rest-api-ca"
+
 // buildProbes constructs readiness, liveness, and startup probes for the Schema Registry container.
 //
-// When SecureHTTP is enabled (scheme == HTTPS), readiness and startup probes use TCPSocket
-// instead of HTTPGet. This avoids TLS certificate verification failures: Kubernetes kubelet
-// does not support insecure-skip-verify for HTTPGet probes, so HTTPS probes against self-signed
-// certificates (generated from the Strimzi cluster CA) would fail perpetually.
-// The liveness probe already uses TCPSocket unconditionally for the same reason.
-func buildProbes(listenerPort int32, scheme v1.URIScheme) (*v1.Probe, *v1.Probe, *v1.Probe) {
-	var readinessHandler, startupHandler v1.ProbeHandler
-
-	if scheme == v1.URISchemeHTTPS {
-		// Self-signed cert: TCPSocket avoids TLS verification failures.
+// With the default "Listener" readiness check and SecureHTTP enabled (scheme == HTTPS), readiness
+// and startup probes use TCPSocket instead of HTTPGet. This avoids TLS certificate verification
+// failures: Kubernetes kubelet does not support insecure-skip-verify or custom CAs for HTTPGet
+// probes, so HTTPS probes against certificates signed by the Strimzi cluster CA would fail perpetually.
+// The "Subjects" readiness check queries /subjects instead; over HTTPS it runs curl inside the
+// container with the mounted CA of the REST API certificate, resolving the service hostname (a SAN
+// of the generated certificate) to the loopback address. When that CA is not known, it falls back
+// to the TCP check.
+// The liveness probe uses TCPSocket unconditionally: a registry that lost Kafka should be taken
+// out of rotation, not restarted. The HTTP paths come from the flavor.
+func buildProbes(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (*v1.Probe, *v1.Probe, *v1.Probe) {
+	listenerPort, scheme := listenerPortAndScheme(instance)
+	flavor := flavorFor(instance)
+	subjectsPath := flavor.apiPath() + "/subjects"
+	probes := instance.Spec.Probes
+	if probes == nil {
+		probes = &strimziregistryoperatorv1alpha1.ProbesSpec{}
+	}
+
+	// The probes carry no credentials: with Basic authentication or required client
+	// certificates, only a TCP check can succeed. The CRD rejects the Subjects check
+	// in that case, so this only sets the default Listener check to TCP.
+	credentialsRequired := basicAuthEnabled(instance) ||
+		clientAuthMode(instance) == strimziregistryoperatorv1alpha1.ClientAuthRequired
+
+	var readinessHandler v1.ProbeHandler
+	switch {
+	case credentialsRequired:
 		readinessHandler = v1.ProbeHandler{
 			TCPSocket: &v1.TCPSocketAction{
 				Port: intstr.IntOrString{IntVal: listenerPort},
 			},
 		}
-		startupHandler = v1.ProbeHandler{
-			TCPSocket: &v1.TCPSocketAction{
-				Port: intstr.IntOrString{IntVal: listenerPort},
+	case probes.ReadinessCheck == strimziregistryoperatorv1alpha1.ReadinessCheckSubjects && scheme == v1.URISchemeHTTPS &&
+		restAPICAKnown(instance):
+		host := fmt.Sprintf("%s.%s.svc", instance.Name, instance.Namespace)
+		readinessHandler = v1.ProbeHandler{
+			Exec: &v1.ExecAction{
+				Command: []string{
+					"curl", "--fail", "--silent", "--show-error", "--output", "/dev/null",
+					"--cacert", restAPICAMountPath + "/ca.crt",
+					"--resolve", fmt.Sprintf("%s:%d:127.0.0.1", host, listenerPort),
+					fmt.Sprintf("https://%s:%d%s", host, listenerPort, subjectsPath),
+				},
 			},
 		}
-	} else {
+	case probes.ReadinessCheck == strimziregistryoperatorv1alpha1.ReadinessCheckSubjects && scheme == v1.URISchemeHTTP:
 		readinessHandler = v1.ProbeHandler{
 			HTTPGet: &v1.HTTPGetAction{
-				Path:   "/",
+				Path:   subjectsPath,
 				Port:   intstr.IntOrString{IntVal: listenerPort},
 				Scheme: v1.URISchemeHTTP,
 			},
 		}
-		startupHandler = v1.ProbeHandler{
+	case scheme == v1.URISchemeHTTPS:
+		// Self-signed cert: TCPSocket avoids TLS verification failures.
+		readinessHandler = v1.ProbeHandler{
+			TCPSocket: &v1.TCPSocketAction{
+				Port: intstr.IntOrString{IntVal: listenerPort},
+			},
+		}
+	default:
+		readinessHandler = v1.ProbeHandler{
 			HTTPGet: &v1.HTTPGetAction{
-				Path:   "/",
+				Path:   flavor.healthPath(),
 				Port:   intstr.IntOrString{IntVal: listenerPort},
 				Scheme: v1.URISchemeHTTP,
 			},
@@ -384,6 +506,7 @@ func buildProbes(listenerPort int32, scheme v1.URIScheme) (*v1.Probe, *v1.Probe,
 		TimeoutSeconds:      5,
 		FailureThreshold:    3,
 	}
+	applyProbeTiming(readinessProbe, probes.Readiness)
 
 	livenessProbe := &v1.Probe{
 		ProbeHandler: v1.ProbeHandler{
@@ -396,18 +519,54 @@ func buildProbes(listenerPort int32, scheme v1.URIScheme) (*v1.Probe, *v1.Probe,
 		TimeoutSeconds:      5,
 		FailureThreshold:    3,
 	}
+	applyProbeTiming(livenessProbe, probes.Liveness)
 
 	startupProbe := &v1.Probe{
-		ProbeHandler:        startupHandler,
+		ProbeHandler:        *readinessHandler.DeepCopy(),
 		InitialDelaySeconds: 30,
 		PeriodSeconds:       10,
 		TimeoutSeconds:      5,
 		FailureThreshold:    3,
 	}
+	applyProbeTiming(startupProbe, probes.Startup)
 
 	return readinessProbe, livenessProbe, startupProbe
 }
 
+// applyProbeTiming overrides the probe timings with the values set in the CR.
+func applyProbeTiming(probe *v1.Probe, timing *strimziregistryoperatorv1alpha1.ProbeTiming) {
+	if timing == nil {
+		return
+	}
+	if timing.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *timing.InitialDelaySeconds
+	}
+	if timing.PeriodSeconds != nil {
+		probe.PeriodSeconds = *timing.PeriodSeconds
+	}
+	if timing.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *timing.TimeoutSeconds
+	}
+	if timing.FailureThreshold != nil {
+		probe.FailureThreshold = *timing.FailureThreshold
+	}
+}
+
+// restAPICAKnown reports whether the CA signing the REST API certificate is known: the
+// Strimzi cluster CA for the generated certificate, connection.caSecretName for a custom one.
+func restAPICAKnown(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return instance.Spec.TLSSecretName == "" ||
+		(instance.Spec.Connection != nil && instance.Spec.Connection.CASecretName != "")
+}
+
+// needsRESTAPICAVolume reports whether the CA certificate of the REST API must be
+// mounted into the Schema Registry container for the HTTPS readiness check.
+func needsRESTAPICAVolume(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return instance.Spec.SecureHTTP && instance.Spec.Probes != nil &&
+		instance.Spec.Probes.ReadinessCheck == strimziregistryoperatorv1alpha1.ReadinessCheckSubjects &&
+		restAPICAKnown(instance)
+}
+
 func (r *StrimziSchemaRegistryReconciler) getKafkaBootstrapServers(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
 	ctx context.Context, logger logr.Logger) (string, string, error) {
 	// Get Kafka cluster name. Finding KafkaUser CR with name equal to our SchemaRegistry
@@ -449,7 +608,8 @@ func (r *StrimziSchemaRegistryReconciler) getKafkaBootstrapServers(instance *str
 	return "", "", go_err.New("cant find bootstrap address")
 }
 
-// createSecret creates or returns an up-to-date JKS secret for the Schema Registry.
+// createSecret creates or returns an up-to-date Kafka store secret for the Schema Registry,
+// in the store format of its flavor.
 // Returns the secret, a bool indicating whether a new secret was created (as opposed
 // to being already up-to-date), and any error encountered.
 func (r *StrimziSchemaRegistryReconciler) createSecret(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
@@ -489,13 +649,15 @@ func (r *StrimziSchemaRegistryReconciler) createSecret(instance *strimziregistry
 		return nil, false, go_err.New("user.password field is missing from KafkaUser secret; this field is required for keystore creation")
 	}
 	userPassword := string(userPasswordData)
+	format := flavorFor(instance).storeFormat()
 
 	jks_secret := &v1.Secret{}
 	jks_secret_name := instance.Name + jksSecretSuffix
 	err := r.Get(ctx, types.NamespacedName{Name: jks_secret_name, Namespace: instance.Namespace}, jks_secret)
 	if err == nil {
 		if jks_secret.Annotations[CAVersionKey] == clusterSecret.ResourceVersion &&
-			jks_secret.Annotations[userVersionKey] == userSecret.ResourceVersion {
+			jks_secret.Annotations[userVersionKey] == userSecret.ResourceVersion &&
+			secretStoreFormat(jks_secret) == format {
 			logger.V(1).Info("JKS secret is up-to-date")
 			// Return the existing secret (not nil) so the caller has it for
 			// updateDeployment without needing to re-fetch.
@@ -511,15 +673,32 @@ func (r *StrimziSchemaRegistryReconciler) createSecret(instance *strimziregistry
 		logger.Error(err, "Failed to get schema registry secret")
 		return nil, false, err
 	}
-	logger.Info("Creating new keystore and truststore", "Secret Name", jks_secret_name)
-	cp := certprocessor.NewCertProcessor(logger)
-	truststore, truststore_password, err := cp.CreateTruststore(clusterCACert, "")
-	if err != nil {
-		return nil, false, err
-	}
-	keystore, keystore_password, err := cp.CreateKeystore(clientCACert, clientCert, clientKey, clientp12, userPassword)
-	if err != nil {
-		return nil, false, err
+	var data map[string][]byte
+	if format == storeFormatPEM {
+		// PEM readers take the Strimzi certificates as they are.
+		logger.Info("Copying Kafka certificates in PEM format", "Secret Name", jks_secret_name)
+		data = map[string][]byte{
+			"ca.crt":   []byte(clusterCACert),
+			"user.crt": []byte(clientCert),
+			"user.key": []byte(clientKey),
+		}
+	} else {
+		logger.Info("Creating new keystore and truststore", "Secret Name", jks_secret_name)
+		cp := certprocessor.NewCertProcessor(logger)
+		truststore, truststore_password, err := cp.CreateTruststore(clusterCACert, "")
+		if err != nil {
+			return nil, false, err
+		}
+		keystore, keystore_password, err := cp.CreateKeystore(clientCACert, clientCert, clientKey, clientp12, userPassword)
+		if err != nil {
+			return nil, false, err
+		}
+		data = map[string][]byte{
+			"truststore.jks":      []byte(truststore),
+			"keystore.jks":        []byte(keystore),
+			"truststore_password": []byte(truststore_password),
+			"keystore_password":   []byte(keystore_password),
+		}
 	}
 	jks_secret = &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -532,15 +711,11 @@ func (r *StrimziSchemaRegistryReconciler) createSecret(instance *strimziregistry
 			Annotations: map[string]string{
 				CAVersionKey:   clusterSecret.ResourceVersion,
 				userVersionKey: userSecret.ResourceVersion,
+				storeFormatKey: string(format),
 			},
 		},
 		Type: v1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"truststore.jks":      []byte(truststore),
-			"keystore.jks":        []byte(keystore),
-			"truststore_password": []byte(truststore_password),
-			"keystore_password":   []byte(keystore_password),
-		},
+		Data: data,
 	}
 	err = ctrl.SetControllerReference(instance, jks_secret, r.Scheme)
 	if err != nil {
@@ -550,30 +725,58 @@ func (r *StrimziSchemaRegistryReconciler) createSecret(instance *strimziregistry
 	return jks_secret, true, nil
 }
 
+// createService builds the desired Service for Schema Registry from spec.service.
 func (r *StrimziSchemaRegistryReconciler) createService(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) (*v1.Service, error) {
-	var port []v1.ServicePort
-	logger.Info("Creating a new Service", "Service.Namespace", instance.Namespace, "Service.Name", instance.Name)
-	if !instance.Spec.SecureHTTP {
-		port = append(port, v1.ServicePort{Name: "http", Protocol: "TCP", Port: 80, TargetPort: intstr.IntOrString{IntVal: 8081}})
-	} else {
-		port = append(port, v1.ServicePort{Name: "https", Protocol: "TCP", Port: 443, TargetPort: intstr.IntOrString{IntVal: 8085}})
+	serviceSpec := instance.Spec.Service
+	if serviceSpec == nil {
+		serviceSpec = &strimziregistryoperatorv1alpha1.ServiceSpec{}
+	}
+	serviceType := serviceSpec.Type
+	if serviceType == "" {
+		serviceType = v1.ServiceTypeClusterIP
+	}
+
+	portName, portNumber := servicePort(instance)
+	targetPort, _ := listenerPortAndScheme(instance)
+	restPort := v1.ServicePort{Name: portName, Protocol: "TCP", Port: portNumber, TargetPort: intstr.FromInt32(targetPort)}
+	if serviceType != v1.ServiceTypeClusterIP && serviceSpec.NodePort != nil {
+		restPort.NodePort = *serviceSpec.NodePort
+	}
+	port := []v1.ServicePort{restPort}
+	if dualListenerEnabled(instance) {
+		port = append(port, v1.ServicePort{Name: "http", Protocol: "TCP", Port: plainHTTPServicePort,
+			TargetPort: intstr.FromInt32(8081)})
 	}
+	if serviceSpec.JMXPort != nil {
+		port = append(port, v1.ServicePort{Name: jmxPortName, Protocol: "TCP", Port: *serviceSpec.JMXPort,
+			TargetPort: intstr.FromInt32(*serviceSpec.JMXPort)})
+	}
+
+	labels := podSelectorLabels(instance.Name)
+	labels["app.kubernetes.io/managed-by"] = "strimzi-registry-operator"
+	if cluster := instance.Labels[strimziClusterLabel]; cluster != "" {
+		labels[strimziClusterLabel] = cluster
+	}
+	maps.Copy(labels, serviceSpec.Labels)
+
 	svc := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      instance.Name,
-			Namespace: instance.Namespace,
-			Labels:    instance.Labels,
-			Annotations: map[string]string{
-				"type": port[0].Name,
-			},
+			Name:        instance.Name,
+			Namespace:   instance.Namespace,
+			Labels:      labels,
+			Annotations: maps.Clone(serviceSpec.Annotations),
 		},
 		Spec: v1.ServiceSpec{
+			Type:  serviceType,
 			Ports: port,
 			Selector: map[string]string{
 				"app": instance.Name,
 			},
 		},
 	}
+	if serviceType == v1.ServiceTypeLoadBalancer {
+		svc.Spec.LoadBalancerSourceRanges = serviceSpec.LoadBalancerSourceRanges
+	}
 	err := ctrl.SetControllerReference(instance, svc, r.Scheme)
 	if err != nil {
 		logger.Error(err, "Failed to set StrimziSchemaRegistryOperator instance as the owner and controller for service")
@@ -615,12 +818,30 @@ func (r *StrimziSchemaRegistryReconciler) createTLSSecret(instance *strimziregis
 			return nil, err
 		}
 	}
-	logger.Info("Creating keystore for TLS secret", "Secret Name", jksTLSSecretName)
 	cp := certprocessor.NewCertProcessor(logger)
-	TLSKeystore, TLSKeystorePassword, err := cp.GenerateTLSforHTTP(clusterCert, clusterKey, "",
-		instance.Name+"."+instance.Namespace)
-	if err != nil {
-		return nil, err
+	var data map[string][]byte
+	if flavorFor(instance).storeFormat() == storeFormatPEM {
+		logger.Info("Creating PEM certificate for TLS secret", "Secret Name", jksTLSSecretName)
+		cert, key, err := cp.GenerateTLSPEMforHTTP(clusterCert, clusterKey, instance.Name+"."+instance.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		data = map[string][]byte{
+			"tls.crt": cert,
+			"tls.key": key,
+		}
+	} else {
+		logger.Info("Creating keystore for TLS secret", "Secret Name", jksTLSSecretName)
+		TLSKeystore, TLSKeystorePassword, err := cp.GenerateTLSforHTTP(clusterCert, clusterKey, "",
+			instance.Name+"."+instance.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		data = map[string][]byte{
+			"tls-keystore.jks":  []byte(TLSKeystore),
+			"keystore_password": []byte(TLSKeystorePassword),
+			"key_password":      []byte(TLSKeystorePassword),
+		}
 	}
 	jksTLSSecret = &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -632,11 +853,7 @@ func (r *StrimziSchemaRegistryReconciler) createTLSSecret(instance *strimziregis
 			},
 		},
 		Type: v1.SecretTypeOpaque,
-		Data: map[string][]byte{
-			"tls-keystore.jks":  []byte(TLSKeystore),
-			"keystore_password": []byte(TLSKeystorePassword),
-			"key_password":      []byte(TLSKeystorePassword),
-		},
+		Data: data,
 	}
 	err = ctrl.SetControllerReference(instance, jksTLSSecret, r.Scheme)
 	if err != nil {
@@ -671,15 +888,15 @@ func (r *StrimziSchemaRegistryReconciler) updateDeployment(instance *strimziregi
 }
 
 // computeSpecHash computes a hash of the relevant spec fields to detect changes.
-// The hash covers CompatibilityLevel, SecureHTTP, HeapOpts, Listener, SecurityProtocol,
-// TLSSecretName, and the full PodTemplateSpec — all fields that affect the deployment spec or service ports.
+// The hash covers SecureHTTP, HeapOpts, Listener, SecurityProtocol, TLSSecretName,
+// DualListener, Flavor, the rolled out Version, TLS, Authentication, Probes and the CA they
+// trust, Availability, the JMX port, the Role and schemas topic, and the full PodTemplateSpec —
+// all fields that affect the pod template or service ports. Replicas is deliberately left out
+// so scaling does not restart pods, and so is CompatibilityLevel, which is applied live through
+// the REST API (see reconcileGlobalCompatibility).
 func computeSpecHash(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (string, error) {
 	h := fnv.New32a()
 
-	if _, err := io.WriteString(h, string(instance.Spec.CompatibilityLevel)); err != nil {
-		return "", fmt.Errorf("failed to write CompatibilityLevel to hash: %w", err)
-	}
-
 	if _, err := fmt.Fprintf(h, "%t", instance.Spec.SecureHTTP); err != nil {
 		return "", fmt.Errorf("failed to write SecureHTTP to hash: %w", err)
 	}
@@ -700,6 +917,108 @@ func computeSpecHash(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegi
 		return "", fmt.Errorf("failed to write TLSSecretName to hash: %w", err)
 	}
 
+	// DualListener is only hashed when enabled, so existing CRs keep their hash.
+	if instance.Spec.DualListener {
+		if _, err := io.WriteString(h, "dualListener"); err != nil {
+			return "", fmt.Errorf("failed to write DualListener to hash: %w", err)
+		}
+	}
+
+	// The managed version and its rollout settings are only hashed when spec.version is set.
+	if version := rolloutVersion(instance); version != "" {
+		if _, err := io.WriteString(h, "version:"+version); err != nil {
+			return "", fmt.Errorf("failed to write Version to hash: %w", err)
+		}
+		if instance.Spec.Upgrade != nil {
+			upgradeJSON, err := json.Marshal(instance.Spec.Upgrade.ProgressDeadlineSeconds)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal Upgrade to JSON for hash: %w", err)
+			}
+			if _, err := h.Write(upgradeJSON); err != nil {
+				return "", fmt.Errorf("failed to write Upgrade to hash: %w", err)
+			}
+		}
+	}
+
+	// The flavor is only hashed when it is not the default, so existing CRs keep their hash.
+	if flavor := instance.Spec.Flavor; flavor != "" && flavor != strimziregistryoperatorv1alpha1.RegistryFlavorConfluent {
+		if _, err := io.WriteString(h, "flavor:"+string(flavor)); err != nil {
+			return "", fmt.Errorf("failed to write Flavor to hash: %w", err)
+		}
+	}
+
+	// Probe overrides are only hashed when set, so CRs without them keep their hash.
+	if instance.Spec.Probes != nil {
+		probesJSON, err := json.Marshal(instance.Spec.Probes)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal Probes to JSON for hash: %w", err)
+		}
+		if _, err := h.Write(probesJSON); err != nil {
+			return "", fmt.Errorf("failed to write Probes to hash: %w", err)
+		}
+	}
+
+	// The CA trusted by the HTTPS readiness check is mounted from connection.caSecretName
+	// when set; it is only hashed then, so other CRs keep their hash.
+	if needsRESTAPICAVolume(instance) && instance.Spec.Connection != nil && instance.Spec.Connection.CASecretName != "" {
+		if _, err := io.WriteString(h, "restAPICA:"+instance.Spec.Connection.CASecretName); err != nil {
+			return "", fmt.Errorf("failed to write the REST API CA to hash: %w", err)
+		}
+	}
+
+	// Availability drives the injected anti-affinity and topology spread constraints.
+	if instance.Spec.Availability != nil {
+		availabilityJSON, err := json.Marshal(instance.Spec.Availability)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal Availability to JSON for hash: %w", err)
+		}
+		if _, err := h.Write(availabilityJSON); err != nil {
+			return "", fmt.Errorf("failed to write Availability to hash: %w", err)
+		}
+	}
+
+	// Client authentication adds env vars and the truststore volume.
+	if instance.Spec.TLS != nil {
+		tlsJSON, err := json.Marshal(instance.Spec.TLS)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal TLS to JSON for hash: %w", err)
+		}
+		if _, err := h.Write(tlsJSON); err != nil {
+			return "", fmt.Errorf("failed to write TLS to hash: %w", err)
+		}
+	}
+
+	// Basic authentication adds env vars and the rendered JAAS volume.
+	if instance.Spec.Authentication != nil {
+		authJSON, err := json.Marshal(instance.Spec.Authentication)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal Authentication to JSON for hash: %w", err)
+		}
+		if _, err := h.Write(authJSON); err != nil {
+			return "", fmt.Errorf("failed to write Authentication to hash: %w", err)
+		}
+	}
+
+	// The JMX port is the only Service setting that reaches the container.
+	if instance.Spec.Service != nil && instance.Spec.Service.JMXPort != nil {
+		if _, err := fmt.Fprintf(h, "jmx:%d", *instance.Spec.Service.JMXPort); err != nil {
+			return "", fmt.Errorf("failed to write JMXPort to hash: %w", err)
+		}
+	}
+
+	// The role and schemas topic set the leader eligibility and topic env vars. They are only
+	// hashed when they are not the defaults, so existing CRs keep their hash.
+	if !leaderEligible(instance) {
+		if _, err := io.WriteString(h, "role:"+string(strimziregistryoperatorv1alpha1.RegistryRoleSecondary)); err != nil {
+			return "", fmt.Errorf("failed to write Role to hash: %w", err)
+		}
+	}
+	if topic := schemasTopic(instance); topic != defaultSchemasTopic {
+		if _, err := io.WriteString(h, "schemasTopic:"+topic); err != nil {
+			return "", fmt.Errorf("failed to write SchemasTopic to hash: %w", err)
+		}
+	}
+
 	// Include the full PodTemplateSpec so that container image, resources,
 	// and other template changes trigger a deployment update.
 	templateJSON, err := json.Marshal(instance.Spec.Template)
@@ -748,11 +1067,17 @@ func (r *StrimziSchemaRegistryReconciler) updateExistingDeployment(
 		}
 	}
 
+	versions, err := r.podTemplateVersions(ctx, instance)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get rendered REST API secrets: %w", err)
+	}
+
 	// Generate desired deployment spec — NO side effects (no secret creation/deletion)
 	desired, err := r.buildDeploymentSpec(instance, kafkaBootstrapServer, kafkaClusterName, jksResourceVersion, TLSSecretName, logger)
 	if err != nil {
 		return nil, false, err
 	}
+	applyPodTemplateVersions(desired, versions)
 
 	// Compute desired spec hash
 	desiredHash, err := computeSpecHash(instance)
@@ -767,8 +1092,23 @@ func (r *StrimziSchemaRegistryReconciler) updateExistingDeployment(
 	}
 	existingHash := existingAnnotations[keyPrefix+"/specHash"]
 
-	// If hash matches, no spec change detected
+	// Once the HorizontalPodAutoscaler owns the replica count it must not be overwritten.
+	autoscaled := instance.Spec.Autoscaling != nil
+
+	// If hash matches, no spec change detected. Replica drift is corrected in place
+	// without touching the pod template, so scaling never restarts pods.
 	if existingHash == desiredHash {
+		if applyPodTemplateVersions(found, versions) {
+			logger.Info("Rendered REST API secret changed, rolling deployment")
+			monitoring.StrimziSchemaRegistrySecretRotationTotal.Inc()
+			return found, true, nil
+		}
+		if !autoscaled && (found.Spec.Replicas == nil || *found.Spec.Replicas != *desired.Spec.Replicas) {
+			logger.Info("Replica count changed, scaling deployment",
+				"replicas", *desired.Spec.Replicas)
+			found.Spec.Replicas = desired.Spec.Replicas
+			return found, true, nil
+		}
 		return found, false, nil
 	}
 
@@ -776,7 +1116,17 @@ func (r *StrimziSchemaRegistryReconciler) updateExistingDeployment(
 		"oldHash", existingHash, "newHash", desiredHash)
 
 	// Update the deployment spec to match desired state
+	if autoscaled {
+		desired.Spec.Replicas = found.Spec.Replicas
+	}
+	// The selector is immutable: keep the one the Deployment was created with, which
+	// migrateDeploymentSelector has freed from the version label.
+	selector := found.Spec.Selector
 	found.Spec = desired.Spec
+	if selector != nil {
+		found.Spec.Selector = selector
+		maps.Copy(found.Spec.Template.Labels, selector.MatchLabels)
+	}
 
 	// Update annotations to trigger rollout
 	if found.Spec.Template.Annotations == nil {
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/logr"
@@ -25,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -63,7 +65,7 @@ func TestComputeSpecHash(t *testing.T) {
 		}
 	})
 
-	t.Run("different CompatibilityLevel produces different hash", func(t *testing.T) {
+	t.Run("CompatibilityLevel does not change the hash", func(t *testing.T) {
 		inst1 := newTestInstance()
 		inst2 := newTestInstance()
 		inst2.Spec.CompatibilityLevel = "forward"
@@ -71,8 +73,8 @@ func TestComputeSpecHash(t *testing.T) {
 		hash1, _ := computeSpecHash(inst1)
 		hash2, _ := computeSpecHash(inst2)
 
-		if hash1 == hash2 {
-			t.Errorf("expected different hashes for different CompatibilityLevel: both %q", hash1)
+		if hash1 != hash2 {
+			t.Errorf("CompatibilityLevel is applied through the REST API and must not restart pods: %q != %q", hash1, hash2)
 		}
 	})
 
@@ -435,3 +437,140 @@ func TestRenewTLSSecret_NoOp(t *testing.T) {
 		}
 	})
 }
+
+func TestBuildProbes(t *testing.T) {
+	t.Run("HTTP listener check uses HTTP GET on root", func(t *testing.T) {
+		inst := newTestInstance()
+		readiness, liveness, startup := buildProbes(inst)
+		if readiness.HTTPGet == nil || readiness.HTTPGet.Path != "/" || readiness.HTTPGet.Port.IntVal != 8081 {
+			t.Errorf("unexpected readiness handler: %+v", readiness.ProbeHandler)
+		}
+		if liveness.TCPSocket == nil {
+			t.Error("liveness probe should use TCPSocket")
+		}
+		if startup.HTTPGet == nil {
+			t.Error("startup probe should mirror the readiness handler")
+		}
+		if readiness.InitialDelaySeconds != 30 || liveness.PeriodSeconds != 15 {
+			t.Error("default timings changed unexpectedly")
+		}
+	})
+
+	t.Run("HTTPS listener check uses TCPSocket", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.SecureHTTP = true
+		readiness, _, _ := buildProbes(inst)
+		if readiness.TCPSocket == nil || readiness.TCPSocket.Port.IntVal != 8085 {
+			t.Errorf("unexpected readiness handler: %+v", readiness.ProbeHandler)
+		}
+	})
+
+	t.Run("HTTP subjects check queries /subjects", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{
+			ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects,
+		}
+		readiness, _, _ := buildProbes(inst)
+		if readiness.HTTPGet == nil || readiness.HTTPGet.Path != "/subjects" {
+			t.Errorf("unexpected readiness handler: %+v", readiness.ProbeHandler)
+		}
+		if needsRESTAPICAVolume(inst) {
+			t.Error("REST API CA volume is only needed over HTTPS")
+		}
+	})
+
+	t.Run("HTTPS subjects check execs curl with the cluster CA", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.SecureHTTP = true
+		inst.Spec.TLSSecretName = ""
+		inst.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{
+			ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects,
+		}
+		readiness, _, startup := buildProbes(inst)
+		if readiness.Exec == nil || startup.Exec == nil {
+			t.Fatalf("expected exec probes, got %+v", readiness.ProbeHandler)
+		}
+		cmd := strings.Join(readiness.Exec.Command, " ")
+		for _, want := range []string{
+			"--cacert /var/rest-api-ca/ca.crt",
+			"--resolve test-sr.default.svc:8085:127.0.0.1",
+			"https://test-sr.default.svc:8085/subjects",
+		} {
+			if !strings.Contains(cmd, want) {
+				t.Errorf("probe command %q does not contain %q", cmd, want)
+			}
+		}
+		if !needsRESTAPICAVolume(inst) {
+			t.Error("expected the REST API CA volume to be required")
+		}
+		volumes, mounts := buildPodVolumes(inst, "test-sr-tls", "kafka")
+		if volumes[len(volumes)-1].Secret.SecretName != "kafka-cluster-ca-cert" ||
+			mounts[len(mounts)-1].MountPath != "/var/rest-api-ca" {
+			t.Errorf("unexpected REST API CA volume: %+v / %+v", volumes[len(volumes)-1], mounts[len(mounts)-1])
+		}
+	})
+
+	t.Run("HTTPS subjects check trusts the CA of a custom certificate", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.SecureHTTP = true
+		inst.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{
+			ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects,
+		}
+		inst.Spec.Connection = &strimziregistryoperatorv1alpha1.ConnectionSpec{CASecretName: "corporate-ca"}
+		readiness, _, _ := buildProbes(inst)
+		if readiness.Exec == nil {
+			t.Fatalf("expected an exec probe, got %+v", readiness.ProbeHandler)
+		}
+		volumes, _ := buildPodVolumes(inst, "test-tls-secret", "kafka")
+		if volumes[len(volumes)-1].Secret.SecretName != "corporate-ca" {
+			t.Errorf("expected the CA of connection.caSecretName, got %+v", volumes[len(volumes)-1])
+		}
+	})
+
+	t.Run("HTTPS subjects check of a custom certificate with an unknown CA uses TCP", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.SecureHTTP = true
+		inst.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{
+			ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects,
+		}
+		readiness, _, _ := buildProbes(inst)
+		if readiness.TCPSocket == nil {
+			t.Errorf("expected a TCP check, got %+v", readiness.ProbeHandler)
+		}
+		if needsRESTAPICAVolume(inst) {
+			t.Error("no CA volume is needed without a known CA")
+		}
+	})
+
+	t.Run("timing overrides are applied per probe", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{
+			Readiness: &strimziregistryoperatorv1alpha1.ProbeTiming{InitialDelaySeconds: ptr.To[int32](5)},
+			Liveness:  &strimziregistryoperatorv1alpha1.ProbeTiming{FailureThreshold: ptr.To[int32](10)},
+			Startup:   &strimziregistryoperatorv1alpha1.ProbeTiming{PeriodSeconds: ptr.To[int32](2), TimeoutSeconds: ptr.To[int32](1)},
+		}
+		readiness, liveness, startup := buildProbes(inst)
+		if readiness.InitialDelaySeconds != 5 || readiness.PeriodSeconds != 10 {
+			t.Errorf("unexpected readiness timings: %+v", readiness)
+		}
+		if liveness.FailureThreshold != 10 || liveness.InitialDelaySeconds != 30 {
+			t.Errorf("unexpected liveness timings: %+v", liveness)
+		}
+		if startup.PeriodSeconds != 2 || startup.TimeoutSeconds != 1 {
+			t.Errorf("unexpected startup timings: %+v", startup)
+		}
+	})
+
+	t.Run("probe overrides change the spec hash", func(t *testing.T) {
+		inst1 := newTestInstance()
+		inst2 := newTestInstance()
+		inst2.Spec.Probes = &strimziregistryoperatorv1alpha1.ProbesSpec{
+			ReadinessCheck: strimziregistryoperatorv1alpha1.ReadinessCheckSubjects,
+		}
+		hash1, _ := computeSpecHash(inst1)
+		hash2, _ := computeSpecHash(inst2)
+		if hash1 == hash2 {
+			t.Errorf("expected different hashes for different probe settings: both %q", hash1)
+		}
+	})
+}
@@ -0,0 +1,368 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	kafka "github.com/scholzj/strimzi-go/pkg/apis/kafka.strimzi.io/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// kafkaClientRegistryLabel on a KafkaConnect or KafkaBridge names the StrimziSchemaRegistry
+	// it is wired to. The registry connection ConfigMap and Secret must exist in its namespace.
+	kafkaClientRegistryLabel = keyPrefix + "/registry"
+	// kafkaClientVersionKey is the pod template annotation rolling the Strimzi pods when the
+	// connection Secret changes (CA rotation, new credentials).
+	kafkaClientVersionKey = keyPrefix + "/connectionVersion"
+
+	kafkaClientVolumeName = "schema-registry-connection"
+	kafkaClientMountPath  = "/mnt/schema-registry"
+
+	registryURLEnv           = "SCHEMA_REGISTRY_URL"
+	registryTruststorePwdEnv = "SCHEMA_REGISTRY_TRUSTSTORE_PASSWORD"
+	registryUserInfoEnv      = "SCHEMA_REGISTRY_BASIC_AUTH_USER_INFO"
+)
+
+// converterPrefixes are the KafkaConnect converters configured with the registry settings.
+var converterPrefixes = []string{"key.converter.", "value.converter."}
+
+// converterSettings are the converter settings managed by the operator. Settings that are
+// no longer desired (TLS or Basic authentication disabled) are removed from spec.config.
+var converterSettings = []string{
+	"schema.registry.url",
+	"schema.registry.ssl.truststore.location",
+	"schema.registry.ssl.truststore.type",
+	"schema.registry.ssl.truststore.password",
+	"basic.auth.credentials.source",
+	"basic.auth.user.info",
+}
+
+// managedClientEnv are the container environment variables managed by the operator.
+var managedClientEnv = []string{registryURLEnv, registryTruststorePwdEnv, registryUserInfoEnv}
+
+// converterConfig returns the converter settings for the registry connection. Secrets are
+// read through the Strimzi environment variable config provider, never stored in spec.config.
+func converterConfig(configMap *v1.ConfigMap, secret *v1.Secret) map[string]string {
+	settings := map[string]string{"schema.registry.url": configMap.Data["url"]}
+	if _, ok := secret.Data["ca.crt"]; ok {
+		settings["schema.registry.ssl.truststore.location"] = kafkaClientMountPath + "/truststore.p12"
+		settings["schema.registry.ssl.truststore.type"] = "PKCS12"
+		settings["schema.registry.ssl.truststore.password"] = "${strimzienv:" + registryTruststorePwdEnv + "}"
+	}
+	if _, ok := secret.Data["basic.auth.user.info"]; ok {
+		settings["basic.auth.credentials.source"] = "USER_INFO"
+		settings["basic.auth.user.info"] = "${strimzienv:" + registryUserInfoEnv + "}"
+	}
+	config := map[string]string{}
+	for _, prefix := range converterPrefixes {
+		for key, value := range settings {
+			config[prefix+key] = value
+		}
+	}
+	return config
+}
+
+// applyConverterConfig sets the desired converter settings and removes the managed ones
+// that are no longer desired. It returns true when config was modified.
+func applyConverterConfig(config *kafka.MapStringObject, desired map[string]string) bool {
+	changed := false
+	if *config == nil {
+		*config = kafka.MapStringObject{}
+	}
+	for _, prefix := range converterPrefixes {
+		for _, setting := range converterSettings {
+			key := prefix + setting
+			current, exists := (*config)[key]
+			value, wanted := desired[key]
+			switch {
+			case wanted && (!exists || fmt.Sprint(current) != value):
+				(*config)[key] = value
+				changed = true
+			case !wanted && exists:
+				delete(*config, key)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// kafkaClientEnv returns the environment variables exposing the registry connection to the
+// Strimzi container.
+func kafkaClientEnv(configMap *v1.ConfigMap, secret *v1.Secret) []kafka.ContainerEnvVar {
+	env := []kafka.ContainerEnvVar{{Name: registryURLEnv, Value: configMap.Data["url"]}}
+	if _, ok := secret.Data["truststore.password"]; ok {
+		env = append(env, secretKeyEnv(registryTruststorePwdEnv, secret.Name, "truststore.password"))
+	}
+	if _, ok := secret.Data["basic.auth.user.info"]; ok {
+		env = append(env, secretKeyEnv(registryUserInfoEnv, secret.Name, "basic.auth.user.info"))
+	}
+	return env
+}
+
+// secretKeyEnv returns a Strimzi container environment variable read from a secret key.
+func secretKeyEnv(name, secret, key string) kafka.ContainerEnvVar {
+	return kafka.ContainerEnvVar{Name: name, ValueFrom: &kafka.ContainerEnvVarSource{
+		SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: secret}, Key: key},
+	}}
+}
+
+// applyContainerTemplate sets the managed environment variables and the connection volume
+// mount (nil when the registry does not use TLS) on a Strimzi container template.
+func applyContainerTemplate(template **kafka.ContainerTemplate, env []kafka.ContainerEnvVar, mount *v1.VolumeMount) bool {
+	if *template == nil {
+		*template = &kafka.ContainerTemplate{}
+	}
+	tpl := *template
+	changed := false
+
+	kept := make([]kafka.ContainerEnvVar, 0, len(tpl.Env))
+	for _, e := range tpl.Env {
+		if !slices.Contains(managedClientEnv, e.Name) {
+			kept = append(kept, e)
+		}
+	}
+	kept = append(kept, env...)
+	if !equality.Semantic.DeepEqual(kept, tpl.Env) {
+		tpl.Env = kept
+		changed = true
+	}
+
+	mounts := make([]v1.VolumeMount, 0, len(tpl.VolumeMounts))
+	for _, m := range tpl.VolumeMounts {
+		if m.Name != kafkaClientVolumeName {
+			mounts = append(mounts, m)
+		}
+	}
+	if mount != nil {
+		mounts = append(mounts, *mount)
+	}
+	if !equality.Semantic.DeepEqual(mounts, tpl.VolumeMounts) {
+		tpl.VolumeMounts = mounts
+		changed = true
+	}
+	return changed
+}
+
+// applyPodTemplate sets the connection volume (nil when the registry does not use TLS) and
+// the connection version annotation on a Strimzi pod template.
+func applyPodTemplate(template **kafka.PodTemplate, volume *kafka.AdditionalVolume, version string) bool {
+	if *template == nil {
+		*template = &kafka.PodTemplate{}
+	}
+	tpl := *template
+	changed := false
+
+	volumes := make([]kafka.AdditionalVolume, 0, len(tpl.Volumes))
+	for _, vol := range tpl.Volumes {
+		if vol.Name != kafkaClientVolumeName {
+			volumes = append(volumes, vol)
+		}
+	}
+	if volume != nil {
+		volumes = append(volumes, *volume)
+	}
+	if !equality.Semantic.DeepEqual(volumes, tpl.Volumes) {
+		tpl.Volumes = volumes
+		changed = true
+	}
+
+	if tpl.Metadata == nil {
+		tpl.Metadata = &kafka.MetadataTemplate{}
+	}
+	if tpl.Metadata.Annotations[kafkaClientVersionKey] != version {
+		if tpl.Metadata.Annotations == nil {
+			tpl.Metadata.Annotations = map[string]string{}
+		}
+		tpl.Metadata.Annotations[kafkaClientVersionKey] = version
+		changed = true
+	}
+	return changed
+}
+
+// kafkaClientVolume returns the connection Secret volume and mount, or nil when the
+// connection Secret holds no truststore.
+func kafkaClientVolume(secret *v1.Secret) (*kafka.AdditionalVolume, *v1.VolumeMount) {
+	if _, ok := secret.Data["ca.crt"]; !ok {
+		return nil, nil
+	}
+	volume := &kafka.AdditionalVolume{
+		Name:   kafkaClientVolumeName,
+		Secret: &v1.SecretVolumeSource{SecretName: secret.Name},
+	}
+	mount := &v1.VolumeMount{Name: kafkaClientVolumeName, MountPath: kafkaClientMountPath, ReadOnly: true}
+	return volume, mount
+}
+
+// wireKafkaConnect configures the converters of a KafkaConnect with the registry connection.
+// It returns true when the KafkaConnect was modified.
+func wireKafkaConnect(connect *kafka.KafkaConnect, configMap *v1.ConfigMap, secret *v1.Secret, version string) bool {
+	if connect.Spec == nil {
+		connect.Spec = &kafka.KafkaConnectSpec{}
+	}
+	spec := connect.Spec
+	changed := applyConverterConfig(&spec.Config, converterConfig(configMap, secret))
+	if spec.Template == nil {
+		spec.Template = &kafka.KafkaConnectTemplate{}
+	}
+	volume, mount := kafkaClientVolume(secret)
+	if applyContainerTemplate(&spec.Template.ConnectContainer, kafkaClientEnv(configMap, secret), mount) {
+		changed = true
+	}
+	if applyPodTemplate(&spec.Template.Pod, volume, version) {
+		changed = true
+	}
+	return changed
+}
+
+// wireKafkaBridge exposes the registry connection to the KafkaBridge container through
+// environment variables and the mounted truststore. The bridge has no converter settings.
+// It returns true when the KafkaBridge was modified.
+func wireKafkaBridge(bridge *kafka.KafkaBridge, configMap *v1.ConfigMap, secret *v1.Secret, version string) bool {
+	if bridge.Spec == nil {
+		bridge.Spec = &kafka.KafkaBridgeSpec{}
+	}
+	spec := bridge.Spec
+	if spec.Template == nil {
+		spec.Template = &kafka.KafkaBridgeTemplate{}
+	}
+	volume, mount := kafkaClientVolume(secret)
+	changed := applyContainerTemplate(&spec.Template.BridgeContainer, kafkaClientEnv(configMap, secret), mount)
+	if applyPodTemplate(&spec.Template.Pod, volume, version) {
+		changed = true
+	}
+	return changed
+}
+
+// reconcileKafkaClients wires the KafkaConnect and KafkaBridge resources labelled with the
+// registry name, in the registry namespace and in the namespaces the connection settings are
+// copied to. The Strimzi pods roll when the connection Secret changes.
+func (r *StrimziSchemaRegistryReconciler) reconcileKafkaClients(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger,
+	configMap *v1.ConfigMap, secret *v1.Secret) error {
+	if !r.AvailableAPIs.KafkaConnect && !r.AvailableAPIs.KafkaBridge {
+		return nil
+	}
+	namespaces, err := r.connectionNamespaces(ctx, instance)
+	if err != nil {
+		return err
+	}
+	// Copies share the source Secret data, so its version identifies the content everywhere.
+	version := secret.ResourceVersion
+	selector := client.MatchingLabels{kafkaClientRegistryLabel: instance.Name}
+
+	var clients []client.Object
+	if r.AvailableAPIs.KafkaConnect {
+		list := &kafka.KafkaConnectList{}
+		if err := r.List(ctx, list, selector); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			clients = append(clients, &list.Items[i])
+		}
+	}
+	if r.AvailableAPIs.KafkaBridge {
+		list := &kafka.KafkaBridgeList{}
+		if err := r.List(ctx, list, selector); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			clients = append(clients, &list.Items[i])
+		}
+	}
+
+	for _, obj := range clients {
+		if !namespaces[obj.GetNamespace()] {
+			continue
+		}
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		var changed bool
+		switch o := obj.(type) {
+		case *kafka.KafkaConnect:
+			changed = wireKafkaConnect(o, configMap, secret, version)
+		case *kafka.KafkaBridge:
+			changed = wireKafkaBridge(o, configMap, secret, version)
+		}
+		if !changed {
+			continue
+		}
+		logger.Info("Wiring registry connection", "Kind", fmt.Sprintf("%T", obj), "Name", obj.GetName(), "Namespace", obj.GetNamespace())
+		if err := r.Patch(ctx, obj, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// connectionNamespaces returns the namespaces holding the registry connection ConfigMap:
+// the registry namespace and those it is copied to.
+func (r *StrimziSchemaRegistryReconciler) connectionNamespaces(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (map[string]bool, error) {
+	configMaps := &v1.ConfigMapList{}
+	err := r.List(ctx, configMaps, client.MatchingLabels{
+		connectionSourceNamespaceLabel: instance.Namespace,
+		connectionSourceNameLabel:      instance.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	namespaces := map[string]bool{instance.Namespace: true}
+	for _, cm := range configMaps.Items {
+		if cm.Name == ConnectionName(instance.Name) {
+			namespaces[cm.Namespace] = true
+		}
+	}
+	return namespaces, nil
+}
+
+// requestsForKafkaClient maps a labelled KafkaConnect or KafkaBridge to its registry: the
+// CR of that name in the same namespace, or the source of the connection copy found there.
+func (r *StrimziSchemaRegistryReconciler) requestsForKafkaClient(ctx context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[kafkaClientRegistryLabel]
+	if name == "" {
+		return nil
+	}
+	key := types.NamespacedName{Name: name, Namespace: obj.GetNamespace()}
+	if err := r.Get(ctx, key, &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}); err == nil {
+		return []reconcile.Request{{NamespacedName: key}}
+	} else if !errors.IsNotFound(err) {
+		return nil
+	}
+	configMap := &v1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ConnectionName(name), Namespace: obj.GetNamespace()}, configMap); err != nil {
+		return nil
+	}
+	source := types.NamespacedName{
+		Name:      configMap.Labels[connectionSourceNameLabel],
+		Namespace: configMap.Labels[connectionSourceNamespaceLabel],
+	}
+	if source.Name != name || source.Namespace == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: source}}
+}
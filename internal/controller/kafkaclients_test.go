@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	kafka "github.com/scholzj/strimzi-go/pkg/apis/kafka.strimzi.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newConnectionObjects(tls, basicAuth bool) (*corev1.ConfigMap, *corev1.Secret) {
+	inst := newTestInstance()
+	inst.Spec.SecureHTTP = tls
+	configMap := buildConnectionConfigMap(inst)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ConnectionName(inst.Name), Namespace: inst.Namespace, ResourceVersion: "7"},
+		Data:       map[string][]byte{},
+	}
+	if tls {
+		secret.Data["ca.crt"] = []byte("CA")
+		secret.Data["truststore.p12"] = []byte("P12")
+		secret.Data["truststore.password"] = []byte("changeit")
+	}
+	if basicAuth {
+		secret.Data["basic.auth.user.info"] = []byte("ci:pw")
+	}
+	return configMap, secret
+}
+
+func TestWireKafkaConnect(t *testing.T) {
+	configMap, secret := newConnectionObjects(true, true)
+	connect := &kafka.KafkaConnect{Spec: &kafka.KafkaConnectSpec{
+		Config: kafka.MapStringObject{"group.id": "connect", "value.converter": "io.confluent.connect.avro.AvroConverter"},
+		Template: &kafka.KafkaConnectTemplate{ConnectContainer: &kafka.ContainerTemplate{
+			Env: []kafka.ContainerEnvVar{{Name: "OTHER", Value: "1"}},
+		}},
+	}}
+
+	if !wireKafkaConnect(connect, configMap, secret, "7") {
+		t.Fatal("expected the KafkaConnect to be modified")
+	}
+	config := connect.Spec.Config
+	if config["value.converter.schema.registry.url"] != "https://test-sr.default.svc:443" ||
+		config["key.converter.schema.registry.ssl.truststore.location"] != kafkaClientMountPath+"/truststore.p12" ||
+		config["value.converter.basic.auth.user.info"] != "${strimzienv:"+registryUserInfoEnv+"}" {
+		t.Errorf("unexpected config: %v", config)
+	}
+	if config["group.id"] != "connect" || config["value.converter"] != "io.confluent.connect.avro.AvroConverter" {
+		t.Error("user config must be kept")
+	}
+	container := connect.Spec.Template.ConnectContainer
+	if len(container.Env) != 4 || container.Env[0].Name != "OTHER" {
+		t.Errorf("unexpected env: %+v", container.Env)
+	}
+	if len(container.VolumeMounts) != 1 || len(connect.Spec.Template.Pod.Volumes) != 1 ||
+		connect.Spec.Template.Pod.Volumes[0].Secret.SecretName != secret.Name {
+		t.Errorf("expected the connection volume, got %+v", connect.Spec.Template.Pod.Volumes)
+	}
+	if connect.Spec.Template.Pod.Metadata.Annotations[kafkaClientVersionKey] != "7" {
+		t.Error("expected the connection version annotation")
+	}
+
+	if wireKafkaConnect(connect, configMap, secret, "7") {
+		t.Error("wiring must be idempotent")
+	}
+	if !wireKafkaConnect(connect, configMap, secret, "8") {
+		t.Error("a new connection version must roll the pods")
+	}
+
+	t.Run("settings of disabled features are removed", func(t *testing.T) {
+		configMap, secret := newConnectionObjects(false, false)
+		if !wireKafkaConnect(connect, configMap, secret, "9") {
+			t.Fatal("expected the KafkaConnect to be modified")
+		}
+		if _, ok := connect.Spec.Config["key.converter.schema.registry.ssl.truststore.location"]; ok {
+			t.Error("truststore settings must be removed")
+		}
+		if _, ok := connect.Spec.Config["value.converter.basic.auth.user.info"]; ok {
+			t.Error("basic auth settings must be removed")
+		}
+		if len(connect.Spec.Template.Pod.Volumes) != 0 || len(connect.Spec.Template.ConnectContainer.VolumeMounts) != 0 {
+			t.Error("connection volume must be removed")
+		}
+		if len(connect.Spec.Template.ConnectContainer.Env) != 2 {
+			t.Errorf("expected OTHER and the registry URL, got %+v", connect.Spec.Template.ConnectContainer.Env)
+		}
+	})
+}
+
+func TestWireKafkaBridge(t *testing.T) {
+	configMap, secret := newConnectionObjects(true, false)
+	bridge := &kafka.KafkaBridge{Spec: &kafka.KafkaBridgeSpec{BootstrapServers: "kafka:9093"}}
+	if !wireKafkaBridge(bridge, configMap, secret, "7") {
+		t.Fatal("expected the KafkaBridge to be modified")
+	}
+	container := bridge.Spec.Template.BridgeContainer
+	if len(container.Env) != 2 || container.Env[0].Value != "https://test-sr.default.svc:443" ||
+		container.Env[1].ValueFrom.SecretKeyRef.Key != "truststore.password" {
+		t.Errorf("unexpected env: %+v", container.Env)
+	}
+	if len(bridge.Spec.Config) != 0 {
+		t.Error("the bridge config must not be modified")
+	}
+}
+
+func TestReconcileKafkaClients(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReconciler()
+	_ = kafka.AddToScheme(r.Scheme)
+	r.AvailableAPIs = AvailableAPIs{KafkaConnect: true, KafkaBridge: true}
+	inst := newTestInstance()
+	configMap, secret := newConnectionObjects(false, false)
+
+	copyConfigMap := configMap.DeepCopy()
+	copyConfigMap.Namespace = "apps"
+	labelled := func(name, namespace string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{kafkaClientRegistryLabel: "test-sr"}}
+	}
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(
+		inst, configMap, copyConfigMap,
+		&kafka.KafkaConnect{ObjectMeta: labelled("local", "default"), Spec: &kafka.KafkaConnectSpec{}},
+		&kafka.KafkaConnect{ObjectMeta: labelled("copied", "apps"), Spec: &kafka.KafkaConnectSpec{}},
+		&kafka.KafkaConnect{ObjectMeta: labelled("unreachable", "other"), Spec: &kafka.KafkaConnectSpec{}},
+		&kafka.KafkaBridge{ObjectMeta: labelled("bridge", "default"), Spec: &kafka.KafkaBridgeSpec{}},
+	).Build()
+
+	if err := r.reconcileKafkaClients(ctx, inst, logr.Discard(), configMap, secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []types.NamespacedName{{Name: "local", Namespace: "default"}, {Name: "copied", Namespace: "apps"}} {
+		connect := &kafka.KafkaConnect{}
+		if err := r.Get(ctx, key, connect); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if connect.Spec.Config["value.converter.schema.registry.url"] != "http://test-sr.default.svc:80" {
+			t.Errorf("%s: expected the registry URL, got %v", key, connect.Spec.Config)
+		}
+	}
+	unreachable := &kafka.KafkaConnect{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "unreachable", Namespace: "other"}, unreachable); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(unreachable.Spec.Config) != 0 {
+		t.Error("a namespace without the connection settings must not be wired")
+	}
+	bridge := &kafka.KafkaBridge{}
+	if err := r.Get(ctx, types.NamespacedName{Name: "bridge", Namespace: "default"}, bridge); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bridge.Spec.Template == nil || len(bridge.Spec.Template.BridgeContainer.Env) != 1 {
+		t.Errorf("expected the bridge to be wired, got %+v", bridge.Spec.Template)
+	}
+
+	t.Run("clients are mapped to their registry", func(t *testing.T) {
+		copied := &kafka.KafkaConnect{ObjectMeta: labelled("copied", "apps")}
+		requests := r.requestsForKafkaClient(ctx, copied)
+		if len(requests) != 1 || requests[0].Name != "test-sr" || requests[0].Namespace != "default" {
+			t.Errorf("unexpected requests: %+v", requests)
+		}
+		if requests := r.requestsForKafkaClient(ctx, &kafka.KafkaConnect{ObjectMeta: labelled("x", "other")}); len(requests) != 0 {
+			t.Errorf("expected no request, got %+v", requests)
+		}
+	})
+}
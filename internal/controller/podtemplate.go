@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// schemaRegistryContainerName is the container name the operator looks for in
+// spec.template. Any other containers (sidecars, log shippers, ...) are left untouched.
+const schemaRegistryContainerName = "schema-registry"
+
+// schemaRegistryContainerIndex returns the index of the Schema Registry container
+// in the pod spec. The container named "schema-registry" is preferred; when no
+// container carries that name, a template with a single container is accepted
+// for backward compatibility. Templates with several containers must name the
+// Schema Registry container explicitly.
+func schemaRegistryContainerIndex(podSpec *v1.PodSpec) (int, error) {
+	if len(podSpec.Containers) == 0 {
+		return -1, fmt.Errorf("template must contain at least one container")
+	}
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == schemaRegistryContainerName {
+			return i, nil
+		}
+	}
+	if len(podSpec.Containers) == 1 {
+		return 0, nil
+	}
+	return -1, fmt.Errorf("template has %d containers but none is named %q",
+		len(podSpec.Containers), schemaRegistryContainerName)
+}
+
+// mergeEnv returns the operator-managed environment followed by the user-defined
+// variables from the template. A user variable that redefines an operator-managed
+// name is rejected, since the operator value would silently win otherwise.
+func mergeEnv(managed, user []v1.EnvVar) ([]v1.EnvVar, error) {
+	names := make(map[string]struct{}, len(managed))
+	for _, env := range managed {
+		names[env.Name] = struct{}{}
+	}
+	merged := append([]v1.EnvVar{}, managed...)
+	for _, env := range user {
+		if _, ok := names[env.Name]; ok {
+			return nil, fmt.Errorf("env var %q in template conflicts with an operator-managed variable", env.Name)
+		}
+		merged = append(merged, env)
+	}
+	return merged, nil
+}
+
+// appendEnvOpts appends opts to the value of the named variable, adding the variable
+// when the template does not define it. A variable set from a source cannot be
+// extended and is rejected.
+func appendEnvOpts(env []v1.EnvVar, name, opts string) ([]v1.EnvVar, error) {
+	if opts == "" {
+		return env, nil
+	}
+	for i := range env {
+		if env[i].Name != name {
+			continue
+		}
+		if env[i].ValueFrom != nil {
+			return nil, fmt.Errorf("env var %q in template must have a literal value, the operator appends %q to it", name, opts)
+		}
+		env[i].Value = strings.TrimSpace(env[i].Value + " " + opts)
+		return env, nil
+	}
+	return append(env, v1.EnvVar{Name: name, Value: opts}), nil
+}
+
+// mergeVolumes returns the operator-managed volumes followed by the user-defined
+// volumes from the template, rejecting user volumes that reuse a managed name.
+func mergeVolumes(managed, user []v1.Volume) ([]v1.Volume, error) {
+	names := make(map[string]struct{}, len(managed))
+	for _, vol := range managed {
+		names[vol.Name] = struct{}{}
+	}
+	merged := append([]v1.Volume{}, managed...)
+	for _, vol := range user {
+		if _, ok := names[vol.Name]; ok {
+			return nil, fmt.Errorf("volume %q in template conflicts with an operator-managed volume", vol.Name)
+		}
+		merged = append(merged, vol)
+	}
+	return merged, nil
+}
+
+// mergeVolumeMounts returns the operator-managed mounts followed by the
+// user-defined mounts from the template. User mounts must not reuse a managed
+// volume name or mount path.
+func mergeVolumeMounts(managed, user []v1.VolumeMount) ([]v1.VolumeMount, error) {
+	names := make(map[string]struct{}, len(managed))
+	paths := make(map[string]struct{}, len(managed))
+	for _, mount := range managed {
+		names[mount.Name] = struct{}{}
+		paths[mount.MountPath] = struct{}{}
+	}
+	merged := append([]v1.VolumeMount{}, managed...)
+	for _, mount := range user {
+		if _, ok := names[mount.Name]; ok {
+			return nil, fmt.Errorf("volume mount %q in template conflicts with an operator-managed mount", mount.Name)
+		}
+		if _, ok := paths[mount.MountPath]; ok {
+			return nil, fmt.Errorf("volume mount path %q in template conflicts with an operator-managed mount", mount.MountPath)
+		}
+		merged = append(merged, mount)
+	}
+	return merged, nil
+}
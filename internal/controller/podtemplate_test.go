@@ -0,0 +1,228 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestReconciler() *StrimziSchemaRegistryReconciler {
+	scheme := runtime.NewScheme()
+	_ = strimziregistryoperatorv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	return &StrimziSchemaRegistryReconciler{Scheme: scheme}
+}
+
+func TestSchemaRegistryContainerIndex(t *testing.T) {
+	t.Run("no containers returns error", func(t *testing.T) {
+		if _, err := schemaRegistryContainerIndex(&corev1.PodSpec{}); err == nil {
+			t.Error("expected error for empty container list")
+		}
+	})
+
+	t.Run("single unnamed container is used", func(t *testing.T) {
+		idx, err := schemaRegistryContainerIndex(&corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "sr"}},
+		})
+		if err != nil || idx != 0 {
+			t.Errorf("expected index 0, got %d (err: %v)", idx, err)
+		}
+	})
+
+	t.Run("container is picked by name after sidecars", func(t *testing.T) {
+		idx, err := schemaRegistryContainerIndex(&corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "envoy"}, {Name: "schema-registry"}},
+		})
+		if err != nil || idx != 1 {
+			t.Errorf("expected index 1, got %d (err: %v)", idx, err)
+		}
+	})
+
+	t.Run("several containers without the expected name returns error", func(t *testing.T) {
+		_, err := schemaRegistryContainerIndex(&corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "envoy"}, {Name: "sr"}},
+		})
+		if err == nil {
+			t.Error("expected error when no container is named schema-registry")
+		}
+	})
+}
+
+func TestMergeEnv(t *testing.T) {
+	managed := []corev1.EnvVar{{Name: "SCHEMA_REGISTRY_LISTENERS", Value: "http://0.0.0.0:8081"}}
+
+	t.Run("user entries are appended after managed ones", func(t *testing.T) {
+		merged, err := mergeEnv(managed, []corev1.EnvVar{{Name: "SCHEMA_REGISTRY_DEBUG", Value: "true"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(merged) != 2 || merged[0].Name != "SCHEMA_REGISTRY_LISTENERS" || merged[1].Name != "SCHEMA_REGISTRY_DEBUG" {
+			t.Errorf("unexpected merge result: %+v", merged)
+		}
+	})
+
+	t.Run("conflicting name returns error", func(t *testing.T) {
+		_, err := mergeEnv(managed, []corev1.EnvVar{{Name: "SCHEMA_REGISTRY_LISTENERS", Value: "http://0.0.0.0:9999"}})
+		if err == nil {
+			t.Error("expected error for conflicting env var")
+		}
+	})
+}
+
+func TestAppendEnvOpts(t *testing.T) {
+	const opts = "-Djava.security.auth.login.config=/jaas.conf"
+
+	t.Run("user value is extended", func(t *testing.T) {
+		env, err := appendEnvOpts([]corev1.EnvVar{{Name: "SCHEMA_REGISTRY_OPTS", Value: "-Dfoo=bar"}}, "SCHEMA_REGISTRY_OPTS", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(env) != 1 || env[0].Value != "-Dfoo=bar "+opts {
+			t.Errorf("unexpected result: %+v", env)
+		}
+	})
+
+	t.Run("missing variable is added", func(t *testing.T) {
+		env, err := appendEnvOpts(nil, "SCHEMA_REGISTRY_OPTS", opts)
+		if err != nil || len(env) != 1 || env[0].Value != opts {
+			t.Errorf("unexpected result: %+v (err: %v)", env, err)
+		}
+	})
+
+	t.Run("variable from a source returns error", func(t *testing.T) {
+		_, err := appendEnvOpts([]corev1.EnvVar{{Name: "SCHEMA_REGISTRY_OPTS", ValueFrom: &corev1.EnvVarSource{}}}, "SCHEMA_REGISTRY_OPTS", opts)
+		if err == nil {
+			t.Error("expected error for a variable set from a source")
+		}
+	})
+}
+
+func TestMergeVolumesAndMounts(t *testing.T) {
+	managedVolumes := []corev1.Volume{{Name: "tls"}}
+	managedMounts := []corev1.VolumeMount{{Name: "tls", MountPath: "/var/schemaregistry"}}
+
+	t.Run("user volumes and mounts are kept", func(t *testing.T) {
+		vols, err := mergeVolumes(managedVolumes, []corev1.Volume{{Name: "ca-bundle"}})
+		if err != nil || len(vols) != 2 {
+			t.Errorf("expected 2 volumes, got %d (err: %v)", len(vols), err)
+		}
+		mounts, err := mergeVolumeMounts(managedMounts, []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ca"}})
+		if err != nil || len(mounts) != 2 {
+			t.Errorf("expected 2 mounts, got %d (err: %v)", len(mounts), err)
+		}
+	})
+
+	t.Run("conflicting volume name returns error", func(t *testing.T) {
+		if _, err := mergeVolumes(managedVolumes, []corev1.Volume{{Name: "tls"}}); err == nil {
+			t.Error("expected error for conflicting volume name")
+		}
+	})
+
+	t.Run("conflicting mount path returns error", func(t *testing.T) {
+		_, err := mergeVolumeMounts(managedMounts, []corev1.VolumeMount{{Name: "other", MountPath: "/var/schemaregistry"}})
+		if err == nil {
+			t.Error("expected error for conflicting mount path")
+		}
+	})
+}
+
+func TestBuildDeploymentSpecPreservesTemplate(t *testing.T) {
+	r := newTestReconciler()
+	inst := newTestInstance()
+	inst.Spec.Template = corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "sidecar", Image: "envoyproxy/envoy:v1.30"},
+				{
+					Name:         "schema-registry",
+					Image:        "confluentinc/cp-schema-registry:7.6.5",
+					Env:          []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-javaagent:/agent/agent.jar"}},
+					VolumeMounts: []corev1.VolumeMount{{Name: "agent", MountPath: "/agent"}},
+				},
+			},
+			Volumes: []corev1.Volume{{Name: "agent"}},
+		},
+	}
+	hashBefore, _ := computeSpecHash(inst)
+
+	dep, err := r.buildDeploymentSpec(inst, "kafka:9093", "kafka", "1", "", logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sidecar := dep.Spec.Template.Spec.Containers[0]
+	if len(sidecar.Env) != 0 || sidecar.ReadinessProbe != nil {
+		t.Error("sidecar container must not be modified")
+	}
+	sr := dep.Spec.Template.Spec.Containers[1]
+	if sr.ReadinessProbe == nil {
+		t.Error("expected probes on the schema-registry container")
+	}
+	if !hasEnv(sr.Env, "JAVA_TOOL_OPTIONS") || !hasEnv(sr.Env, "SCHEMA_REGISTRY_LISTENERS") {
+		t.Errorf("expected both user and managed env vars, got %+v", sr.Env)
+	}
+	if len(sr.VolumeMounts) != 2 || len(dep.Spec.Template.Spec.Volumes) != 2 {
+		t.Errorf("expected managed and user volumes, got mounts=%d volumes=%d",
+			len(sr.VolumeMounts), len(dep.Spec.Template.Spec.Volumes))
+	}
+	if dep.Labels["app.kubernetes.io/version"] != "7.6.5" {
+		t.Errorf("expected version label from schema-registry image, got %q", dep.Labels["app.kubernetes.io/version"])
+	}
+
+	// The CR itself must stay untouched so repeated builds do not conflict.
+	if len(inst.Spec.Template.Spec.Containers[1].Env) != 1 {
+		t.Errorf("buildDeploymentSpec mutated the CR template env: %+v", inst.Spec.Template.Spec.Containers[1].Env)
+	}
+	hashAfter, _ := computeSpecHash(inst)
+	if hashBefore != hashAfter {
+		t.Error("buildDeploymentSpec must not change the CR spec hash")
+	}
+	if _, err := r.buildDeploymentSpec(inst, "kafka:9093", "kafka", "1", "", logr.Discard()); err != nil {
+		t.Errorf("second build should succeed, got: %v", err)
+	}
+}
+
+func TestBuildDeploymentSpecRejectsConflicts(t *testing.T) {
+	r := newTestReconciler()
+	inst := newTestInstance()
+	inst.Spec.Template = corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:  "schema-registry",
+				Image: "confluentinc/cp-schema-registry:7.6.5",
+				Env:   []corev1.EnvVar{{Name: "SCHEMA_REGISTRY_KAFKASTORE_TOPIC", Value: "other"}},
+			}},
+		},
+	}
+	if _, err := r.buildDeploymentSpec(inst, "kafka:9093", "kafka", "1", "", logr.Discard()); err == nil {
+		t.Error("expected validation error for env var conflicting with operator-managed name")
+	}
+}
+
+func hasEnv(env []corev1.EnvVar, name string) bool {
+	for _, e := range env {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
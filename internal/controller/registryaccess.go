@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// registryRefIndex indexes the resources referencing a registry by its "<namespace>/<name>".
+const registryRefIndex = ".spec.registry"
+
+// RegistryClientFactory builds REST API clients. Tests replace it to target a fake registry.
+type RegistryClientFactory func(registryclient.Config) (*registryclient.Client, error)
+
+// registryRefKey returns the registryRefIndex value of a resource referencing a registry.
+func registryRefKey(obj client.Object) []string {
+	var ref strimziregistryoperatorv1alpha1.RegistryReference
+	switch o := obj.(type) {
+	case *strimziregistryoperatorv1alpha1.StrimziSchema:
+		ref = o.Spec.Registry
+	case *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig:
+		ref = o.Spec.Registry
+	case *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup:
+		ref = o.Spec.Registry
+	case *strimziregistryoperatorv1alpha1.StrimziSchemaExporter:
+		ref = o.Spec.Registry
+	default:
+		return nil
+	}
+	return []string{registryReference(ref, obj.GetNamespace()).String()}
+}
+
+// requestsForRegistryRef maps a StrimziSchemaRegistry to the resources of the kind of list
+// referencing it, looked up through registryRefIndex.
+func requestsForRegistryRef(c client.Reader, list client.ObjectList) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		items := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, items, client.MatchingFields{registryRefIndex: client.ObjectKeyFromObject(obj).String()}); err != nil {
+			return nil
+		}
+		var requests []reconcile.Request
+		_ = meta.EachListItem(items, func(item runtime.Object) error {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(item.(client.Object))})
+			return nil
+		})
+		return requests
+	}
+}
+
+// newRegistryClient returns a REST API client for registry, built by factory (registryclient.New when nil).
+func newRegistryClient(ctx context.Context, c client.Reader, factory RegistryClientFactory,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (*registryclient.Client, error) {
+	cfg, err := registryClientConfig(ctx, c, registry)
+	if err != nil {
+		return nil, err
+	}
+	if factory == nil {
+		factory = registryclient.New
+	}
+	return factory(cfg)
+}
+
+// registryReference resolves a RegistryReference relative to the namespace of the referencing resource.
+func registryReference(ref strimziregistryoperatorv1alpha1.RegistryReference, namespace string) types.NamespacedName {
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	return types.NamespacedName{Name: ref.Name, Namespace: namespace}
+}
+
+// registryClientConfig returns how the operator reaches the REST API of a registry: its first
+// listener, trusting the CA that signs the REST API certificate, presenting the KafkaUser
+// certificate of the registry (the one its Kafka keystore is built from) when the REST API
+// verifies client certificates, and authenticated as spec.connection.basicAuthUser when Basic
+// authentication is enabled.
+func registryClientConfig(ctx context.Context, c client.Reader,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (registryclient.Config, error) {
+	cfg := registryclient.Config{URL: listenerStatuses(registry)[0].URL}
+
+	if registry.Spec.SecureHTTP {
+		clusterName, err := getStrimziClusterName(registry)
+		if err != nil {
+			return cfg, err
+		}
+		caName := connectionCASecretName(registry, clusterName)
+		caSecret := &v1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Name: caName, Namespace: registry.Namespace}, caSecret); err != nil {
+			return cfg, fmt.Errorf("failed to get CA secret %s: %w", caName, err)
+		}
+		cfg.CACert = caSecret.Data["ca.crt"]
+	}
+
+	if clientAuthEnabled(registry) {
+		userSecret := &v1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Name: registry.Name, Namespace: registry.Namespace}, userSecret); err != nil {
+			return cfg, fmt.Errorf("failed to get KafkaUser secret %s: %w", registry.Name, err)
+		}
+		cfg.ClientCert = userSecret.Data["user.crt"]
+		cfg.ClientKey = userSecret.Data["user.key"]
+	}
+
+	if basicAuthEnabled(registry) {
+		user := connectionBasicAuthUser(registry)
+		if user == "" {
+			return cfg, fmt.Errorf("the REST API of %s/%s requires Basic authentication: set spec.connection.basicAuthUser",
+				registry.Namespace, registry.Name)
+		}
+		usersName := registry.Spec.Authentication.Basic.UsersSecretName
+		users := &v1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Name: usersName, Namespace: registry.Namespace}, users); err != nil {
+			return cfg, fmt.Errorf("failed to get users secret %s: %w", usersName, err)
+		}
+		entry, ok := users.Data[user]
+		if !ok {
+			return cfg, fmt.Errorf("user %q not found in users secret %s", user, usersName)
+		}
+		cfg.Username = user
+		cfg.Password = basicAuthPassword(entry)
+	}
+	return cfg, nil
+}
+
+// basicAuthPassword returns the password of a users secret entry ("password[,role...]").
+func basicAuthPassword(entry []byte) string {
+	password, _, _ := strings.Cut(strings.TrimRight(string(entry), "\r\n"), ",")
+	return password
+}
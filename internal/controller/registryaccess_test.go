@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Fixtures shared by the tests of the controllers of resources referencing a registry
+// (StrimziSchema, StrimziSchemaSubjectConfig, StrimziSchemaRegistryBackup, StrimziSchemaExporter).
+
+// testStart is the time the clocks of the controller tests start at.
+var testStart = time.Date(2026, 10, 19, 0, 30, 0, 0, time.UTC)
+
+// testClock is a settable clock for the reconcilers.
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+// newReadyRegistry returns the test-sr registry the test resources reference, ready.
+func newReadyRegistry() *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	registry := newTestInstance()
+	registry.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"}}
+	return registry
+}
+
+// fakeRegistryClientFactory returns a RegistryClientFactory whose clients target url, keeping
+// the credentials of the registry they are built for.
+func fakeRegistryClientFactory(url string) RegistryClientFactory {
+	return func(cfg registryclient.Config) (*registryclient.Client, error) {
+		cfg.URL = url
+		return registryclient.New(cfg)
+	}
+}
+
+// seedRegistry registers a schema under each subject.
+func seedRegistry(t *testing.T, registry *testutil.FakeRegistry, subjects ...string) {
+	t.Helper()
+	rc, err := registryclient.New(registryclient.Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, subject := range subjects {
+		if _, err := rc.RegisterSchema(context.Background(), subject, registryclient.Schema{Schema: testAvroSchema}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+// newRegistryResourceClient returns a fake client holding objs, serving the status subresource
+// of the kind of status.
+func newRegistryResourceClient(status client.Object, objs ...client.Object) client.Client {
+	scheme := newTestReconciler().Scheme
+	_ = batchv1.AddToScheme(scheme)
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(status).Build()
+}
+
+// reconcileRegistryResource reconciles default/name with r and reads the resource back into
+// obj, left empty when the reconcile deleted it.
+func reconcileRegistryResource(t *testing.T, r reconcile.Reconciler, c client.Reader, name string,
+	obj client.Object) ctrl.Result {
+	t.Helper()
+	key := types.NamespacedName{Name: name, Namespace: "default"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Get(context.Background(), key, obj); err != nil && !errors.IsNotFound(err) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result
+}
+
+// updateRegistryResource applies mutate to default/name as a spec change.
+func updateRegistryResource[T any, PT interface {
+	*T
+	client.Object
+}](t *testing.T, c client.Client, name string, mutate func(PT)) {
+	t.Helper()
+	obj := PT(new(T))
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, obj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mutate(obj)
+	obj.SetGeneration(obj.GetGeneration() + 1)
+	if err := c.Update(context.Background(), obj); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// expectReady checks the Ready condition among conditions.
+func expectReady(t *testing.T, conditions []metav1.Condition, status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	condition := meta.FindStatusCondition(conditions, "Ready")
+	if condition == nil || condition.Status != status || condition.Reason != reason {
+		t.Errorf("expected Ready %s with reason %s, got %+v", status, reason, condition)
+	}
+}
+
+func TestRequestsForRegistryRef(t *testing.T) {
+	registry := newTestInstance()
+	other := newSharedRegistry()
+	orders := newTestSubjectConfig("orders", "orders-value")
+	payments := newTestSubjectConfig("payments", "payments-value")
+	payments.Spec.Registry = strimziregistryoperatorv1alpha1.RegistryReference{Name: other.Name, Namespace: other.Namespace}
+	c := fake.NewClientBuilder().WithScheme(newTestReconciler().Scheme).WithObjects(orders, payments).
+		WithIndex(&strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{}, registryRefIndex, registryRefKey).Build()
+
+	mapFunc := requestsForRegistryRef(c, &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfigList{})
+	for _, tc := range []struct {
+		registry client.Object
+		want     string
+	}{{registry, "default/orders"}, {other, "default/payments"}} {
+		requests := mapFunc(context.Background(), tc.registry)
+		if len(requests) != 1 || requests[0].String() != tc.want {
+			t.Errorf("expected a request for %s, got %v", tc.want, requests)
+		}
+	}
+	if requests := mapFunc(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "default"}}); len(requests) != 0 {
+		t.Errorf("expected no request, got %v", requests)
+	}
+}
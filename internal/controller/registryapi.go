@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// responsiveCondition reports whether the REST API answers the requests of the operator.
+	responsiveCondition = "RegistryResponsive"
+	// registryAPIResyncPeriod is how often a ready registry is called through its REST API, to
+	// report its responsiveness and revert settings changed outside of the operator.
+	registryAPIResyncPeriod = time.Minute
+)
+
+// reconcileRegistryAPI calls the REST API of a ready registry: it sets the RegistryResponsive
+// condition and, when the registry answers, applies the settings managed through the API.
+// Pods passing their readiness probe may still reject the operator (e.g. wrong credentials),
+// which the condition reports without failing the reconcile.
+// The schema export of spec.schemaExport also runs from here, as it reads the registry.
+func (r *StrimziSchemaRegistryReconciler) reconcileRegistryAPI(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) {
+	rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, instance)
+	if err == nil {
+		err = rc.Ping(ctx)
+	}
+	if err != nil {
+		logger.Error(err, "Schema Registry REST API is not responsive")
+		reason := "Unreachable"
+		if registryclient.IsClientError(err) {
+			reason = "Rejected"
+		}
+		setRegistryCondition(instance, responsiveCondition, metav1.ConditionFalse, reason, err.Error())
+		if !registryReadOnly(instance) {
+			setRegistryCondition(instance, compatibilityCondition, metav1.ConditionFalse, "RegistryUnreachable",
+				"the REST API is not responsive")
+		}
+		if instance.Spec.SchemaExport != nil && instance.Spec.SchemaExport.PersistentVolumeClaim == nil {
+			setRegistryCondition(instance, schemaExportCondition, metav1.ConditionFalse, "RegistryUnreachable",
+				"the REST API is not responsive")
+		}
+		return
+	}
+	setRegistryCondition(instance, responsiveCondition, metav1.ConditionTrue, "Responsive", "the REST API answers requests")
+	r.reconcileGlobalCompatibility(ctx, instance, rc, logger)
+	r.reconcileSchemaExport(ctx, instance, rc, logger)
+}
+
+// setRegistryCondition sets a condition of a StrimziSchemaRegistry for its current generation.
+func setRegistryCondition(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, conditionType string,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+}
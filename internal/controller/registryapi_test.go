@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestAPIReconciler returns a registry reconciler whose REST API clients target url.
+func newTestAPIReconciler(url string, objs ...client.Object) *StrimziSchemaRegistryReconciler {
+	r := newTestReconciler()
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(objs...).Build()
+	r.NewRegistryClient = fakeRegistryClientFactory(url)
+	return r
+}
+
+func TestReconcileRegistryAPI(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("responsive registry gets its settings applied", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		instance := newTestInstance()
+		newTestAPIReconciler(registry.URL).reconcileRegistryAPI(ctx, instance, logr.Discard())
+		if !meta.IsStatusConditionTrue(instance.Status.Conditions, responsiveCondition) {
+			t.Errorf("expected %s condition, got %+v", responsiveCondition, instance.Status.Conditions)
+		}
+		if level, _ := registry.Compatibility(""); level != "BACKWARD" || instance.Status.CompatibilityLevel != "BACKWARD" {
+			t.Errorf("expected the compatibility level to be applied, got %q", level)
+		}
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+		}))
+		defer server.Close()
+		instance := newTestInstance()
+		newTestAPIReconciler(server.URL).reconcileRegistryAPI(ctx, instance, logr.Discard())
+		responsive := meta.FindStatusCondition(instance.Status.Conditions, responsiveCondition)
+		if responsive == nil || responsive.Status != metav1.ConditionFalse || responsive.Reason != "Rejected" {
+			t.Errorf("expected %s=False with reason Rejected, got %+v", responsiveCondition, responsive)
+		}
+		if meta.IsStatusConditionTrue(instance.Status.Conditions, compatibilityCondition) {
+			t.Error("the compatibility level cannot be applied to an unresponsive registry")
+		}
+	})
+
+	t.Run("missing CA secret", func(t *testing.T) {
+		instance := newTestInstance()
+		instance.Spec.SecureHTTP = true
+		instance.Labels = map[string]string{strimziClusterLabel: "kafka"}
+		newTestAPIReconciler("https://unused").reconcileRegistryAPI(ctx, instance, logr.Discard())
+		responsive := meta.FindStatusCondition(instance.Status.Conditions, responsiveCondition)
+		if responsive == nil || responsive.Status != metav1.ConditionFalse || responsive.Reason != "Unreachable" {
+			t.Errorf("expected %s=False with reason Unreachable, got %+v", responsiveCondition, responsive)
+		}
+	})
+}
+
+func TestRegistryClientConfigClientCertificate(t *testing.T) {
+	registry := newTestInstance()
+	registry.Labels = map[string]string{strimziClusterLabel: "kafka"}
+	registry.Spec.SecureHTTP = true
+	registry.Spec.TLS = &strimziregistryoperatorv1alpha1.TLSSpec{ClientAuth: strimziregistryoperatorv1alpha1.ClientAuthRequired}
+	c := fake.NewClientBuilder().WithScheme(newTestReconciler().Scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kafka-cluster-ca-cert", Namespace: "default"},
+			Data:       map[string][]byte{"ca.crt": []byte("cluster-ca")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sr", Namespace: "default"},
+			Data:       map[string][]byte{"user.crt": []byte("user-cert"), "user.key": []byte("user-key")},
+		},
+	).Build()
+
+	cfg, err := registryClientConfig(context.Background(), c, registry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(cfg.CACert) != "cluster-ca" || string(cfg.ClientCert) != "user-cert" || string(cfg.ClientKey) != "user-key" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+)
+
+// defaultSchemasTopic is the topic primary registries store their schemas in.
+const defaultSchemasTopic = "registry-schemas"
+
+// errRegistryReadOnly rejects writes into a registry marked read-only by spec.secondary.readOnly.
+var errRegistryReadOnly = errors.New("registry is read-only")
+
+// leaderEligible reports whether the instances of the registry may become the leader.
+func leaderEligible(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return instance.Spec.Role != strimziregistryoperatorv1alpha1.RegistryRoleSecondary
+}
+
+// schemasTopic returns the topic the registry reads its schemas from.
+func schemasTopic(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if !leaderEligible(instance) && instance.Spec.Secondary != nil && instance.Spec.Secondary.SchemasTopic != "" {
+		return instance.Spec.Secondary.SchemasTopic
+	}
+	return defaultSchemasTopic
+}
+
+// registryReadOnly reports whether the operator must not write into the registry.
+func registryReadOnly(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return !leaderEligible(instance) && instance.Spec.Secondary != nil && instance.Spec.Secondary.ReadOnly
+}
+
+// registryWritable returns errRegistryReadOnly when the operator must not write into the registry.
+func registryWritable(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) error {
+	if registryReadOnly(instance) {
+		return fmt.Errorf("%w: StrimziSchemaRegistry %s/%s is a read-only secondary", errRegistryReadOnly,
+			instance.Namespace, instance.Name)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// newReadOnlySecondary returns a ready secondary registry reading a replicated schemas topic.
+func newReadOnlySecondary() *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	registry := newReadyRegistry()
+	registry.Spec.Role = strimziregistryoperatorv1alpha1.RegistryRoleSecondary
+	registry.Spec.Secondary = &strimziregistryoperatorv1alpha1.SecondarySpec{
+		SchemasTopic: "primary.registry-schemas",
+		ReadOnly:     true,
+	}
+	return registry
+}
+
+func envValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func TestRegistryRoleEnv(t *testing.T) {
+	secondary := newTestInstance()
+	secondary.Spec.Role = strimziregistryoperatorv1alpha1.RegistryRoleSecondary
+	tests := []struct {
+		name            string
+		instance        *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry
+		eligible, topic string
+	}{
+		{"primary", newTestInstance(), "true", "registry-schemas"},
+		{"secondary of the primary group", secondary, "false", "registry-schemas"},
+		{"read-only secondary", newReadOnlySecondary(), "false", "primary.registry-schemas"},
+	}
+	for _, test := range tests {
+		env := buildPodEnv(test.instance, "kafka:9093", "")
+		if got := envValue(env, "SCHEMA_REGISTRY_MASTER_ELIGIBILITY"); got != test.eligible {
+			t.Errorf("%s: expected leader eligibility %s, got %s", test.name, test.eligible, got)
+		}
+		if got := envValue(env, "SCHEMA_REGISTRY_KAFKASTORE_TOPIC"); got != test.topic {
+			t.Errorf("%s: expected schemas topic %s, got %s", test.name, test.topic, got)
+		}
+	}
+
+	// The role and the schemas topic reach the pods through the spec hash.
+	hashes := map[string]string{}
+	for _, test := range tests {
+		hash, _ := computeSpecHash(test.instance)
+		if other, ok := hashes[hash]; ok {
+			t.Errorf("%s and %s must have different spec hashes", other, test.name)
+		}
+		hashes[hash] = test.name
+	}
+	primary := newTestInstance()
+	primary.Spec.Role = strimziregistryoperatorv1alpha1.RegistryRolePrimary
+	explicit, _ := computeSpecHash(primary)
+	if hashes[explicit] != "primary" {
+		t.Error("an explicit primary role must keep the spec hash")
+	}
+}
+
+func TestReadOnlySecondary(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	seedRegistry(t, registry, "orders-value")
+	registry.SetCompatibility("", "NONE")
+
+	t.Run("the global compatibility level is left alone", func(t *testing.T) {
+		rc, err := registryclient.New(registryclient.Config{URL: registry.URL})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		instance := newReadOnlySecondary()
+		newTestReconciler().reconcileGlobalCompatibility(ctx, instance, rc, logr.Discard())
+		if level, _ := registry.Compatibility(""); level != "NONE" {
+			t.Errorf("expected the level to be kept, got %q", level)
+		}
+		if meta.FindStatusCondition(instance.Status.Conditions, compatibilityCondition) != nil {
+			t.Error("expected no compatibility condition")
+		}
+	})
+
+	t.Run("schemas are looked up instead of registered", func(t *testing.T) {
+		r := newTestSchemaReconciler(registry, newReadOnlySecondary(),
+			newTestSchema("orders", "orders-value"), newTestSchema("refunds", "refunds-value"))
+		schema := reconcileSchema(t, r, "orders")
+		condition := meta.FindStatusCondition(schema.Status.Conditions, "Ready")
+		if condition == nil || condition.Reason != "Replicated" || schema.Status.ID != registry.Versions("orders-value")[0].ID {
+			t.Errorf("expected the replicated schema, got %+v %+v", condition, schema.Status)
+		}
+		schema = reconcileSchema(t, r, "refunds")
+		condition = meta.FindStatusCondition(schema.Status.Conditions, "Ready")
+		if condition == nil || condition.Reason != "NotReplicated" {
+			t.Errorf("expected NotReplicated, got %+v", condition)
+		}
+		if len(registry.Versions("refunds-value")) != 0 {
+			t.Error("expected nothing to be registered")
+		}
+	})
+
+	t.Run("subject configurations are rejected", func(t *testing.T) {
+		r := newTestSubjectConfigReconciler(registry, newReadOnlySecondary(), newTestSubjectConfig("orders", "orders-value"))
+		config := reconcileSubjectConfig(t, r, "orders")
+		condition := meta.FindStatusCondition(config.Status.Conditions, "Ready")
+		if condition == nil || condition.Reason != "ReadOnlyRegistry" {
+			t.Errorf("expected ReadOnlyRegistry, got %+v", condition)
+		}
+		if _, ok := registry.Compatibility("orders-value"); ok {
+			t.Error("expected the subject configuration to be left alone")
+		}
+	})
+}
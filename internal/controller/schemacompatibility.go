@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/compatibility"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// compatibilityCheckTimeout bounds the registry calls of a compatibility check, which runs
+// during admission.
+const compatibilityCheckTimeout = 5 * time.Second
+
+// SchemaCompatibility is the result of checking a StrimziSchema against the registered versions
+// of its subject.
+type SchemaCompatibility struct {
+	// Compatible is false when the registry would reject the schema.
+	Compatible bool
+	// Messages explain why the schema is incompatible, naming the fields involved when known.
+	Messages []string
+	// Warning reports a partial check: the schema was checked offline, or not at all.
+	Warning string
+}
+
+// CheckSchemaCompatibility checks a StrimziSchema against the versions of its subject under the
+// compatibility level of the subject, through the /compatibility endpoint of its registry.
+// When the registry cannot be called, Avro schemas are checked offline against the versions
+// exported by spec.schemaExport or, on update, against the previous spec (old may be nil).
+// Schemas outside of the context of their namespace in a shared registry are incompatible.
+func CheckSchemaCompatibility(ctx context.Context, c client.Reader, factory RegistryClientFactory,
+	schema, old *strimziregistryoperatorv1alpha1.StrimziSchema) *SchemaCompatibility {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	registryKey := registryReference(schema.Spec.Registry, schema.Namespace)
+	if err := c.Get(ctx, registryKey, registry); err != nil {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked: failed to get StrimziSchemaRegistry %s: %v", registryKey, err)}
+	}
+	definition, err := schemaDefinition(ctx, c, schema)
+	if err != nil {
+		return &SchemaCompatibility{Compatible: true, Warning: "compatibility not checked: " + err.Error()}
+	}
+	subject, err := tenantSchema(ctx, c, registry, schema, &definition)
+	if tenancyReason(err) != "" {
+		return &SchemaCompatibility{Messages: []string{err.Error()}}
+	} else if err != nil {
+		return &SchemaCompatibility{Compatible: true, Warning: "compatibility not checked: " + err.Error()}
+	}
+
+	reason := fmt.Sprintf("StrimziSchemaRegistry %s is not ready", registryKey)
+	if meta.IsStatusConditionTrue(registry.Status.Conditions, "Ready") {
+		ctx, cancel := context.WithTimeout(ctx, compatibilityCheckTimeout)
+		defer cancel()
+		rc, err := newRegistryClient(ctx, c, factory, registry)
+		var compatible bool
+		var messages []string
+		if err == nil {
+			compatible, messages, err = rc.CheckCompatibility(ctx, subject, "", definition)
+		}
+		switch {
+		case err == nil:
+			if !compatible && len(messages) == 0 {
+				messages = []string{fmt.Sprintf("the schema is incompatible with the versions of subject %s", subject)}
+			}
+			return &SchemaCompatibility{Compatible: compatible, Messages: messages}
+		case registryclient.IsNotFound(err):
+			// The first version of a subject is always compatible.
+			return &SchemaCompatibility{Compatible: true}
+		case registryclient.IsInvalidSchema(err):
+			return &SchemaCompatibility{Messages: []string{err.Error()}}
+		}
+		reason = err.Error()
+	}
+	return checkSchemaCompatibilityOffline(ctx, c, registry, subject, schema, old, definition, reason)
+}
+
+// checkSchemaCompatibilityOffline checks an Avro schema registered under subject without calling
+// the registry. The compatibility level set by a StrimziSchemaSubjectConfig wins over the exported
+// one, itself winning over the global level. Schemas whose level is unknown are not checked.
+func checkSchemaCompatibilityOffline(ctx context.Context, c client.Reader,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, subject string, schema, old *strimziregistryoperatorv1alpha1.StrimziSchema,
+	definition registryclient.Schema, reason string) *SchemaCompatibility {
+	if definition.SchemaType != string(strimziregistryoperatorv1alpha1.SchemaTypeAvro) {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked: the registry cannot be called (%s) and offline checks support Avro only", reason)}
+	}
+
+	level := registry.Status.CompatibilityLevel
+	if level == "" {
+		level = registry.Spec.CompatibilityLevel
+	}
+	source := "the versions exported to ConfigMap " + SchemaExportName(registry.Name)
+	versions, exportedLevel, err := exportedVersions(ctx, c, registry, subject)
+	if err != nil {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked: the registry cannot be called (%s): %v", reason, err)}
+	}
+	if exportedLevel != "" {
+		level = exportedLevel
+	}
+	configLevel, err := subjectConfigLevel(ctx, c, registry, subject)
+	if err != nil {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked: the registry cannot be called (%s) and the compatibility level of subject %s is unknown: %v",
+				reason, subject, err)}
+	}
+	if configLevel != "" {
+		level = configLevel
+	}
+	if level == "" {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked: the registry cannot be called (%s) and the compatibility level of subject %s is unknown",
+				reason, subject)}
+	}
+	if versions == nil && old != nil && old.Spec.Subject == schema.Spec.Subject && old.Status.Version > 0 {
+		if previous, err := schemaDefinition(ctx, c, old); err == nil && previous.SchemaType == definition.SchemaType {
+			versions = []compatibility.Version{{Version: old.Status.Version, Schema: previous.Schema}}
+			source = "the previous spec"
+		}
+	}
+	if versions == nil {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked: the registry cannot be called (%s) and no previous version is known", reason)}
+	}
+
+	messages, err := compatibility.Avro(level, definition.Schema, versions)
+	if err != nil {
+		return &SchemaCompatibility{Compatible: true,
+			Warning: fmt.Sprintf("compatibility not checked offline: %v", err)}
+	}
+	return &SchemaCompatibility{Compatible: len(messages) == 0, Messages: messages,
+		Warning: fmt.Sprintf("the registry cannot be called (%s): checked offline against %s", reason, source)}
+}
+
+// subjectConfigLevel returns the compatibility level the StrimziSchemaSubjectConfigs of registry
+// set on subject, falling back to the one set on its context, or "" when none sets one.
+func subjectConfigLevel(ctx context.Context, c client.Reader,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, subject string) (string, error) {
+	configs := &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfigList{}
+	if err := c.List(ctx, configs); err != nil {
+		return "", fmt.Errorf("failed to list StrimziSchemaSubjectConfigs: %w", err)
+	}
+	subjectContext := ""
+	if rest, ok := strings.CutPrefix(subject, ":."); ok {
+		if name, _, ok := strings.Cut(rest, ":"); ok {
+			subjectContext = ":." + name + ":"
+		}
+	}
+	registryKey := client.ObjectKeyFromObject(registry)
+	var subjectLevel, contextLevel string
+	for i := range configs.Items {
+		config := &configs.Items[i]
+		if config.Spec.Compatibility == "" || config.DeletionTimestamp != nil ||
+			registryReference(config.Spec.Registry, config.Namespace) != registryKey {
+			continue
+		}
+		tenant, err := tenantContext(ctx, c, registry, config.Namespace)
+		if tenancyReason(err) != "" {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		target, err := subjectConfigTarget(config, tenant)
+		if err != nil {
+			continue
+		}
+		switch {
+		case target == subject:
+			subjectLevel = string(config.Spec.Compatibility)
+		case subjectContext != "" && target == subjectContext:
+			contextLevel = string(config.Spec.Compatibility)
+		}
+	}
+	if subjectLevel != "" {
+		return subjectLevel, nil
+	}
+	return contextLevel, nil
+}
+
+// exportedVersions returns the Avro versions of subject and its compatibility level from the
+// schema tree exported by spec.schemaExport, or nil when the subject was not exported.
+func exportedVersions(ctx context.Context, c client.Reader,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, subject string) ([]compatibility.Version, string, error) {
+	configMap := &v1.ConfigMap{}
+	key := types.NamespacedName{Name: SchemaExportName(registry.Name), Namespace: registry.Namespace}
+	if err := c.Get(ctx, key, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	files, err := backup.TreeFromConfigMapData(configMap.Data)
+	if err != nil {
+		return nil, "", err
+	}
+	manifest := &backup.Manifest{}
+	if err := json.Unmarshal(files[backup.ManifestFile], manifest); err != nil {
+		return nil, "", fmt.Errorf("invalid %s in ConfigMap %s: %w", backup.ManifestFile, key, err)
+	}
+	for _, exported := range manifest.Subjects {
+		if exported.Subject != subject {
+			continue
+		}
+		var versions []compatibility.Version
+		for _, version := range exported.Versions {
+			if version.SchemaType == "" || version.SchemaType == string(strimziregistryoperatorv1alpha1.SchemaTypeAvro) {
+				versions = append(versions, compatibility.Version{Version: version.Version, Schema: string(files[version.Path])})
+			}
+		}
+		level := exported.Compatibility
+		if level == "" {
+			level = manifest.Compatibility
+		}
+		return versions, level, nil
+	}
+	return nil, "", nil
+}
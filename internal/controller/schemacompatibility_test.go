@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// testAvroSchemaV2 adds a field without a default to testAvroSchema.
+const testAvroSchemaV2 = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"total","type":"double"}]}`
+
+// newTestSchemaExport returns the export ConfigMap of test-sr holding testAvroSchema as version 1
+// of orders-value.
+func newTestSchemaExport(t *testing.T) *corev1.ConfigMap {
+	t.Helper()
+	files, err := backup.Tree(&backup.Snapshot{Subjects: []backup.Subject{{
+		Name:     "orders-value",
+		Versions: []registryclient.SubjectVersion{{Subject: "orders-value", Version: 1, ID: 1, Schema: testAvroSchema}},
+	}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sr-schemas", Namespace: "default"},
+		Data:       backup.TreeConfigMapData(files),
+	}
+}
+
+func TestCheckSchemaCompatibility(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	registry.Incompatible = func(_, schema string) bool { return schema == testAvroSchemaV2 }
+	factory := fakeRegistryClientFactory(registry.URL)
+	seedRegistry(t, registry, "orders-value")
+	scheme := newTestReconciler().Scheme
+	reader := func(objs ...client.Object) client.Reader {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+	changed := newTestSchema("orders", "orders-value")
+	changed.Spec.Schema = testAvroSchemaV2
+
+	t.Run("registry checks the schema", func(t *testing.T) {
+		result := CheckSchemaCompatibility(ctx, reader(newReadyRegistry()), factory, changed, nil)
+		if result.Compatible || len(result.Messages) != 1 || result.Warning != "" {
+			t.Errorf("expected the registry to reject the schema, got %+v", result)
+		}
+		result = CheckSchemaCompatibility(ctx, reader(newReadyRegistry()), factory, newTestSchema("new", "new-value"), nil)
+		if !result.Compatible || result.Warning != "" {
+			t.Errorf("expected the first version of a subject to be compatible, got %+v", result)
+		}
+	})
+
+	t.Run("unreachable registry falls back to the exported versions", func(t *testing.T) {
+		result := CheckSchemaCompatibility(ctx, reader(newTestInstance(), newTestSchemaExport(t)), factory, changed, nil)
+		if result.Compatible || len(result.Messages) != 1 || !strings.Contains(result.Messages[0], `field "total"`) {
+			t.Errorf("expected an offline rejection naming the field, got %+v", result)
+		}
+		if !strings.Contains(result.Warning, "checked offline against the versions exported to ConfigMap test-sr-schemas") {
+			t.Errorf("expected an offline warning, got %q", result.Warning)
+		}
+	})
+
+	t.Run("unreachable registry falls back to the previous spec", func(t *testing.T) {
+		old := newTestSchema("orders", "orders-value")
+		old.Status.Version = 1
+		result := CheckSchemaCompatibility(ctx, reader(newTestInstance()), factory, changed, old)
+		if result.Compatible || !strings.Contains(result.Warning, "the previous spec") {
+			t.Errorf("expected an offline rejection against the previous spec, got %+v", result)
+		}
+		compatible := newTestSchema("orders", "orders-value")
+		compatible.Spec.Schema = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},` +
+			`{"name":"total","type":"double","default":0}]}`
+		if result := CheckSchemaCompatibility(ctx, reader(newTestInstance()), factory, compatible, old); !result.Compatible {
+			t.Errorf("expected a field with a default to be compatible, got %+v", result)
+		}
+	})
+
+	t.Run("offline check uses the level of the subject configuration", func(t *testing.T) {
+		old := newTestSchema("orders", "orders-value")
+		old.Status.Version = 1
+		subjectConfig := newTestSubjectConfig("orders", "orders-value")
+		subjectConfig.Spec.Compatibility = "NONE"
+		result := CheckSchemaCompatibility(ctx, reader(newTestInstance(), subjectConfig), factory, changed, old)
+		if !result.Compatible || !strings.Contains(result.Warning, "the previous spec") {
+			t.Errorf("expected the schema to be admitted under NONE, got %+v", result)
+		}
+
+		inContext := changed.DeepCopy()
+		inContext.Spec.Subject = ":.team-a:orders-value"
+		oldInContext := old.DeepCopy()
+		oldInContext.Spec.Subject = inContext.Spec.Subject
+		contextConfig := newTestSubjectConfig("team-a", "")
+		contextConfig.Spec.Context, contextConfig.Spec.Compatibility = "team-a", "NONE"
+		result = CheckSchemaCompatibility(ctx, reader(newTestInstance(), contextConfig), factory, inContext, oldInContext)
+		if !result.Compatible {
+			t.Errorf("expected the level of the context to apply, got %+v", result)
+		}
+		subjectConfig.Spec.Subject, subjectConfig.Spec.Compatibility = inContext.Spec.Subject, "BACKWARD"
+		result = CheckSchemaCompatibility(ctx, reader(newTestInstance(), contextConfig, subjectConfig), factory, inContext, oldInContext)
+		if result.Compatible {
+			t.Errorf("expected the level of the subject to win over the context, got %+v", result)
+		}
+	})
+
+	t.Run("unknown level is admitted with a warning", func(t *testing.T) {
+		old := newTestSchema("orders", "orders-value")
+		old.Status.Version = 1
+		instance := newTestInstance()
+		instance.Spec.CompatibilityLevel = ""
+		result := CheckSchemaCompatibility(ctx, reader(instance), factory, changed, old)
+		if !result.Compatible || !strings.Contains(result.Warning, "compatibility level of subject orders-value is unknown") {
+			t.Errorf("expected an unchecked schema with a warning, got %+v", result)
+		}
+	})
+
+	t.Run("unchecked schemas are admitted with a warning", func(t *testing.T) {
+		jsonSchema := newTestSchema("payments", "payments-value")
+		jsonSchema.Spec.SchemaType = strimziregistryoperatorv1alpha1.SchemaTypeJSON
+		for name, result := range map[string]*SchemaCompatibility{
+			"no previous version": CheckSchemaCompatibility(ctx, reader(newTestInstance()), factory, changed, nil),
+			"JSON Schema":         CheckSchemaCompatibility(ctx, reader(newTestInstance()), factory, jsonSchema, nil),
+			"missing registry":    CheckSchemaCompatibility(ctx, reader(), factory, changed, nil),
+		} {
+			if !result.Compatible || !strings.HasPrefix(result.Warning, "compatibility not checked") {
+				t.Errorf("%s: expected an unchecked schema with a warning, got %+v", name, result)
+			}
+		}
+	})
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// schemaExportCondition reports whether the schema tree of spec.schemaExport is exported.
+	schemaExportCondition = "SchemasExported"
+	// schemaExportSuffix names the ConfigMap holding the schema tree.
+	schemaExportSuffix = "-schemas"
+	// schemaExportJobSuffix names the CronJob exporting the schema tree to a volume.
+	schemaExportJobSuffix = "-schema-export"
+	// schemaExportAccessSuffix names the Secret holding the registry access of the export CronJob.
+	schemaExportAccessSuffix = "-schema-export-access"
+	// schemaExportVolumeDir is where the export CronJob mounts its volume.
+	schemaExportVolumeDir = "/export"
+	// maxSchemaExportSize keeps the export ConfigMap below the 1MiB object size limit.
+	maxSchemaExportSize = 1000 * 1024
+)
+
+// SchemaExportName returns the name of the ConfigMap holding the schema tree of a registry.
+func SchemaExportName(registry string) string {
+	return registry + schemaExportSuffix
+}
+
+// reconcileSchemaExport exports the schema tree of a responsive registry as requested in
+// spec.schemaExport: into the "<name>-schemas" ConfigMap when an export is due, or through the
+// "<name>-schema-export" CronJob for volumes. The resources of the other target, or of both when
+// the export is disabled, are removed. Failures are reported in the SchemasExported condition
+// rather than failing the reconcile.
+func (r *StrimziSchemaRegistryReconciler) reconcileSchemaExport(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, rc *registryclient.Client, logger logr.Logger) {
+	export := instance.Spec.SchemaExport
+	if export == nil || export.PersistentVolumeClaim != nil {
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: SchemaExportName(instance.Name), Namespace: instance.Namespace}}
+		if err := r.deleteOwnedNamed(ctx, instance, logger, configMap); err != nil {
+			logger.Error(err, "Failed to delete schema export ConfigMap")
+		}
+	}
+	if export == nil || export.PersistentVolumeClaim == nil {
+		for _, obj := range []client.Object{
+			&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + schemaExportJobSuffix, Namespace: instance.Namespace}},
+			&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + schemaExportAccessSuffix, Namespace: instance.Namespace}},
+		} {
+			if err := r.deleteOwnedNamed(ctx, instance, logger, obj); err != nil {
+				logger.Error(err, "Failed to delete schema export resource", "Name", obj.GetName())
+			}
+		}
+	}
+	if export == nil {
+		instance.Status.SchemaExport = nil
+		meta.RemoveStatusCondition(&instance.Status.Conditions, schemaExportCondition)
+		return
+	}
+	if instance.Status.SchemaExport == nil {
+		instance.Status.SchemaExport = &strimziregistryoperatorv1alpha1.SchemaExportStatus{}
+	}
+
+	scheduleSpec := export.Schedule
+	if scheduleSpec == "" {
+		scheduleSpec = "*/10 * * * *"
+	}
+	schedule, err := cron.ParseStandard(scheduleSpec)
+	if err != nil {
+		setRegistryCondition(instance, schemaExportCondition, metav1.ConditionFalse, "InvalidSchedule", err.Error())
+		return
+	}
+	if export.PersistentVolumeClaim != nil {
+		r.reconcileSchemaExportCronJob(ctx, instance, scheduleSpec, logger)
+		return
+	}
+
+	status := instance.Status.SchemaExport
+	exported := meta.FindStatusCondition(instance.Status.Conditions, schemaExportCondition)
+	if status.LastExportTime != nil && schedule.Next(status.LastExportTime.Time).After(time.Now()) &&
+		exported != nil && exported.Status == metav1.ConditionTrue && exported.ObservedGeneration == instance.Generation {
+		return
+	}
+	snapshot, err := backup.Export(ctx, rc)
+	if err != nil {
+		logger.Error(err, "Failed to read schemas for export")
+		reason := "RegistryUnreachable"
+		if registryclient.IsClientError(err) {
+			reason = "Rejected"
+		}
+		setRegistryCondition(instance, schemaExportCondition, metav1.ConditionFalse, reason, err.Error())
+		return
+	}
+	if err := r.applySchemaExportConfigMap(ctx, instance, snapshot); err != nil {
+		logger.Error(err, "Failed to export schemas")
+		setRegistryCondition(instance, schemaExportCondition, metav1.ConditionFalse, "Failed", err.Error())
+		return
+	}
+	now := metav1.Now()
+	status.LastExportTime = &now
+	status.Subjects, status.Schemas = int32(len(snapshot.Subjects)), int32(snapshot.SchemaCount())
+	setRegistryCondition(instance, schemaExportCondition, metav1.ConditionTrue, "Exported",
+		fmt.Sprintf("%d subjects and %d schemas exported to ConfigMap %s", status.Subjects, status.Schemas,
+			SchemaExportName(instance.Name)))
+}
+
+// applySchemaExportConfigMap writes the schema tree of a snapshot to the "<name>-schemas" ConfigMap.
+func (r *StrimziSchemaRegistryReconciler) applySchemaExportConfigMap(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, snapshot *backup.Snapshot) error {
+	files, err := backup.Tree(snapshot)
+	if err != nil {
+		return err
+	}
+	data := backup.TreeConfigMapData(files)
+	size := 0
+	for key, value := range data {
+		size += len(key) + len(value)
+	}
+	if size > maxSchemaExportSize {
+		return fmt.Errorf("the schema tree (%d bytes) does not fit in a ConfigMap: export it to a volume", size)
+	}
+
+	configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: SchemaExportName(instance.Name), Namespace: instance.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		configMap.Labels = map[string]string{"app": instance.Name, keyPrefix + "/schema-export": "true"}
+		configMap.Data = data
+		return ctrl.SetControllerReference(instance, configMap, r.Scheme)
+	})
+	return err
+}
+
+// reconcileSchemaExportCronJob applies the CronJob exporting the schema tree to a volume and
+// reports its last successful run.
+func (r *StrimziSchemaRegistryReconciler) reconcileSchemaExportCronJob(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, schedule string, logger logr.Logger) {
+	fail := func(reason string, err error) {
+		logger.Error(err, "Failed to apply schema export CronJob")
+		setRegistryCondition(instance, schemaExportCondition, metav1.ConditionFalse, reason, err.Error())
+	}
+	if r.JobImage == "" {
+		fail("JobImageNotSet", errJobImageNotSet)
+		return
+	}
+	cfg, err := registryClientConfig(ctx, r.Client, instance)
+	if err != nil {
+		fail("RegistryUnreachable", err)
+		return
+	}
+	accessName := instance.Name + schemaExportAccessSuffix
+	if err := applyRegistryAccessSecret(ctx, r.Client, r.Scheme, instance, accessName, cfg); err != nil {
+		fail("Failed", err)
+		return
+	}
+
+	storage := instance.Spec.SchemaExport.PersistentVolumeClaim
+	labels := map[string]string{"app": instance.Name, keyPrefix + "/schema-export": "true"}
+	args := []string{"export", "--registry-dir=" + backupRegistryDir, "--dir=" + path.Join(schemaExportVolumeDir, storage.Path)}
+	cronJob := &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + schemaExportJobSuffix, Namespace: instance.Namespace}}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cronJob, func() error {
+		cronJob.Labels = labels
+		cronJob.Spec.Schedule = schedule
+		cronJob.Spec.TimeZone = ptr.To("Etc/UTC")
+		cronJob.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+		cronJob.Spec.SuccessfulJobsHistoryLimit = ptr.To[int32](1)
+		cronJob.Spec.FailedJobsHistoryLimit = ptr.To[int32](1)
+		cronJob.Spec.JobTemplate = batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: labels},
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To[int32](1),
+				Template: volumeJobPodTemplate(labels, r.JobImage, accessName, storage.ClaimName,
+					schemaExportVolumeDir, args),
+			},
+		}
+		return ctrl.SetControllerReference(instance, cronJob, r.Scheme)
+	}); err != nil {
+		fail("Failed", err)
+		return
+	}
+
+	instance.Status.SchemaExport.LastExportTime = cronJob.Status.LastSuccessfulTime
+	instance.Status.SchemaExport.Subjects, instance.Status.SchemaExport.Schemas = 0, 0
+	setRegistryCondition(instance, schemaExportCondition, metav1.ConditionTrue, "Scheduled",
+		fmt.Sprintf("CronJob %s exports the schemas to volume %s", cronJob.Name, storage.ClaimName))
+}
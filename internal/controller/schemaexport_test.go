@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestExportReconciler returns a registry reconciler able to manage schema export CronJobs.
+func newTestExportReconciler(registry *testutil.FakeRegistry) *StrimziSchemaRegistryReconciler {
+	r := newTestAPIReconciler(registry.URL)
+	_ = batchv1.AddToScheme(r.Scheme)
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).Build()
+	return r
+}
+
+func newTestExportInstance(export *strimziregistryoperatorv1alpha1.SchemaExportSpec) *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	instance := newTestInstance()
+	instance.UID = "test-uid"
+	instance.Generation = 1
+	instance.Spec.SchemaExport = export
+	return instance
+}
+
+func expectExportCondition(t *testing.T, instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
+	status metav1.ConditionStatus, reason string) {
+	t.Helper()
+	condition := meta.FindStatusCondition(instance.Status.Conditions, schemaExportCondition)
+	if condition == nil || condition.Status != status || condition.Reason != reason {
+		t.Errorf("expected %s %s with reason %s, got %+v", schemaExportCondition, status, reason, condition)
+	}
+}
+
+func TestReconcileSchemaExport(t *testing.T) {
+	ctx := context.Background()
+	configMapKey := types.NamespacedName{Name: "test-sr-schemas", Namespace: "default"}
+	cronJobKey := types.NamespacedName{Name: "test-sr-schema-export", Namespace: "default"}
+	accessKey := types.NamespacedName{Name: "test-sr-schema-export-access", Namespace: "default"}
+
+	t.Run("ConfigMap is exported on schedule", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		seedRegistry(t, registry, "orders-value")
+		r := newTestExportReconciler(registry)
+		instance := newTestExportInstance(&strimziregistryoperatorv1alpha1.SchemaExportSpec{})
+
+		r.reconcileRegistryAPI(ctx, instance, logr.Discard())
+		expectExportCondition(t, instance, metav1.ConditionTrue, "Exported")
+		if status := instance.Status.SchemaExport; status == nil || status.Subjects != 1 || status.Schemas != 1 ||
+			status.LastExportTime == nil {
+			t.Fatalf("unexpected export status %+v", status)
+		}
+		configMap := &corev1.ConfigMap{}
+		if err := r.Get(ctx, configMapKey, configMap); err != nil {
+			t.Fatalf("expected the schemas ConfigMap: %v", err)
+		}
+		if !metav1.IsControlledBy(configMap, instance) {
+			t.Error("the schemas ConfigMap must be owned by the registry")
+		}
+		files, err := backup.TreeFromConfigMapData(configMap.Data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := files["orders-value/1.avsc"]; !ok {
+			t.Errorf("expected orders-value/1.avsc in the tree, got %v", configMap.Data)
+		}
+		if _, ok := files[backup.ManifestFile]; !ok {
+			t.Errorf("expected %s in the tree", backup.ManifestFile)
+		}
+
+		// The next export waits for the schedule.
+		seedRegistry(t, registry, "payments-value")
+		r.reconcileRegistryAPI(ctx, instance, logr.Discard())
+		if instance.Status.SchemaExport.Subjects != 1 {
+			t.Errorf("expected no export before the schedule, got %+v", instance.Status.SchemaExport)
+		}
+		instance.Status.SchemaExport.LastExportTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		r.reconcileRegistryAPI(ctx, instance, logr.Discard())
+		if instance.Status.SchemaExport.Subjects != 2 {
+			t.Errorf("expected the due export to include the new subject, got %+v", instance.Status.SchemaExport)
+		}
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		instance := newTestExportInstance(&strimziregistryoperatorv1alpha1.SchemaExportSpec{Schedule: "every day"})
+		newTestExportReconciler(registry).reconcileRegistryAPI(ctx, instance, logr.Discard())
+		expectExportCondition(t, instance, metav1.ConditionFalse, "InvalidSchedule")
+	})
+
+	t.Run("volume export requires the job image", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		instance := newTestExportInstance(&strimziregistryoperatorv1alpha1.SchemaExportSpec{
+			PersistentVolumeClaim: &strimziregistryoperatorv1alpha1.PersistentVolumeClaimBackupStorage{ClaimName: "schemas"},
+		})
+		newTestExportReconciler(registry).reconcileRegistryAPI(ctx, instance, logr.Discard())
+		expectExportCondition(t, instance, metav1.ConditionFalse, "JobImageNotSet")
+	})
+
+	t.Run("volume export runs a CronJob and disabling cleans up", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		r := newTestExportReconciler(registry)
+		r.JobImage = "operator:test"
+		instance := newTestExportInstance(&strimziregistryoperatorv1alpha1.SchemaExportSpec{})
+		r.reconcileRegistryAPI(ctx, instance, logr.Discard())
+		if err := r.Get(ctx, configMapKey, &corev1.ConfigMap{}); err != nil {
+			t.Fatalf("expected the schemas ConfigMap: %v", err)
+		}
+
+		instance.Generation = 2
+		instance.Spec.SchemaExport = &strimziregistryoperatorv1alpha1.SchemaExportSpec{
+			Schedule: "0 * * * *",
+			PersistentVolumeClaim: &strimziregistryoperatorv1alpha1.PersistentVolumeClaimBackupStorage{
+				ClaimName: "schemas", Path: "registry",
+			},
+		}
+		r.reconcileRegistryAPI(ctx, instance, logr.Discard())
+		expectExportCondition(t, instance, metav1.ConditionTrue, "Scheduled")
+		if err := r.Get(ctx, configMapKey, &corev1.ConfigMap{}); !errors.IsNotFound(err) {
+			t.Errorf("expected the schemas ConfigMap to be removed, got %v", err)
+		}
+		cronJob := &batchv1.CronJob{}
+		if err := r.Get(ctx, cronJobKey, cronJob); err != nil {
+			t.Fatalf("expected the export CronJob: %v", err)
+		}
+		if cronJob.Spec.Schedule != "0 * * * *" || cronJob.Spec.ConcurrencyPolicy != batchv1.ForbidConcurrent {
+			t.Errorf("unexpected CronJob spec %+v", cronJob.Spec)
+		}
+		pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		container := pod.Containers[0]
+		if container.Image != "operator:test" || len(container.Args) != 3 || container.Args[0] != "export" ||
+			container.Args[2] != "--dir=/export/registry" {
+			t.Errorf("unexpected export container %+v", container)
+		}
+		if claim := pod.Volumes[1].PersistentVolumeClaim; claim == nil || claim.ClaimName != "schemas" {
+			t.Errorf("expected the schemas claim to be mounted, got %+v", pod.Volumes)
+		}
+		access := &corev1.Secret{}
+		if err := r.Get(ctx, accessKey, access); err != nil {
+			t.Fatalf("expected the registry access Secret: %v", err)
+		}
+		if string(access.Data["url"]) != "http://test-sr.default.svc:80" {
+			t.Errorf("unexpected registry url %q", access.Data["url"])
+		}
+
+		instance.Spec.SchemaExport = nil
+		r.reconcileRegistryAPI(ctx, instance, logr.Discard())
+		for _, obj := range []client.Object{&batchv1.CronJob{}, &corev1.Secret{}} {
+			key := cronJobKey
+			if _, ok := obj.(*corev1.Secret); ok {
+				key = accessKey
+			}
+			if err := r.Get(ctx, key, obj); !errors.IsNotFound(err) {
+				t.Errorf("expected %s to be removed, got %v", key.Name, err)
+			}
+		}
+		if instance.Status.SchemaExport != nil || meta.FindStatusCondition(instance.Status.Conditions, schemaExportCondition) != nil {
+			t.Error("expected the export status and condition to be cleared")
+		}
+	})
+}
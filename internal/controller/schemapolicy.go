@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/policy"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// schemaPolicyCondition reports whether a StrimziSchema passes the schema policy of its registry.
+const schemaPolicyCondition = "PolicyCompliant"
+
+// checkSchemaPolicy evaluates the spec.schemaPolicy of the registry on the definition registered
+// under subject and records the violations in the status. It returns false when the schema must not be
+// registered; errors are transient failures to read the latest version of the subject.
+func (r *StrimziSchemaReconciler) checkSchemaPolicy(ctx context.Context, schema *strimziregistryoperatorv1alpha1.StrimziSchema,
+	subject string, registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, definition registryclient.Schema,
+	rc *registryclient.Client) (bool, error) {
+	spec := registry.Spec.SchemaPolicy
+	if spec == nil {
+		schema.Status.PolicyViolations = nil
+		meta.RemoveStatusCondition(&schema.Status.Conditions, schemaPolicyCondition)
+		return true, nil
+	}
+	rules, err := r.schemaPolicy(ctx, registry)
+	if err != nil {
+		setSchemaCondition(schema, schemaPolicyCondition, metav1.ConditionFalse, "InvalidPolicy", err.Error())
+		setSchemaReady(schema, metav1.ConditionFalse, "InvalidPolicy", err.Error())
+		return false, nil
+	}
+	parsed, err := policy.Parse(definition.SchemaType, definition.Schema)
+	if err != nil {
+		setSchemaCondition(schema, schemaPolicyCondition, metav1.ConditionFalse, "InvalidSchema", err.Error())
+		setSchemaReady(schema, metav1.ConditionFalse, "InvalidSchema", err.Error())
+		return false, nil
+	}
+	in := &policy.Input{Subject: subject, Schema: parsed}
+	if spec.ForbidFieldTypeChanges || len(spec.Rules) > 0 || spec.RulesFrom != nil {
+		latest, err := rc.GetVersion(ctx, subject, "latest", false)
+		switch {
+		case registryclient.IsNotFound(err):
+		case err != nil:
+			setSchemaReady(schema, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+			return false, err
+		default:
+			// A previous version the parser does not understand is not compared.
+			in.Previous, _ = policy.Parse(latest.SchemaType, latest.Schema)
+		}
+	}
+	violations, err := rules.Evaluate(in)
+	if err != nil {
+		setSchemaCondition(schema, schemaPolicyCondition, metav1.ConditionFalse, "InvalidPolicy", err.Error())
+		setSchemaReady(schema, metav1.ConditionFalse, "InvalidPolicy", err.Error())
+		return false, nil
+	}
+
+	schema.Status.PolicyViolations = nil
+	for _, violation := range violations {
+		schema.Status.PolicyViolations = append(schema.Status.PolicyViolations,
+			strimziregistryoperatorv1alpha1.PolicyViolation{Rule: violation.Rule, Message: violation.Message})
+	}
+	if len(violations) > 0 {
+		messages := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			messages = append(messages, violation.Rule+": "+violation.Message)
+		}
+		setSchemaCondition(schema, schemaPolicyCondition, metav1.ConditionFalse, "Violations", strings.Join(messages, "; "))
+		setSchemaReady(schema, metav1.ConditionFalse, "PolicyViolation",
+			fmt.Sprintf("the schema fails %d schema policy checks of StrimziSchemaRegistry %s", len(violations), registry.Name))
+		return false, nil
+	}
+	setSchemaCondition(schema, schemaPolicyCondition, metav1.ConditionTrue, "Compliant", "the schema passes the schema policy")
+	return true, nil
+}
+
+// schemaPolicy builds the policy of spec.schemaPolicy, reading the rules of its ConfigMap.
+func (r *StrimziSchemaReconciler) schemaPolicy(ctx context.Context,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (policy.Policy, error) {
+	spec := registry.Spec.SchemaPolicy
+	var rules policy.Policy
+	if spec.RequireDoc {
+		rules = append(rules, policy.RequireDoc())
+	}
+	if spec.NamespacePattern != "" {
+		rule, err := policy.NamespacePattern(spec.NamespacePattern)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	if spec.MaxSchemaBytes != nil {
+		rules = append(rules, policy.MaxSize(int(*spec.MaxSchemaBytes)))
+	}
+	if spec.ForbidFieldTypeChanges {
+		rules = append(rules, policy.NoFieldTypeChanges())
+	}
+	for _, rule := range spec.Rules {
+		celRule, err := policy.CELRule(rule.Name, rule.Expression, rule.Message)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, celRule)
+	}
+	if spec.RulesFrom != nil {
+		configMap := &v1.ConfigMap{}
+		key := types.NamespacedName{Name: spec.RulesFrom.Name, Namespace: registry.Namespace}
+		if err := r.Get(ctx, key, configMap); err != nil {
+			return nil, fmt.Errorf("failed to get schema policy ConfigMap %s: %w", key, err)
+		}
+		names := make([]string, 0, len(configMap.Data))
+		for name := range configMap.Data {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			celRule, err := policy.CELRule(name, configMap.Data[name], "")
+			if err != nil {
+				return nil, fmt.Errorf("ConfigMap %s: %w", key, err)
+			}
+			rules = append(rules, celRule)
+		}
+	}
+	return rules, nil
+}
+
+// requestsForPolicyConfigMap returns the StrimziSchemas of the registries reading their schema
+// policy rules from the ConfigMap.
+func (r *StrimziSchemaReconciler) requestsForPolicyConfigMap(ctx context.Context, configMap client.Object) []reconcile.Request {
+	registries := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryList{}
+	if err := r.List(ctx, registries, client.InNamespace(configMap.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, registry := range registries.Items {
+		if spec := registry.Spec.SchemaPolicy; spec != nil && spec.RulesFrom != nil &&
+			spec.RulesFrom.Name == configMap.GetName() {
+			requests = append(requests, r.requestsForIndex(ctx, registryRefIndex, client.ObjectKeyFromObject(&registry).String())...)
+		}
+	}
+	return requests
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStrimziSchemaPolicy(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	sr := newReadyRegistry()
+	sr.Spec.SchemaPolicy = &strimziregistryoperatorv1alpha1.SchemaPolicySpec{
+		RequireDoc:             true,
+		NamespacePattern:       `com\.example(\..+)?`,
+		MaxSchemaBytes:         ptr.To[int32](1024),
+		ForbidFieldTypeChanges: true,
+		Rules: []strimziregistryoperatorv1alpha1.SchemaPolicyRule{{
+			Name: "value-subjects", Expression: "subject.endsWith('-value')", Message: "subjects must end with -value",
+		}},
+		RulesFrom: &corev1.LocalObjectReference{Name: "schema-rules"},
+	}
+	rules := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "schema-rules", Namespace: "default"},
+		Data:       map[string]string{"lower-case-fields": "schema.fields.all(f, f.path.lowerAscii() == f.path)"},
+	}
+	compliant := newTestSchema("orders", "orders-value")
+	compliant.Spec.Schema = `{"type":"record","name":"Order","namespace":"com.example","doc":"An order.",
+		"fields":[{"name":"id","type":"string","doc":"Order ID."}]}`
+	undocumented := newTestSchema("payments", "payments-value")
+	r := newTestSchemaReconciler(registry, sr, rules, compliant, undocumented)
+
+	t.Run("compliant schema is registered", func(t *testing.T) {
+		schema := reconcileSchema(t, r, "orders")
+		if !meta.IsStatusConditionTrue(schema.Status.Conditions, schemaPolicyCondition) ||
+			!meta.IsStatusConditionTrue(schema.Status.Conditions, schemaReadyCondition) {
+			t.Errorf("expected PolicyCompliant and Ready conditions, got %+v", schema.Status.Conditions)
+		}
+		if len(registry.Versions("orders-value")) != 1 {
+			t.Error("expected the schema to be registered")
+		}
+	})
+
+	t.Run("violations are reported and the schema is not registered", func(t *testing.T) {
+		schema := reconcileSchema(t, r, "payments")
+		expected := []strimziregistryoperatorv1alpha1.PolicyViolation{
+			{Rule: "requireDoc", Message: "the schema has no doc"},
+			{Rule: "requireDoc", Message: "field id has no doc"},
+			{Rule: "namespacePattern", Message: `namespace "" does not match "com\\.example(\\..+)?"`},
+		}
+		if len(schema.Status.PolicyViolations) != len(expected) {
+			t.Fatalf("expected violations %+v, got %+v", expected, schema.Status.PolicyViolations)
+		}
+		for i := range expected {
+			if schema.Status.PolicyViolations[i] != expected[i] {
+				t.Errorf("expected violation %+v, got %+v", expected[i], schema.Status.PolicyViolations[i])
+			}
+		}
+		ready := meta.FindStatusCondition(schema.Status.Conditions, schemaReadyCondition)
+		if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != "PolicyViolation" {
+			t.Errorf("expected Ready=False with reason PolicyViolation, got %+v", ready)
+		}
+		if len(registry.Versions("payments-value")) != 0 {
+			t.Error("a schema failing the policy must not be registered")
+		}
+	})
+
+	t.Run("field type changes and ConfigMap rules", func(t *testing.T) {
+		schema := &strimziregistryoperatorv1alpha1.StrimziSchema{}
+		_ = r.Get(ctx, types.NamespacedName{Name: "orders", Namespace: "default"}, schema)
+		schema.Spec.Schema = `{"type":"record","name":"Order","namespace":"com.example","doc":"An order.",
+			"fields":[{"name":"id","type":"long","doc":"Order ID."},{"name":"Total","type":"double","doc":"Total."}]}`
+		schema.Generation = 2
+		if err := r.Update(ctx, schema); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		schema = reconcileSchema(t, r, "orders")
+		expected := []strimziregistryoperatorv1alpha1.PolicyViolation{
+			{Rule: "forbidFieldTypeChanges", Message: "field id changes type from string to long"},
+			{Rule: "lower-case-fields", Message: "the schema fails schema.fields.all(f, f.path.lowerAscii() == f.path)"},
+		}
+		if len(schema.Status.PolicyViolations) != len(expected) ||
+			schema.Status.PolicyViolations[0] != expected[0] || schema.Status.PolicyViolations[1] != expected[1] {
+			t.Errorf("expected violations %+v, got %+v", expected, schema.Status.PolicyViolations)
+		}
+		if len(registry.Versions("orders-value")) != 1 {
+			t.Error("a schema failing the policy must not be registered")
+		}
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		rules.Data = map[string]string{"broken": "schema."}
+		if err := r.Update(ctx, rules); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		schema := reconcileSchema(t, r, "payments")
+		ready := meta.FindStatusCondition(schema.Status.Conditions, schemaReadyCondition)
+		if ready == nil || ready.Reason != "InvalidPolicy" {
+			t.Errorf("expected Ready=False with reason InvalidPolicy, got %+v", ready)
+		}
+	})
+
+	t.Run("ConfigMap changes requeue the schemas of the registries using it", func(t *testing.T) {
+		r := newTestSchemaReconciler(registry, sr, rules, compliant)
+		r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(sr, rules, compliant).
+			WithIndex(&strimziregistryoperatorv1alpha1.StrimziSchema{}, registryRefIndex, registryRefKey).Build()
+		requests := r.requestsForPolicyConfigMap(ctx, rules)
+		if len(requests) != 1 || requests[0].Name != "orders" {
+			t.Errorf("expected the orders schema to be requeued, got %+v", requests)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// referencedSecretIndex indexes StrimziSchemaRegistry objects by the user-supplied secrets
+// they reference, so that changes to those secrets can be mapped back without a full List.
+const referencedSecretIndex = ".spec.referencedSecrets"
+
+// referencedSecrets is the field indexer for referencedSecretIndex: the user-supplied secrets
+// (client CA, Basic authentication users, connection CA) a CR derives operator-managed secrets from.
+func referencedSecrets(obj client.Object) []string {
+	instance, ok := obj.(*strimziregistryoperatorv1alpha1.StrimziSchemaRegistry)
+	if !ok {
+		return nil
+	}
+	var names []string
+	if instance.Spec.TLS != nil && instance.Spec.TLS.ClientCASecretName != "" {
+		names = append(names, instance.Spec.TLS.ClientCASecretName)
+	}
+	if basicAuthEnabled(instance) {
+		names = append(names, instance.Spec.Authentication.Basic.UsersSecretName)
+	}
+	if instance.Spec.Connection != nil && instance.Spec.Connection.CASecretName != "" {
+		names = append(names, instance.Spec.Connection.CASecretName)
+	}
+	return names
+}
+
+// requestsForReferencedSecret returns the CRs referencing obj in their spec.
+func (r *StrimziSchemaRegistryReconciler) requestsForReferencedSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	list := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryList{}
+	err := r.List(ctx, list, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{referencedSecretIndex: obj.GetName()})
+	if err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace},
+		})
+	}
+	return requests
+}
+
+// podTemplateVersions returns, per pod template annotation, the ResourceVersion of the
+// operator-rendered secret it tracks; "" when the feature is disabled or the secret does
+// not exist yet. The secrets are only read, never created.
+func (r *StrimziSchemaRegistryReconciler) podTemplateVersions(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) (map[string]string, error) {
+	tracked := map[string]string{}
+	if clientAuthEnabled(instance) {
+		tracked[clientTruststoreVersionKey] = instance.Name + clientTruststoreSuffix
+	}
+	if basicAuthEnabled(instance) {
+		tracked[basicAuthVersionKey] = instance.Name + basicAuthSuffix
+	}
+
+	versions := map[string]string{clientTruststoreVersionKey: "", basicAuthVersionKey: ""}
+	for key, name := range tracked {
+		secret := &v1.Secret{}
+		err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, secret)
+		if err != nil {
+			if err = client.IgnoreNotFound(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		versions[key] = secret.ResourceVersion
+	}
+	return versions, nil
+}
+
+// applyPodTemplateVersions records the versions on the pod template, so a new rendered
+// secret rolls the pods. Empty versions remove the annotation. It returns true when the
+// template was modified.
+func applyPodTemplateVersions(dep *apps.Deployment, versions map[string]string) bool {
+	changed := false
+	for key, version := range versions {
+		current, ok := dep.Spec.Template.Annotations[key]
+		switch {
+		case version == "" && ok:
+			delete(dep.Spec.Template.Annotations, key)
+			changed = true
+		case version != "" && current != version:
+			if dep.Spec.Template.Annotations == nil {
+				dep.Spec.Template.Annotations = map[string]string{}
+			}
+			dep.Spec.Template.Annotations[key] = version
+			changed = true
+		}
+	}
+	return changed
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// jmxPortName is the Service port name used for remote JMX.
+	jmxPortName = "jmx"
+	// plainHTTPServicePort is the Service port of the plain HTTP listener kept in dual-listener mode.
+	plainHTTPServicePort int32 = 80
+	// legacyServiceTypeAnnotation was set by earlier operator versions and is removed on update.
+	legacyServiceTypeAnnotation = "type"
+	// Annotations recording which Service labels/annotations come from spec.service, so that
+	// keys removed from the spec are removed from the Service as well.
+	serviceManagedAnnotationsKey = keyPrefix + "/managedAnnotations"
+	serviceManagedLabelsKey      = keyPrefix + "/managedLabels"
+)
+
+// updateExistingService corrects drift between the existing Service and the desired one:
+// type, ports, labels, annotations and loadBalancerSourceRanges. Fields owned by Kubernetes
+// (cluster IP, allocated node ports, ...) and metadata set by other tools are kept.
+// It returns true when found was modified and needs to be updated.
+func updateExistingService(found, desired *v1.Service) bool {
+	changed := false
+
+	if found.Spec.Type != desired.Spec.Type {
+		found.Spec.Type = desired.Spec.Type
+		changed = true
+	}
+
+	ports := desiredServicePorts(found, desired)
+	if !slices.EqualFunc(found.Spec.Ports, ports, servicePortEqual) {
+		found.Spec.Ports = ports
+		changed = true
+	}
+
+	if !slices.Equal(found.Spec.LoadBalancerSourceRanges, desired.Spec.LoadBalancerSourceRanges) {
+		found.Spec.LoadBalancerSourceRanges = desired.Spec.LoadBalancerSourceRanges
+		changed = true
+	}
+
+	if _, ok := found.Annotations[legacyServiceTypeAnnotation]; ok {
+		delete(found.Annotations, legacyServiceTypeAnnotation)
+		changed = true
+	}
+	if mergeManagedMetadata(&found.Labels, &found.Annotations, serviceManagedLabelsKey, desired.Labels) {
+		changed = true
+	}
+	if mergeManagedMetadata(&found.Annotations, &found.Annotations, serviceManagedAnnotationsKey, desired.Annotations) {
+		changed = true
+	}
+	return changed
+}
+
+// dualListenerEnabled reports whether both the HTTP and the HTTPS listener are served.
+func dualListenerEnabled(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) bool {
+	return instance.Spec.SecureHTTP && instance.Spec.DualListener
+}
+
+// listenerStatuses returns the REST API listeners reachable through the Service. Their URLs
+// point at the Confluent-compatible API of the flavor.
+func listenerStatuses(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) []strimziregistryoperatorv1alpha1.ListenerStatus {
+	host := internalHostname(instance)
+	name, port := servicePort(instance)
+	apiPath := flavorFor(instance).apiPath()
+	listeners := []strimziregistryoperatorv1alpha1.ListenerStatus{
+		{Name: name, Port: port, URL: fmt.Sprintf("%s://%s:%d%s", name, host, port, apiPath)},
+	}
+	if dualListenerEnabled(instance) {
+		listeners = append(listeners, strimziregistryoperatorv1alpha1.ListenerStatus{
+			Name: "http", Port: plainHTTPServicePort, URL: fmt.Sprintf("http://%s:%d%s", host, plainHTTPServicePort, apiPath),
+		})
+	}
+	return listeners
+}
+
+// trackManagedMetadata records the labels and annotations of a Service about to be
+// created, so later updates can tell which keys the operator owns.
+func trackManagedMetadata(svc *v1.Service) {
+	labels, annotations := maps.Clone(svc.Labels), maps.Clone(svc.Annotations)
+	mergeManagedMetadata(&svc.Labels, &svc.Annotations, serviceManagedLabelsKey, labels)
+	mergeManagedMetadata(&svc.Annotations, &svc.Annotations, serviceManagedAnnotationsKey, annotations)
+}
+
+// desiredServicePorts returns the desired ports, keeping node ports Kubernetes allocated
+// for ports that do not pin one.
+func desiredServicePorts(found, desired *v1.Service) []v1.ServicePort {
+	ports := make([]v1.ServicePort, len(desired.Spec.Ports))
+	copy(ports, desired.Spec.Ports)
+	if desired.Spec.Type == v1.ServiceTypeClusterIP {
+		return ports
+	}
+	for i := range ports {
+		if ports[i].NodePort != 0 {
+			continue
+		}
+		for _, existing := range found.Spec.Ports {
+			if existing.Name == ports[i].Name {
+				ports[i].NodePort = existing.NodePort
+			}
+		}
+	}
+	return ports
+}
+
+// servicePortEqual compares the Service port fields managed by the operator.
+func servicePortEqual(a, b v1.ServicePort) bool {
+	return a.Name == b.Name && a.Port == b.Port && a.TargetPort == b.TargetPort &&
+		a.NodePort == b.NodePort && a.Protocol == b.Protocol
+}
+
+// mergeManagedMetadata applies desired to the target map (labels or annotations). Keys
+// recorded under trackingKey on the previous update but missing from desired are removed.
+// The tracking annotation is always stored in annotations.
+func mergeManagedMetadata(target, annotations *map[string]string, trackingKey string, desired map[string]string) bool {
+	changed := false
+	if *target == nil {
+		*target = map[string]string{}
+	}
+	if *annotations == nil {
+		*annotations = map[string]string{}
+	}
+
+	var previous []string
+	if tracked := (*annotations)[trackingKey]; tracked != "" {
+		previous = strings.Split(tracked, ",")
+	}
+	for _, key := range previous {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, ok := (*target)[key]; ok {
+			delete(*target, key)
+			changed = true
+		}
+	}
+
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		keys = append(keys, key)
+		if current, ok := (*target)[key]; !ok || current != value {
+			(*target)[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+	tracked := strings.Join(keys, ",")
+	if tracked == "" {
+		if _, ok := (*annotations)[trackingKey]; ok {
+			delete(*annotations, trackingKey)
+			changed = true
+		}
+	} else if (*annotations)[trackingKey] != tracked {
+		(*annotations)[trackingKey] = tracked
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestCreateService(t *testing.T) {
+	r := newTestReconciler()
+
+	t.Run("defaults keep the ClusterIP service on port 80", func(t *testing.T) {
+		svc, err := r.createService(newTestInstance(), logr.Discard())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if svc.Spec.Type != corev1.ServiceTypeClusterIP || len(svc.Spec.Ports) != 1 {
+			t.Fatalf("unexpected service spec: %+v", svc.Spec)
+		}
+		port := svc.Spec.Ports[0]
+		if port.Name != "http" || port.Port != 80 || port.TargetPort.IntValue() != 8081 {
+			t.Errorf("unexpected port: %+v", port)
+		}
+		if _, ok := svc.Annotations[legacyServiceTypeAnnotation]; ok {
+			t.Error("the legacy type annotation must not be set")
+		}
+	})
+
+	t.Run("spec.service is applied", func(t *testing.T) {
+		inst := newTestInstance()
+		inst.Spec.SecureHTTP = true
+		inst.Spec.Service = &strimziregistryoperatorv1alpha1.ServiceSpec{
+			Type:                     corev1.ServiceTypeLoadBalancer,
+			Port:                     ptr.To[int32](8443),
+			NodePort:                 ptr.To[int32](30443),
+			JMXPort:                  ptr.To[int32](9999),
+			Annotations:              map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+			Labels:                   map[string]string{"team": "data"},
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+		}
+		svc, err := r.createService(inst, logr.Discard())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || len(svc.Spec.LoadBalancerSourceRanges) != 1 {
+			t.Errorf("unexpected service spec: %+v", svc.Spec)
+		}
+		if len(svc.Spec.Ports) != 2 {
+			t.Fatalf("expected REST and JMX ports, got %+v", svc.Spec.Ports)
+		}
+		rest, jmx := svc.Spec.Ports[0], svc.Spec.Ports[1]
+		if rest.Name != "https" || rest.Port != 8443 || rest.NodePort != 30443 || rest.TargetPort.IntValue() != 8085 {
+			t.Errorf("unexpected REST port: %+v", rest)
+		}
+		if jmx.Name != jmxPortName || jmx.Port != 9999 || jmx.TargetPort.IntValue() != 9999 {
+			t.Errorf("unexpected JMX port: %+v", jmx)
+		}
+		if svc.Labels["team"] != "data" || svc.Annotations["service.beta.kubernetes.io/aws-load-balancer-internal"] != "true" {
+			t.Errorf("expected custom metadata, got labels=%v annotations=%v", svc.Labels, svc.Annotations)
+		}
+	})
+
+	t.Run("JMX port reaches the container and the spec hash", func(t *testing.T) {
+		inst := newTestInstance()
+		before, _ := computeSpecHash(inst)
+		inst.Spec.Service = &strimziregistryoperatorv1alpha1.ServiceSpec{JMXPort: ptr.To[int32](9999)}
+		after, _ := computeSpecHash(inst)
+		if before == after {
+			t.Error("enabling JMX must change the spec hash")
+		}
+		if !hasEnv(buildPodEnv(inst, "kafka:9093", ""), "SCHEMA_REGISTRY_JMX_PORT") {
+			t.Error("expected SCHEMA_REGISTRY_JMX_PORT env var")
+		}
+	})
+}
+
+func TestUpdateExistingService(t *testing.T) {
+	r := newTestReconciler()
+	inst := newTestInstance()
+	inst.Spec.Service = &strimziregistryoperatorv1alpha1.ServiceSpec{
+		Type:        corev1.ServiceTypeNodePort,
+		Annotations: map[string]string{"a": "1"},
+		Labels:      map[string]string{"team": "data"},
+	}
+	desired, _ := r.createService(inst, logr.Discard())
+
+	found := desired.DeepCopy()
+	trackManagedMetadata(found)
+	found.Spec.ClusterIP = "10.0.0.10"
+	found.Spec.Ports[0].NodePort = 31000 // allocated by Kubernetes
+	found.Annotations["kubectl.kubernetes.io/last-applied-configuration"] = "{}"
+
+	t.Run("no drift", func(t *testing.T) {
+		svc := found.DeepCopy()
+		if updateExistingService(svc, desired) {
+			t.Errorf("expected no change, got %+v", svc)
+		}
+	})
+
+	t.Run("type, metadata and ports are corrected", func(t *testing.T) {
+		svc := found.DeepCopy()
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+		svc.Spec.Ports[0].TargetPort = intstr.FromInt32(9090)
+		svc.Annotations["a"] = "changed"
+		svc.Annotations[legacyServiceTypeAnnotation] = "http"
+		if !updateExistingService(svc, desired) {
+			t.Fatal("expected drift to be detected")
+		}
+		if svc.Spec.Type != corev1.ServiceTypeNodePort || svc.Spec.Ports[0].TargetPort.IntValue() != 8081 {
+			t.Errorf("spec drift not corrected: %+v", svc.Spec)
+		}
+		if svc.Spec.Ports[0].NodePort != 31000 {
+			t.Errorf("allocated node port must be kept, got %d", svc.Spec.Ports[0].NodePort)
+		}
+		if svc.Annotations["a"] != "1" || svc.Annotations["kubectl.kubernetes.io/last-applied-configuration"] != "{}" {
+			t.Errorf("unexpected annotations: %v", svc.Annotations)
+		}
+		if _, ok := svc.Annotations[legacyServiceTypeAnnotation]; ok {
+			t.Error("legacy type annotation must be removed")
+		}
+		if svc.Spec.ClusterIP != "10.0.0.10" {
+			t.Error("cluster IP must be kept")
+		}
+	})
+
+	t.Run("keys removed from spec.service are removed", func(t *testing.T) {
+		svc := found.DeepCopy()
+		inst := inst.DeepCopy()
+		inst.Spec.Service.Annotations = nil
+		inst.Spec.Service.Labels = nil
+		next, _ := r.createService(inst, logr.Discard())
+		if !updateExistingService(svc, next) {
+			t.Fatal("expected drift to be detected")
+		}
+		if _, ok := svc.Annotations["a"]; ok {
+			t.Error("annotation removed from spec must be removed from the service")
+		}
+		if _, ok := svc.Labels["team"]; ok {
+			t.Error("label removed from spec must be removed from the service")
+		}
+		if svc.Labels["app.kubernetes.io/instance"] != "test-sr" {
+			t.Error("operator labels must be kept")
+		}
+	})
+}
+
+func TestDualListener(t *testing.T) {
+	r := newTestReconciler()
+	inst := newTestInstance()
+	inst.Spec.SecureHTTP = true
+	inst.Spec.DualListener = true
+
+	svc, err := r.createService(inst, logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(svc.Spec.Ports) != 2 || svc.Spec.Ports[0].Name != "https" || svc.Spec.Ports[1].Name != "http" ||
+		svc.Spec.Ports[1].Port != 80 || svc.Spec.Ports[1].TargetPort.IntValue() != 8081 {
+		t.Errorf("expected https and http ports, got %+v", svc.Spec.Ports)
+	}
+
+	for _, env := range buildPodEnv(inst, "kafka:9093", "tls") {
+		if env.Name == "SCHEMA_REGISTRY_LISTENERS" && env.Value != "http://0.0.0.0:8081,https://0.0.0.0:8085" {
+			t.Errorf("unexpected listeners: %q", env.Value)
+		}
+	}
+
+	listeners := listenerStatuses(inst)
+	if len(listeners) != 2 || listeners[0].URL != "https://test-sr.default.svc:443" || listeners[1].URL != "http://test-sr.default.svc:80" {
+		t.Errorf("unexpected listener status: %+v", listeners)
+	}
+
+	dual, _ := computeSpecHash(inst)
+	inst.Spec.DualListener = false
+	single, _ := computeSpecHash(inst)
+	if single == dual {
+		t.Error("toggling dualListener must change the spec hash")
+	}
+	if len(listenerStatuses(inst)) != 1 {
+		t.Error("expected a single listener without dualListener")
+	}
+}
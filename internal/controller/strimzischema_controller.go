@@ -0,0 +1,284 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// schemaConfigMapIndex indexes StrimziSchemas by the ConfigMap holding their definition.
+	schemaConfigMapIndex = ".spec.schemaFrom.name"
+
+	schemaReadyCondition      = "Ready"
+	schemaCompatibleCondition = "Compatible"
+
+	// schemaResyncPeriod is how often registered schemas are checked against the registry,
+	// to register them again when the subject was deleted by hand.
+	schemaResyncPeriod = 5 * time.Minute
+	// registryNotReadyRequeue is the retry delay while the registry is not ready.
+	registryNotReadyRequeue = 30 * time.Second
+)
+
+// StrimziSchemaReconciler registers StrimziSchema resources into their StrimziSchemaRegistry.
+type StrimziSchemaReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// NewRegistryClient builds the REST API client. Defaults to registryclient.New.
+	NewRegistryClient RegistryClientFactory
+}
+
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemas,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemas/status,verbs=get;update;patch
+
+// Reconcile registers the schema and records its ID and version in the status. Failures are
+// reported through the Ready and Compatible conditions.
+func (r *StrimziSchemaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	schema := &strimziregistryoperatorv1alpha1.StrimziSchema{}
+	if err := r.Get(ctx, req.NamespacedName, schema); err != nil {
+		if errors.IsNotFound(err) {
+			// Registered schemas are kept in the registry: subjects are deleted explicitly.
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get StrimziSchema")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+
+	result, err := r.registerSchema(ctx, schema, logger)
+	if statusErr := r.Status().Update(ctx, schema); statusErr != nil {
+		logger.Error(statusErr, "Failed to update StrimziSchema status")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil {
+		logger.Error(err, "Failed to register schema", "Subject", schema.Spec.Subject)
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+	}
+	return result, err
+}
+
+// registerSchema registers the schema unless the registry already holds it under the subject or
+// it fails the schema policy of the registry, and updates the status in memory. The subject is
+// moved into the context of the namespace when the registry is shared through spec.tenancy.
+// Only transient failures are returned as errors; rejected schemas wait for a spec change or the
+// next resync.
+func (r *StrimziSchemaReconciler) registerSchema(ctx context.Context,
+	schema *strimziregistryoperatorv1alpha1.StrimziSchema, logger logr.Logger) (ctrl.Result, error) {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	registryKey := registryReference(schema.Spec.Registry, schema.Namespace)
+	if err := r.Get(ctx, registryKey, registry); err != nil {
+		if errors.IsNotFound(err) {
+			setSchemaReady(schema, metav1.ConditionFalse, "RegistryNotFound", fmt.Sprintf("StrimziSchemaRegistry %s not found", registryKey))
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !meta.IsStatusConditionTrue(registry.Status.Conditions, "Ready") {
+		setSchemaReady(schema, metav1.ConditionFalse, "RegistryNotReady", fmt.Sprintf("StrimziSchemaRegistry %s is not ready", registryKey))
+		return ctrl.Result{RequeueAfter: registryNotReadyRequeue}, nil
+	}
+
+	definition, err := schemaDefinition(ctx, r.Client, schema)
+	if err != nil {
+		setSchemaReady(schema, metav1.ConditionFalse, "SchemaSourceError", err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	subject, err := tenantSchema(ctx, r.Client, registry, schema, &definition)
+	if reason := tenancyReason(err); reason != "" {
+		setSchemaReady(schema, metav1.ConditionFalse, reason, err.Error())
+		return ctrl.Result{RequeueAfter: schemaResyncPeriod}, nil
+	} else if err != nil {
+		setSchemaReady(schema, metav1.ConditionFalse, "TenancyUnresolved", err.Error())
+		return ctrl.Result{}, err
+	}
+	schema.Status.Subject = subject
+
+	rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, registry)
+	if err != nil {
+		setSchemaReady(schema, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+		return ctrl.Result{}, err
+	}
+	if registryReadOnly(registry) {
+		return lookupReplicatedSchema(ctx, schema, subject, definition, rc)
+	}
+	if compliant, err := r.checkSchemaPolicy(ctx, schema, subject, registry, definition, rc); !compliant {
+		return ctrl.Result{}, err
+	}
+
+	registered, err := rc.LookupSchema(ctx, subject, definition)
+	if registryclient.IsNotFound(err) {
+		logger.Info("Registering schema", "Subject", subject)
+		_, err = rc.RegisterSchema(ctx, subject, definition)
+		if err == nil {
+			registered, err = rc.LookupSchema(ctx, subject, definition)
+		}
+	}
+	switch {
+	case registryclient.IsIncompatible(err):
+		setSchemaCondition(schema, schemaCompatibleCondition, metav1.ConditionFalse, "Incompatible", err.Error())
+		setSchemaReady(schema, metav1.ConditionFalse, "Incompatible", "the schema is incompatible with the subject")
+		return ctrl.Result{RequeueAfter: schemaResyncPeriod}, nil
+	case registryclient.IsInvalidSchema(err):
+		setSchemaReady(schema, metav1.ConditionFalse, "InvalidSchema", err.Error())
+		return ctrl.Result{}, nil
+	case err != nil:
+		setSchemaReady(schema, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	schema.Status.ID = registered.ID
+	schema.Status.Version = registered.Version
+	schema.Status.ObservedGeneration = schema.Generation
+	setSchemaCondition(schema, schemaCompatibleCondition, metav1.ConditionTrue, "Compatible", "the schema is registered under the subject")
+	setSchemaReady(schema, metav1.ConditionTrue, "Registered", fmt.Sprintf("registered as version %d with ID %d", registered.Version, registered.ID))
+	return ctrl.Result{RequeueAfter: schemaResyncPeriod}, nil
+}
+
+// lookupReplicatedSchema records the ID and version of a schema in a read-only secondary, which
+// holds the schemas replicated from its primary, and updates the status in memory.
+func lookupReplicatedSchema(ctx context.Context, schema *strimziregistryoperatorv1alpha1.StrimziSchema,
+	subject string, definition registryclient.Schema, rc *registryclient.Client) (ctrl.Result, error) {
+	registered, err := rc.LookupSchema(ctx, subject, definition)
+	switch {
+	case registryclient.IsNotFound(err):
+		setSchemaReady(schema, metav1.ConditionFalse, "NotReplicated",
+			"the registry is a read-only secondary and does not hold the schema yet")
+		return ctrl.Result{RequeueAfter: schemaResyncPeriod}, nil
+	case err != nil:
+		setSchemaReady(schema, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+		return ctrl.Result{}, err
+	}
+	schema.Status.ID = registered.ID
+	schema.Status.Version = registered.Version
+	schema.Status.ObservedGeneration = schema.Generation
+	setSchemaReady(schema, metav1.ConditionTrue, "Replicated", fmt.Sprintf("replicated as version %d with ID %d", registered.Version, registered.ID))
+	return ctrl.Result{RequeueAfter: schemaResyncPeriod}, nil
+}
+
+// schemaDefinition returns the schema as sent to the registry, reading spec.schemaFrom if set.
+func schemaDefinition(ctx context.Context, c client.Reader,
+	schema *strimziregistryoperatorv1alpha1.StrimziSchema) (registryclient.Schema, error) {
+	definition := registryclient.Schema{
+		Schema:     schema.Spec.Schema,
+		SchemaType: string(schema.Spec.SchemaType),
+	}
+	if definition.SchemaType == "" {
+		definition.SchemaType = string(strimziregistryoperatorv1alpha1.SchemaTypeAvro)
+	}
+	for _, ref := range schema.Spec.References {
+		definition.References = append(definition.References, registryclient.Reference{
+			Name: ref.Name, Subject: ref.Subject, Version: ref.Version,
+		})
+	}
+	if from := schema.Spec.SchemaFrom; from != nil {
+		configMap := &v1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Name: from.Name, Namespace: schema.Namespace}, configMap); err != nil {
+			return definition, fmt.Errorf("failed to get ConfigMap %s: %w", from.Name, err)
+		}
+		value, ok := configMap.Data[from.Key]
+		if !ok {
+			return definition, fmt.Errorf("ConfigMap %s has no key %q", from.Name, from.Key)
+		}
+		definition.Schema = value
+	}
+	return definition, nil
+}
+
+// setSchemaReady sets the Ready condition of a StrimziSchema.
+func setSchemaReady(schema *strimziregistryoperatorv1alpha1.StrimziSchema, status metav1.ConditionStatus, reason, message string) {
+	setSchemaCondition(schema, schemaReadyCondition, status, reason, message)
+}
+
+// setSchemaCondition sets a condition of a StrimziSchema for its current generation.
+func setSchemaCondition(schema *strimziregistryoperatorv1alpha1.StrimziSchema, conditionType string,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&schema.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: schema.Generation,
+	})
+}
+
+// schemaConfigMapKey returns the schemaConfigMapIndex value of a StrimziSchema.
+func schemaConfigMapKey(obj client.Object) []string {
+	schema, ok := obj.(*strimziregistryoperatorv1alpha1.StrimziSchema)
+	if !ok || schema.Spec.SchemaFrom == nil {
+		return nil
+	}
+	return []string{schema.Spec.SchemaFrom.Name}
+}
+
+// requestsForIndex returns the StrimziSchemas matching value in a field index.
+func (r *StrimziSchemaReconciler) requestsForIndex(ctx context.Context, index, value string, opts ...client.ListOption) []reconcile.Request {
+	list := &strimziregistryoperatorv1alpha1.StrimziSchemaList{}
+	if err := r.List(ctx, list, append(opts, client.MatchingFields{index: value})...); err != nil {
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.Name, Namespace: item.Namespace}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StrimziSchemaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchema{},
+		registryRefIndex, registryRefKey); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchema{},
+		schemaConfigMapIndex, schemaConfigMapKey); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&strimziregistryoperatorv1alpha1.StrimziSchema{}).
+		Watches(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{},
+			handler.EnqueueRequestsFromMapFunc(requestsForRegistryRef(r.Client, &strimziregistryoperatorv1alpha1.StrimziSchemaList{}))).
+		Watches(&v1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+				return append(r.requestsForIndex(ctx, schemaConfigMapIndex, obj.GetName(), client.InNamespace(obj.GetNamespace())),
+					r.requestsForPolicyConfigMap(ctx, obj)...)
+			})).
+		Named("strimzischema").
+		Complete(r)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testAvroSchema = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+
+func newTestSchema(name, subject string) *strimziregistryoperatorv1alpha1.StrimziSchema {
+	return &strimziregistryoperatorv1alpha1.StrimziSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1},
+		Spec: strimziregistryoperatorv1alpha1.StrimziSchemaSpec{
+			Registry: strimziregistryoperatorv1alpha1.RegistryReference{Name: "test-sr"},
+			Subject:  subject,
+			Schema:   testAvroSchema,
+		},
+	}
+}
+
+// newTestSchemaReconciler returns a reconciler whose registry clients target the fake registry.
+func newTestSchemaReconciler(registry *testutil.FakeRegistry, objs ...client.Object) *StrimziSchemaReconciler {
+	c := newRegistryResourceClient(&strimziregistryoperatorv1alpha1.StrimziSchema{}, objs...)
+	return &StrimziSchemaReconciler{Client: c, Scheme: c.Scheme(), NewRegistryClient: fakeRegistryClientFactory(registry.URL)}
+}
+
+func reconcileSchema(t *testing.T, r *StrimziSchemaReconciler, name string) *strimziregistryoperatorv1alpha1.StrimziSchema {
+	t.Helper()
+	schema := &strimziregistryoperatorv1alpha1.StrimziSchema{}
+	reconcileRegistryResource(t, r, r.Client, name, schema)
+	return schema
+}
+
+func TestStrimziSchemaReconcile(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	registry.Incompatible = func(_, schema string) bool { return strings.Contains(schema, "breaking") }
+
+	fromConfigMap := newTestSchema("payments", "payments-value")
+	fromConfigMap.Spec.Schema = ""
+	fromConfigMap.Spec.SchemaType = strimziregistryoperatorv1alpha1.SchemaTypeJSON
+	fromConfigMap.Spec.SchemaFrom = &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "schemas"}, Key: "payments.json",
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "schemas", Namespace: "default"},
+		Data:       map[string]string{"payments.json": `{"type":"object"}`},
+	}
+	r := newTestSchemaReconciler(registry, newReadyRegistry(), configMap,
+		newTestSchema("orders", "orders-value"), fromConfigMap)
+
+	t.Run("schema is registered and its ID and version reported", func(t *testing.T) {
+		schema := reconcileSchema(t, r, "orders")
+		versions := registry.Versions("orders-value")
+		if len(versions) != 1 || schema.Status.ID != versions[0].ID || schema.Status.Version != 1 {
+			t.Errorf("unexpected status %+v, registry holds %+v", schema.Status, versions)
+		}
+		if !meta.IsStatusConditionTrue(schema.Status.Conditions, schemaReadyCondition) ||
+			!meta.IsStatusConditionTrue(schema.Status.Conditions, schemaCompatibleCondition) {
+			t.Errorf("expected Ready and Compatible conditions, got %+v", schema.Status.Conditions)
+		}
+
+		reconcileSchema(t, r, "orders")
+		if len(registry.Versions("orders-value")) != 1 {
+			t.Error("reconciling again must not register a new version")
+		}
+	})
+
+	t.Run("schema is read from the ConfigMap", func(t *testing.T) {
+		schema := reconcileSchema(t, r, "payments")
+		versions := registry.Versions("payments-value")
+		if len(versions) != 1 || versions[0].SchemaType != "JSON" || versions[0].Schema != `{"type":"object"}` {
+			t.Errorf("unexpected registered schema %+v", versions)
+		}
+		if schema.Status.ID != versions[0].ID {
+			t.Errorf("unexpected status %+v", schema.Status)
+		}
+	})
+
+	t.Run("subject deleted by hand is registered again", func(t *testing.T) {
+		registry.DeleteSubject("orders-value")
+		reconcileSchema(t, r, "orders")
+		if len(registry.Versions("orders-value")) != 1 {
+			t.Error("expected the schema to be registered again")
+		}
+	})
+
+	t.Run("incompatible schema sets the Compatible condition", func(t *testing.T) {
+		schema := &strimziregistryoperatorv1alpha1.StrimziSchema{}
+		_ = r.Get(context.Background(), types.NamespacedName{Name: "orders", Namespace: "default"}, schema)
+		schema.Spec.Schema = `{"type":"record","name":"Order","fields":[{"name":"breaking","type":"int"}]}`
+		schema.Generation = 2
+		if err := r.Update(context.Background(), schema); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		schema = reconcileSchema(t, r, "orders")
+		compatible := meta.FindStatusCondition(schema.Status.Conditions, schemaCompatibleCondition)
+		if compatible == nil || compatible.Status != metav1.ConditionFalse || compatible.Reason != "Incompatible" ||
+			!strings.Contains(compatible.Message, "incompatible") {
+			t.Errorf("expected Compatible=False, got %+v", compatible)
+		}
+		if meta.IsStatusConditionTrue(schema.Status.Conditions, schemaReadyCondition) {
+			t.Error("an incompatible schema must not be Ready")
+		}
+		if schema.Status.Version != 1 {
+			t.Error("the last registered version must be kept")
+		}
+	})
+}
+
+func TestStrimziSchemaRegistryNotReady(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	t.Run("missing registry", func(t *testing.T) {
+		r := newTestSchemaReconciler(registry, newTestSchema("orders", "orders-value"))
+		schema := reconcileSchema(t, r, "orders")
+		ready := meta.FindStatusCondition(schema.Status.Conditions, schemaReadyCondition)
+		if ready == nil || ready.Reason != "RegistryNotFound" {
+			t.Errorf("expected RegistryNotFound, got %+v", ready)
+		}
+	})
+
+	t.Run("registry not ready", func(t *testing.T) {
+		r := newTestSchemaReconciler(registry, newTestInstance(), newTestSchema("orders", "orders-value"))
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "orders", Namespace: "default"}})
+		if err != nil || result.RequeueAfter != registryNotReadyRequeue {
+			t.Errorf("expected a delayed retry, got %+v (err: %v)", result, err)
+		}
+		if len(registry.Versions("orders-value")) != 0 {
+			t.Error("nothing must be registered before the registry is ready")
+		}
+	})
+}
+
+func TestRegistryClientConfig(t *testing.T) {
+	ctx := context.Background()
+	registry := newBasicAuthInstance()
+	registry.Spec.Connection = &strimziregistryoperatorv1alpha1.ConnectionSpec{BasicAuthUser: "ci"}
+	users := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-users", Namespace: "default"},
+		Data:       map[string][]byte{"ci": []byte("ci-pw,developer")},
+	}
+	c := fake.NewClientBuilder().WithScheme(newTestReconciler().Scheme).WithObjects(users).Build()
+
+	cfg, err := registryClientConfig(ctx, c, registry)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.URL != listenerStatuses(registry)[0].URL || cfg.Username != "ci" || cfg.Password != "ci-pw" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	registry.Spec.Connection = nil
+	if _, err := registryClientConfig(ctx, c, registry); err == nil {
+		t.Error("expected an error without credentials for the operator")
+	}
+}
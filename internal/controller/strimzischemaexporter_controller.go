@@ -0,0 +1,649 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// exporterFinalizer deletes the Schema Registry exporter on deletion.
+	exporterFinalizer = keyPrefix + "/exporter"
+	// exporterResyncPeriod is how often the lag is measured and, with the Copy method, the
+	// missing versions are copied.
+	exporterResyncPeriod = time.Minute
+)
+
+// errExporterCredentialsRequired rejects exporters to a StrimziSchemaRegistry authenticating its
+// clients without spec.destination.credentialsSecretName.
+var errExporterCredentialsRequired = errors.New("exporter credentials required")
+
+// StrimziSchemaExporterReconciler keeps the schemas of a StrimziSchemaRegistry in another
+// registry, through a Schema Registry exporter or by copying them in IMPORT mode.
+type StrimziSchemaExporterReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// NewRegistryClient builds the REST API clients. Defaults to registryclient.New.
+	NewRegistryClient RegistryClientFactory
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaexporters,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaexporters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaexporters/finalizers,verbs=update
+
+// Reconcile brings the export in line with the spec and reports its lag.
+func (r *StrimziSchemaExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &strimziregistryoperatorv1alpha1.StrimziSchemaExporter{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get StrimziSchemaExporter")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+
+	if instance.DeletionTimestamp != nil {
+		if !controllerutil.ContainsFinalizer(instance, exporterFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.removeExporter(ctx, instance, logger); err != nil {
+			logger.Error(err, "Failed to delete the exporter from the registry")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(instance, exporterFinalizer)
+		return ctrl.Result{}, r.Update(ctx, instance)
+	}
+	if !controllerutil.ContainsFinalizer(instance, exporterFinalizer) {
+		controllerutil.AddFinalizer(instance, exporterFinalizer)
+		if err := r.Update(ctx, instance); err != nil {
+			logger.Error(err, "Failed to add finalizer to StrimziSchemaExporter")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.reconcileExport(ctx, instance, logger)
+	if statusErr := r.Status().Update(ctx, instance); statusErr != nil {
+		logger.Error(statusErr, "Failed to update StrimziSchemaExporter status")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil {
+		logger.Error(err, "Failed to export schemas")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+	}
+	return result, err
+}
+
+func (r *StrimziSchemaExporterReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// registryClient builds the REST API client of cfg.
+func (r *StrimziSchemaExporterReconciler) registryClient(cfg registryclient.Config) (*registryclient.Client, error) {
+	if r.NewRegistryClient != nil {
+		return r.NewRegistryClient(cfg)
+	}
+	return registryclient.New(cfg)
+}
+
+// reconcileExport runs the export with the method of the spec and updates the status in memory.
+func (r *StrimziSchemaExporterReconciler) reconcileExport(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, logger logr.Logger) (ctrl.Result, error) {
+	spec, status := instance.Spec, &instance.Status
+	source, result, err := r.sourceClient(ctx, instance)
+	if source == nil {
+		return result, err
+	}
+	destinationCfg, destinationRegistry, err := r.destinationConfig(ctx, instance)
+	if errors.Is(err, errRegistryReadOnly) {
+		setExporterReady(instance, metav1.ConditionFalse, "ReadOnlyRegistry", err.Error())
+		return ctrl.Result{}, nil
+	} else if reason := tenancyReason(err); reason != "" {
+		setExporterReady(instance, metav1.ConditionFalse, reason, err.Error())
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		setExporterReady(instance, metav1.ConditionFalse, "DestinationUnavailable", err.Error())
+		return ctrl.Result{}, err
+	}
+	destination, err := r.registryClient(destinationCfg)
+	if err != nil {
+		setExporterReady(instance, metav1.ConditionFalse, "DestinationUnavailable", err.Error())
+		return ctrl.Result{}, err
+	}
+
+	method := spec.Method
+	if method == "" || method == strimziregistryoperatorv1alpha1.ExporterMethodAuto {
+		method = strimziregistryoperatorv1alpha1.ExporterMethodExporter
+		if _, err := source.ListExporters(ctx); registryclient.IsNotFound(err) {
+			method = strimziregistryoperatorv1alpha1.ExporterMethodCopy
+		} else if err != nil {
+			return exporterFailure(instance, err)
+		}
+	}
+	if status.Method == strimziregistryoperatorv1alpha1.ExporterMethodExporter && method != status.Method {
+		logger.Info("Deleting the exporter replaced by the copy loop", "Exporter", instance.Name)
+		if err := source.DeleteExporter(ctx, instance.Name); err != nil && !registryclient.IsNotFound(err) {
+			return exporterFailure(instance, err)
+		}
+	}
+	status.Method = method
+
+	// The lag is the number of versions missing from the destination; the exporter itself only
+	// reports its offset in the schemas topic of the source registry.
+	pending, pendingErr := pendingExportVersions(ctx, source, destination, spec)
+	trace := ""
+	if method == strimziregistryoperatorv1alpha1.ExporterMethodExporter {
+		exporterCfg, err := r.exporterDestinationConfig(ctx, instance, destinationRegistry, destinationCfg)
+		if errors.Is(err, errExporterCredentialsRequired) {
+			setExporterReady(instance, metav1.ConditionFalse, "CredentialsRequired", err.Error())
+			return ctrl.Result{RequeueAfter: exporterResyncPeriod}, nil
+		} else if err != nil {
+			setExporterReady(instance, metav1.ConditionFalse, "DestinationUnavailable", err.Error())
+			return ctrl.Result{}, err
+		}
+		progress, err := r.syncExporter(ctx, instance, source, exporterClientConfig(exporterCfg), logger)
+		if err != nil {
+			return exporterFailure(instance, err)
+		}
+		status.State, status.Offset, trace = progress.State, progress.Offset, progress.Trace
+	} else {
+		status.Offset = 0
+		status.State = registryclient.ExporterStatePaused
+		if pendingErr != nil {
+			status.Lag = nil
+			return exporterFailure(instance, pendingErr)
+		}
+		if !spec.Suspend {
+			status.State = registryclient.ExporterStateRunning
+			copied, switched, err := copyExportVersions(ctx, destination, pending, logger)
+			status.ImportSubjects = slices.Compact(slices.Sorted(slices.Values(append(status.ImportSubjects, switched...))))
+			if pending = pending[copied:]; err != nil {
+				status.Lag = ptr.To(int32(len(pending)))
+				return exporterFailure(instance, err)
+			}
+		}
+	}
+
+	status.ObservedGeneration = instance.Generation
+	if pendingErr != nil {
+		status.Lag = nil
+	} else {
+		status.Lag = ptr.To(int32(len(pending)))
+		if len(pending) == 0 {
+			status.LastSyncTime = &metav1.Time{Time: r.now()}
+		}
+	}
+	switch {
+	case status.State == registryclient.ExporterStateError:
+		setExporterReady(instance, metav1.ConditionFalse, "ExporterFailed", fmt.Sprintf("exporter %s failed: %s", instance.Name, trace))
+	case spec.Suspend:
+		setExporterReady(instance, metav1.ConditionTrue, "Suspended", "the export is paused")
+	case pendingErr != nil:
+		setExporterReady(instance, metav1.ConditionTrue, "Exporting",
+			fmt.Sprintf("exporting with method %s; the lag is unknown: %v", method, pendingErr))
+	default:
+		setExporterReady(instance, metav1.ConditionTrue, "Exporting",
+			fmt.Sprintf("exporting with method %s, %d versions behind", method, len(pending)))
+	}
+	return ctrl.Result{RequeueAfter: exporterResyncPeriod}, nil
+}
+
+// sourceClient returns a REST API client for the source registry. It returns nil without error
+// when the registry cannot be called yet, or must not be by instance, after setting the Ready
+// condition.
+func (r *StrimziSchemaExporterReconciler) sourceClient(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter) (*registryclient.Client, ctrl.Result, error) {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	registryKey := registryReference(instance.Spec.Registry, instance.Namespace)
+	if err := r.Get(ctx, registryKey, registry); err != nil {
+		if apierrors.IsNotFound(err) {
+			setExporterReady(instance, metav1.ConditionFalse, "RegistryNotFound", fmt.Sprintf("StrimziSchemaRegistry %s not found", registryKey))
+			return nil, ctrl.Result{}, nil
+		}
+		return nil, ctrl.Result{}, err
+	}
+	if !meta.IsStatusConditionTrue(registry.Status.Conditions, "Ready") {
+		setExporterReady(instance, metav1.ConditionFalse, "RegistryNotReady", fmt.Sprintf("StrimziSchemaRegistry %s is not ready", registryKey))
+		return nil, ctrl.Result{RequeueAfter: registryNotReadyRequeue}, nil
+	}
+	if err := registryWideAccess(registry, instance.Namespace); err != nil {
+		setExporterReady(instance, metav1.ConditionFalse, tenancyReason(err), err.Error())
+		return nil, ctrl.Result{}, nil
+	}
+	rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, registry)
+	if err != nil {
+		setExporterReady(instance, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+		return nil, ctrl.Result{}, err
+	}
+	return rc, ctrl.Result{}, nil
+}
+
+// destinationConfig returns how the operator reaches the destination registry: a
+// StrimziSchemaRegistry the way the operator reaches it, returned along with its config, or
+// spec.destination.url with the credentials of its Secret. Read-only StrimziSchemaRegistries are
+// rejected with errRegistryReadOnly and those the namespace may not act on with
+// errNamespaceNotAllowed.
+func (r *StrimziSchemaExporterReconciler) destinationConfig(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter) (registryclient.Config,
+	*strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, error) {
+	destination := instance.Spec.Destination
+	if destination.Registry == nil {
+		cfg, err := r.destinationCredentials(ctx, instance, registryclient.Config{URL: destination.URL})
+		return cfg, nil, err
+	}
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	registryKey := registryReference(*destination.Registry, instance.Namespace)
+	if err := r.Get(ctx, registryKey, registry); err != nil {
+		return registryclient.Config{}, nil, fmt.Errorf("failed to get destination StrimziSchemaRegistry %s: %w", registryKey, err)
+	}
+	if err := registryWideAccess(registry, instance.Namespace); err != nil {
+		return registryclient.Config{}, nil, err
+	}
+	if err := registryWritable(registry); err != nil {
+		return registryclient.Config{}, nil, err
+	}
+	cfg, err := registryClientConfig(ctx, r.Client, registry)
+	return cfg, registry, err
+}
+
+// exporterDestinationConfig returns how the exporter of the source registry reaches the
+// destination. Its config can be read back through the REST API of the source registry, so the
+// operator's own credentials for a destination StrimziSchemaRegistry are never passed on: only
+// its URL and CA are, along with spec.destination.credentialsSecretName, which is required when
+// the registry authenticates its clients.
+func (r *StrimziSchemaExporterReconciler) exporterDestinationConfig(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, cfg registryclient.Config) (registryclient.Config, error) {
+	if registry == nil {
+		return cfg, nil
+	}
+	if instance.Spec.Destination.CredentialsSecretName == "" &&
+		(basicAuthEnabled(registry) || clientAuthMode(registry) == strimziregistryoperatorv1alpha1.ClientAuthRequired) {
+		return registryclient.Config{}, fmt.Errorf("%w: destination StrimziSchemaRegistry %s/%s authenticates its clients: set spec.destination.credentialsSecretName",
+			errExporterCredentialsRequired, registry.Namespace, registry.Name)
+	}
+	return r.destinationCredentials(ctx, instance, registryclient.Config{URL: cfg.URL, CACert: cfg.CACert})
+}
+
+// destinationCredentials adds the credentials of spec.destination.credentialsSecretName to cfg.
+func (r *StrimziSchemaExporterReconciler) destinationCredentials(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, cfg registryclient.Config) (registryclient.Config, error) {
+	name := instance.Spec.Destination.CredentialsSecretName
+	if name == "" {
+		return cfg, nil
+	}
+	secret := &v1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, secret); err != nil {
+		return cfg, fmt.Errorf("failed to get credentials secret %s: %w", name, err)
+	}
+	cfg.Username, cfg.Password = string(secret.Data["username"]), string(secret.Data["password"])
+	if ca := secret.Data["ca.crt"]; len(ca) > 0 {
+		cfg.CACert = ca
+	}
+	cfg.ClientCert, cfg.ClientKey = secret.Data["tls.crt"], secret.Data["tls.key"]
+	return cfg, nil
+}
+
+// exporterClientConfig returns the client configuration an exporter uses to reach the
+// registry described by cfg, PEM material being passed inline.
+func exporterClientConfig(cfg registryclient.Config) map[string]string {
+	config := map[string]string{"schema.registry.url": cfg.URL}
+	if cfg.Username != "" {
+		config["basic.auth.credentials.source"] = "USER_INFO"
+		config["basic.auth.user.info"] = cfg.Username + ":" + cfg.Password
+	}
+	if len(cfg.CACert) > 0 {
+		config["schema.registry.ssl.truststore.type"] = "PEM"
+		config["schema.registry.ssl.truststore.certificates"] = string(cfg.CACert)
+	}
+	if len(cfg.ClientCert) > 0 {
+		config["schema.registry.ssl.keystore.type"] = "PEM"
+		config["schema.registry.ssl.keystore.certificate.chain"] = string(cfg.ClientCert)
+		config["schema.registry.ssl.keystore.key"] = string(cfg.ClientKey)
+	}
+	return config
+}
+
+// desiredExporter returns the exporter of the spec, named after the resource.
+func desiredExporter(instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, config map[string]string) registryclient.Exporter {
+	spec := instance.Spec
+	exporter := registryclient.Exporter{
+		Name:                instance.Name,
+		ContextType:         registryclient.ExporterContextNone,
+		Subjects:            exportedSubjectPatterns(spec),
+		SubjectRenameFormat: spec.SubjectRenameFormat,
+		Config:              config,
+	}
+	if spec.ContextType == strimziregistryoperatorv1alpha1.ExporterContextCustom {
+		exporter.ContextType, exporter.Context = registryclient.ExporterContextCustom, spec.Context
+	}
+	return exporter
+}
+
+// exporterMatches reports whether the registry exporter holds the desired settings.
+func exporterMatches(current *registryclient.Exporter, desired registryclient.Exporter) bool {
+	return current.ContextType == desired.ContextType && current.Context == desired.Context &&
+		slices.Equal(current.Subjects, desired.Subjects) && current.SubjectRenameFormat == desired.SubjectRenameFormat &&
+		maps.Equal(current.Config, desired.Config)
+}
+
+// syncExporter creates or updates the exporter of the source registry, pauses or resumes it
+// according to spec.suspend and returns its progress.
+func (r *StrimziSchemaExporterReconciler) syncExporter(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, source *registryclient.Client,
+	config map[string]string, logger logr.Logger) (*registryclient.ExporterStatus, error) {
+	desired := desiredExporter(instance, config)
+	current, err := source.GetExporter(ctx, desired.Name)
+	switch {
+	case registryclient.IsNotFound(err):
+		logger.Info("Creating exporter", "Exporter", desired.Name)
+		if err := source.CreateExporter(ctx, desired); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case !exporterMatches(current, desired):
+		// The registry only accepts updates of paused exporters.
+		logger.Info("Updating exporter", "Exporter", desired.Name)
+		progress, err := source.GetExporterStatus(ctx, desired.Name)
+		if err != nil {
+			return nil, err
+		}
+		if progress.State != registryclient.ExporterStatePaused {
+			if err := source.PauseExporter(ctx, desired.Name); err != nil {
+				return nil, err
+			}
+		}
+		if err := source.UpdateExporter(ctx, desired); err != nil {
+			return nil, err
+		}
+		if err := source.ResumeExporter(ctx, desired.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	progress, err := source.GetExporterStatus(ctx, desired.Name)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case instance.Spec.Suspend && progress.State != registryclient.ExporterStatePaused:
+		if err := source.PauseExporter(ctx, desired.Name); err != nil {
+			return nil, err
+		}
+		progress.State = registryclient.ExporterStatePaused
+	case !instance.Spec.Suspend && progress.State == registryclient.ExporterStatePaused:
+		if err := source.ResumeExporter(ctx, desired.Name); err != nil {
+			return nil, err
+		}
+		progress.State = registryclient.ExporterStateRunning
+	}
+	return progress, nil
+}
+
+// exportedSubjectPatterns returns spec.subjects, "*" when empty.
+func exportedSubjectPatterns(spec strimziregistryoperatorv1alpha1.StrimziSchemaExporterSpec) []string {
+	if len(spec.Subjects) == 0 {
+		return []string{"*"}
+	}
+	return spec.Subjects
+}
+
+// exportDestinationSubject returns the name of subject at the destination, renamed by
+// spec.subjectRenameFormat and moved into spec.context.
+func exportDestinationSubject(spec strimziregistryoperatorv1alpha1.StrimziSchemaExporterSpec, subject string) string {
+	if spec.SubjectRenameFormat != "" {
+		subject = strings.ReplaceAll(spec.SubjectRenameFormat, "${subject}", subject)
+	}
+	if spec.ContextType == strimziregistryoperatorv1alpha1.ExporterContextCustom {
+		subject = ":." + spec.Context + ":" + subject
+	}
+	return subject
+}
+
+// pendingExportVersions returns the versions of the exported subjects missing from the
+// destination, in ID order, with their destination subject and references.
+func pendingExportVersions(ctx context.Context, source, destination *registryclient.Client,
+	spec strimziregistryoperatorv1alpha1.StrimziSchemaExporterSpec) ([]registryclient.SubjectVersion, error) {
+	var subjects []string
+	for _, pattern := range exportedSubjectPatterns(spec) {
+		if pattern != "*" {
+			subjects = append(subjects, pattern)
+			continue
+		}
+		names, err := source.ListSubjects(ctx, "", false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the subjects of the source registry: %w", err)
+		}
+		for _, name := range names {
+			if !strings.HasPrefix(name, ":.") {
+				subjects = append(subjects, name)
+			}
+		}
+	}
+	slices.Sort(subjects)
+	subjects = slices.Compact(subjects)
+
+	var pending []registryclient.SubjectVersion
+	for _, subject := range subjects {
+		versions, err := source.ListVersions(ctx, subject, false)
+		if registryclient.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to list the versions of subject %s: %w", subject, err)
+		}
+		target := exportDestinationSubject(spec, subject)
+		existing, err := destination.ListVersions(ctx, target, false)
+		if err != nil && !registryclient.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to list the versions of subject %s in the destination registry: %w", target, err)
+		}
+		for _, number := range versions {
+			if slices.Contains(existing, number) {
+				continue
+			}
+			version, err := source.GetVersion(ctx, subject, strconv.Itoa(int(number)), false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get version %d of subject %s: %w", number, subject, err)
+			}
+			version.Subject = target
+			for i := range version.References {
+				version.References[i].Subject = exportDestinationSubject(spec, version.References[i].Subject)
+			}
+			pending = append(pending, *version)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	return pending, nil
+}
+
+// copyExportVersions imports the pending versions into the destination with their original IDs
+// and version numbers, switching their subjects to IMPORT mode first. Subjects stay in IMPORT
+// mode while the resource exists, as they do with exporters. It returns the number of versions
+// copied and the subjects switched to IMPORT mode.
+func copyExportVersions(ctx context.Context, destination *registryclient.Client,
+	pending []registryclient.SubjectVersion, logger logr.Logger) (int, []string, error) {
+	importing := map[string]bool{}
+	var switched []string
+	for i, version := range pending {
+		if !importing[version.Subject] {
+			mode, err := destination.GetMode(ctx, version.Subject)
+			if err != nil && !registryclient.IsNotFound(err) {
+				return i, switched, err
+			}
+			if mode != registryclient.ModeImport {
+				if err := destination.SetMode(ctx, version.Subject, registryclient.ModeImport); err != nil {
+					return i, switched, fmt.Errorf("failed to switch subject %s of the destination registry to IMPORT mode: %w", version.Subject, err)
+				}
+				switched = append(switched, version.Subject)
+			}
+			importing[version.Subject] = true
+		}
+		if _, err := destination.ImportSchema(ctx, version.Subject, version); err != nil {
+			return i, switched, fmt.Errorf("failed to import version %d of subject %s (ID %d): %w",
+				version.Version, version.Subject, version.ID, err)
+		}
+		logger.Info("Copied schema", "Subject", version.Subject, "Version", version.Version, "ID", version.ID)
+	}
+	return len(pending), switched, nil
+}
+
+// removeExporter cleans up the export before the resource is deleted: the exporter of the
+// Exporter method is deleted from the source registry and the destination subjects the Copy
+// method switched to IMPORT mode get their mode deleted.
+func (r *StrimziSchemaExporterReconciler) removeExporter(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, logger logr.Logger) error {
+	if instance.Status.Method == strimziregistryoperatorv1alpha1.ExporterMethodExporter {
+		if err := r.deleteExporter(ctx, instance, logger); err != nil {
+			return err
+		}
+	}
+	if len(instance.Status.ImportSubjects) == 0 {
+		return nil
+	}
+	return r.deleteImportModes(ctx, instance, logger)
+}
+
+// deleteExporter deletes the exporter from the source registry. Nothing is done when the
+// registry itself is gone or when the namespace may not act on it.
+func (r *StrimziSchemaExporterReconciler) deleteExporter(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, logger logr.Logger) error {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	if err := r.Get(ctx, registryReference(instance.Spec.Registry, instance.Namespace), registry); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if registry.DeletionTimestamp != nil || registryWideAccess(registry, instance.Namespace) != nil {
+		return nil
+	}
+	rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, registry)
+	if err != nil {
+		return err
+	}
+	logger.Info("Deleting exporter", "Exporter", instance.Name)
+	if err := rc.PauseExporter(ctx, instance.Name); err != nil {
+		return ignoreRegistryNotFound(err)
+	}
+	return ignoreRegistryNotFound(rc.DeleteExporter(ctx, instance.Name))
+}
+
+// deleteImportModes deletes the mode of the destination subjects the Copy method switched to
+// IMPORT mode, which fall back to the global mode. Nothing is done when the destination
+// StrimziSchemaRegistry or its credentials are gone, when the registry is being deleted or when
+// the namespace may not act on it.
+func (r *StrimziSchemaExporterReconciler) deleteImportModes(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, logger logr.Logger) error {
+	cfg, registry, err := r.destinationConfig(ctx, instance)
+	if apierrors.IsNotFound(err) || errors.Is(err, errRegistryReadOnly) || tenancyReason(err) != "" {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if registry != nil && registry.DeletionTimestamp != nil {
+		return nil
+	}
+	destination, err := r.registryClient(cfg)
+	if err != nil {
+		return err
+	}
+	for _, subject := range instance.Status.ImportSubjects {
+		logger.Info("Deleting the IMPORT mode of the destination subject", "Subject", subject)
+		if err := destination.DeleteMode(ctx, subject); err != nil && !registryclient.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the mode of subject %s of the destination registry: %w", subject, err)
+		}
+	}
+	return nil
+}
+
+// ignoreRegistryNotFound returns nil on registry "not found" errors, err otherwise.
+func ignoreRegistryNotFound(err error) error {
+	if registryclient.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// exporterFailure reports a failed registry call. Requests rejected by a registry (e.g. IMPORT
+// mode on a destination subject created by producers) wait for a spec change or the next resync
+// instead of retrying with backoff.
+func exporterFailure(instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, err error) (ctrl.Result, error) {
+	if registryclient.IsClientError(err) {
+		setExporterReady(instance, metav1.ConditionFalse, "Rejected", err.Error())
+		return ctrl.Result{RequeueAfter: exporterResyncPeriod}, nil
+	}
+	setExporterReady(instance, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+	return ctrl.Result{}, err
+}
+
+// setExporterReady sets the Ready condition of a StrimziSchemaExporter.
+func setExporterReady(instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StrimziSchemaExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchemaExporter{},
+		registryRefIndex, registryRefKey); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&strimziregistryoperatorv1alpha1.StrimziSchemaExporter{}).
+		Watches(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{},
+			handler.EnqueueRequestsFromMapFunc(requestsForRegistryRef(r.Client, &strimziregistryoperatorv1alpha1.StrimziSchemaExporterList{}))).
+		Named("strimzischemaexporter").
+		Complete(r)
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestExporter(name, destinationURL string) *strimziregistryoperatorv1alpha1.StrimziSchemaExporter {
+	return &strimziregistryoperatorv1alpha1.StrimziSchemaExporter{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1},
+		Spec: strimziregistryoperatorv1alpha1.StrimziSchemaExporterSpec{
+			Registry: strimziregistryoperatorv1alpha1.RegistryReference{Name: "test-sr"},
+			Destination: strimziregistryoperatorv1alpha1.ExporterDestination{
+				URL:                   destinationURL,
+				CredentialsSecretName: "dr-credentials",
+			},
+		},
+	}
+}
+
+// newTestExporterReconciler returns a reconciler whose clients of the test-sr registry target
+// source, other URLs being called as they are.
+func newTestExporterReconciler(source *testutil.FakeRegistry, objs ...client.Object) *StrimziSchemaExporterReconciler {
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dr-credentials", Namespace: "default"},
+		Data:       map[string][]byte{"username": []byte("exporter"), "password": []byte("secret")},
+	}
+	toSource := fakeRegistryClientFactory(source.URL)
+	c := newRegistryResourceClient(&strimziregistryoperatorv1alpha1.StrimziSchemaExporter{}, append(objs, credentials)...)
+	return &StrimziSchemaExporterReconciler{
+		Client: c,
+		Scheme: c.Scheme(),
+		NewRegistryClient: func(cfg registryclient.Config) (*registryclient.Client, error) {
+			if cfg.URL == "http://test-sr.default.svc:80" {
+				return toSource(cfg)
+			}
+			return registryclient.New(cfg)
+		},
+		Now: func() time.Time { return testStart },
+	}
+}
+
+func reconcileExporter(t *testing.T, r *StrimziSchemaExporterReconciler,
+	name string) *strimziregistryoperatorv1alpha1.StrimziSchemaExporter {
+	t.Helper()
+	instance := &strimziregistryoperatorv1alpha1.StrimziSchemaExporter{}
+	reconcileRegistryResource(t, r, r.Client, name, instance)
+	return instance
+}
+
+func expectExporterLag(t *testing.T, instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter, lag int32) {
+	t.Helper()
+	if instance.Status.Lag == nil || *instance.Status.Lag != lag {
+		t.Errorf("expected a lag of %d, got %v", lag, instance.Status.Lag)
+	}
+}
+
+func TestStrimziSchemaExporterExporter(t *testing.T) {
+	source := testutil.NewFakeRegistry()
+	defer source.Close()
+	destination := testutil.NewFakeRegistry()
+	defer destination.Close()
+	seedRegistry(t, source, "orders-value", "payments-value")
+
+	r := newTestExporterReconciler(source, newReadyRegistry(), newTestExporter("dr", destination.URL))
+
+	t.Run("exporter is created", func(t *testing.T) {
+		instance := reconcileExporter(t, r, "dr")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionTrue, "Exporting")
+		exporter, ok := source.Exporter("dr")
+		if !ok {
+			t.Fatal("expected the exporter to be created")
+		}
+		if exporter.ContextType != "NONE" || len(exporter.Subjects) != 1 || exporter.Subjects[0] != "*" {
+			t.Errorf("unexpected exporter %+v", exporter)
+		}
+		if exporter.Config["schema.registry.url"] != destination.URL || exporter.Config["basic.auth.user.info"] != "exporter:secret" {
+			t.Errorf("unexpected exporter configuration %v", exporter.Config)
+		}
+		if instance.Status.Method != strimziregistryoperatorv1alpha1.ExporterMethodExporter || instance.Status.State != "RUNNING" {
+			t.Errorf("unexpected status %+v", instance.Status)
+		}
+		// The fake exporter does not copy anything.
+		expectExporterLag(t, instance, 2)
+		if instance.Status.LastSyncTime != nil {
+			t.Error("expected no sync time while versions are missing")
+		}
+	})
+
+	t.Run("changes are applied to the paused exporter", func(t *testing.T) {
+		updateRegistryResource(t, r.Client, "dr", func(instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter) {
+			instance.Spec.Subjects = []string{"orders-value"}
+		})
+		instance := reconcileExporter(t, r, "dr")
+		exporter, _ := source.Exporter("dr")
+		if len(exporter.Subjects) != 1 || exporter.Subjects[0] != "orders-value" || exporter.State != "RUNNING" {
+			t.Errorf("unexpected exporter %+v", exporter)
+		}
+		expectExporterLag(t, instance, 1)
+	})
+
+	t.Run("suspend pauses the exporter", func(t *testing.T) {
+		updateRegistryResource(t, r.Client, "dr", func(instance *strimziregistryoperatorv1alpha1.StrimziSchemaExporter) {
+			instance.Spec.Suspend = true
+		})
+		instance := reconcileExporter(t, r, "dr")
+		if exporter, _ := source.Exporter("dr"); exporter.State != "PAUSED" || instance.Status.State != "PAUSED" {
+			t.Errorf("expected the exporter to be paused, got %s", exporter.State)
+		}
+		expectReady(t, instance.Status.Conditions, metav1.ConditionTrue, "Suspended")
+	})
+
+	t.Run("the exporter is deleted with the resource", func(t *testing.T) {
+		instance := reconcileExporter(t, r, "dr")
+		if err := r.Delete(context.Background(), instance); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reconcileExporter(t, r, "dr")
+		if _, ok := source.Exporter("dr"); ok {
+			t.Error("expected the exporter to be deleted")
+		}
+	})
+}
+
+func TestStrimziSchemaExporterCopy(t *testing.T) {
+	source := testutil.NewFakeRegistry()
+	defer source.Close()
+	source.NoExporters = true
+	destination := testutil.NewFakeRegistry()
+	defer destination.Close()
+	seedRegistry(t, source, "orders-value", "payments-value")
+
+	dr := newTestExporter("dr", destination.URL)
+	linked := newTestExporter("linked", destination.URL)
+	linked.Spec.Subjects = []string{"orders-value"}
+	linked.Spec.ContextType, linked.Spec.Context = strimziregistryoperatorv1alpha1.ExporterContextCustom, "dc1"
+	linked.Spec.SubjectRenameFormat = "dc1.${subject}"
+	r := newTestExporterReconciler(source, newReadyRegistry(), dr, linked)
+
+	t.Run("missing versions are copied with their IDs", func(t *testing.T) {
+		instance := reconcileExporter(t, r, "dr")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionTrue, "Exporting")
+		if instance.Status.Method != strimziregistryoperatorv1alpha1.ExporterMethodCopy {
+			t.Errorf("expected the copy loop without exporter support, got %q", instance.Status.Method)
+		}
+		for _, subject := range []string{"orders-value", "payments-value"} {
+			copied, original := destination.Versions(subject), source.Versions(subject)
+			if len(copied) != 1 || copied[0].ID != original[0].ID || copied[0].Version != original[0].Version {
+				t.Errorf("expected %s to be copied as %+v, got %+v", subject, original, copied)
+			}
+			if mode, _ := destination.Mode(subject); mode != "IMPORT" {
+				t.Errorf("expected %s to be in IMPORT mode, got %q", subject, mode)
+			}
+		}
+		expectExporterLag(t, instance, 0)
+		if instance.Status.LastSyncTime == nil {
+			t.Error("expected the sync time to be set")
+		}
+	})
+
+	t.Run("new versions are copied", func(t *testing.T) {
+		rc, _ := registryclient.New(registryclient.Config{URL: source.URL})
+		if _, err := rc.RegisterSchema(context.Background(), "orders-value", registryclient.Schema{Schema: testAvroSchemaV2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		instance := reconcileExporter(t, r, "dr")
+		if versions := destination.Versions("orders-value"); len(versions) != 2 {
+			t.Errorf("expected 2 versions, got %+v", versions)
+		}
+		expectExporterLag(t, instance, 0)
+	})
+
+	t.Run("subjects are renamed and moved into the context", func(t *testing.T) {
+		instance := reconcileExporter(t, r, "linked")
+		expectExporterLag(t, instance, 0)
+		if versions := destination.Versions(":.dc1:dc1.orders-value"); len(versions) != 2 {
+			t.Errorf("expected 2 versions in context dc1, got %+v", versions)
+		}
+		if len(destination.Versions(":.dc1:dc1.payments-value")) != 0 {
+			t.Error("expected only the selected subjects to be copied")
+		}
+	})
+
+	t.Run("subjects written by producers are not overwritten", func(t *testing.T) {
+		seedRegistry(t, destination, "refunds-value")
+		seedRegistry(t, source, "refunds-value")
+		rc, _ := registryclient.New(registryclient.Config{URL: source.URL})
+		if _, err := rc.RegisterSchema(context.Background(), "refunds-value", registryclient.Schema{Schema: testAvroSchemaV2}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		instance := reconcileExporter(t, r, "dr")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "Rejected")
+		expectExporterLag(t, instance, 1)
+		if !slices.Equal(instance.Status.ImportSubjects, []string{"orders-value", "payments-value"}) {
+			t.Errorf("expected the subjects switched to IMPORT mode to be reported, got %v", instance.Status.ImportSubjects)
+		}
+	})
+
+	t.Run("the IMPORT mode of the copied subjects is deleted with the resource", func(t *testing.T) {
+		destination.SetMode("invoices-value", "IMPORT")
+		for _, name := range []string{"dr", "linked"} {
+			instance := reconcileExporter(t, r, name)
+			if err := r.Delete(context.Background(), instance); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			reconcileExporter(t, r, name)
+		}
+		for _, subject := range []string{"orders-value", "payments-value", ":.dc1:dc1.orders-value"} {
+			if mode, ok := destination.Mode(subject); ok {
+				t.Errorf("expected the mode of %s to be deleted, got %q", subject, mode)
+			}
+		}
+		if mode, _ := destination.Mode("invoices-value"); mode != "IMPORT" {
+			t.Errorf("expected the mode set by users to be kept, got %q", mode)
+		}
+	})
+}
+
+func TestStrimziSchemaExporterRegistryDestination(t *testing.T) {
+	source := testutil.NewFakeRegistry()
+	defer source.Close()
+
+	// The destination requires Basic authentication; the operator calls it as "operator".
+	destination := newBasicAuthInstance()
+	destination.Name = "dr-sr"
+	destination.Spec.Connection = &strimziregistryoperatorv1alpha1.ConnectionSpec{BasicAuthUser: "operator"}
+	users := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-users", Namespace: "default"},
+		Data:       map[string][]byte{"operator": []byte("operator-pw,admin")},
+	}
+	newRegistryExporter := func(name, credentials string) *strimziregistryoperatorv1alpha1.StrimziSchemaExporter {
+		exporter := newTestExporter(name, "")
+		exporter.Spec.Method = strimziregistryoperatorv1alpha1.ExporterMethodExporter
+		exporter.Spec.Destination = strimziregistryoperatorv1alpha1.ExporterDestination{
+			Registry:              &strimziregistryoperatorv1alpha1.RegistryReference{Name: "dr-sr"},
+			CredentialsSecretName: credentials,
+		}
+		return exporter
+	}
+	r := newTestExporterReconciler(source, newReadyRegistry(), destination, users,
+		newRegistryExporter("anonymous", ""), newRegistryExporter("dr", "dr-credentials"))
+
+	t.Run("credentials are required by an authenticating destination", func(t *testing.T) {
+		instance := reconcileExporter(t, r, "anonymous")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "CredentialsRequired")
+		if _, ok := source.Exporter("anonymous"); ok {
+			t.Error("expected no exporter")
+		}
+	})
+
+	t.Run("the exporter gets the credentials of its secret only", func(t *testing.T) {
+		reconcileExporter(t, r, "dr")
+		exporter, ok := source.Exporter("dr")
+		if !ok {
+			t.Fatal("expected the exporter to be created")
+		}
+		if exporter.Config["schema.registry.url"] != "http://dr-sr.default.svc:80" ||
+			exporter.Config["basic.auth.user.info"] != "exporter:secret" {
+			t.Errorf("unexpected exporter configuration %v", exporter.Config)
+		}
+	})
+
+	t.Run("destinations of another namespace are rejected", func(t *testing.T) {
+		exporter := newRegistryExporter("remote", "dr-credentials")
+		shared := sharedRegistryReference
+		exporter.Spec.Destination.Registry = &shared
+		r := newTestExporterReconciler(source, newReadyRegistry(), newSharedRegistry(), exporter)
+		instance := reconcileExporter(t, r, "remote")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "NamespaceNotAllowed")
+	})
+}
+
+func TestStrimziSchemaExporterTenancy(t *testing.T) {
+	source := testutil.NewFakeRegistry()
+	defer source.Close()
+
+	exporter := newTestExporter("dr", "http://dr.example.com")
+	exporter.Spec.Registry = sharedRegistryReference
+	r := newTestExporterReconciler(source, newSharedRegistry(), exporter)
+	instance := reconcileExporter(t, r, "dr")
+	expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "NamespaceNotAllowed")
+	if _, ok := source.Exporter("dr"); ok {
+		t.Error("expected no exporter")
+	}
+}
@@ -19,14 +19,19 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
 	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
+	kafka "github.com/scholzj/strimzi-go/pkg/apis/kafka.strimzi.io/v1"
 	apps "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -39,12 +44,22 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 // StrimziSchemaRegistryReconciler reconciles a StrimziSchemaRegistry object
 type StrimziSchemaRegistryReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// AvailableAPIs lists the optional APIs (Ingress, Gateway API, OpenShift Route)
+	// served by the cluster, as detected at startup.
+	AvailableAPIs AvailableAPIs
+	// NewRegistryClient builds the REST API client. Defaults to registryclient.New.
+	NewRegistryClient RegistryClientFactory
+	// JobImage is the operator image run by the CronJob exporting schemas to a volume.
+	JobImage string
+	// Versions maps spec.version to images. Defaults to DefaultVersionTable.
+	Versions VersionTable
 }
 
 const finalizer = "metrics.strimziregistryoperator.randsw.code/finalizer"
@@ -75,8 +90,19 @@ func getStrimziClusterName(instance *strimziregistryoperatorv1alpha1.StrimziSche
 // +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaregistries/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaregistries/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaregistrybackups,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkaconnects;kafkabridges,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;tlsroutes;backendtlspolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -113,7 +139,11 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 	isApplicationMarkedToBeDeleted := instance.GetDeletionTimestamp() != nil
 	if isApplicationMarkedToBeDeleted {
 		if controllerutil.ContainsFinalizer(instance, finalizer) {
-			r.finalizeApplication()
+			if err := r.finalizeApplication(ctx, instance, logger); err != nil {
+				logger.Error(err, "Failed to finalize StrimziSchemaRegistry")
+				monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+				return ctrl.Result{}, err
+			}
 			controllerutil.RemoveFinalizer(instance, finalizer)
 			err := r.Update(ctx, instance)
 			if err != nil {
@@ -141,6 +171,36 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		return result, nil
 	}
 
+	// Keep the REST API client truststore in line with the client CA (spec.tls.clientAuth)
+	if _, err = r.reconcileClientTruststore(ctx, instance, logger, strimziClusterName); err != nil {
+		logger.Error(err, "Failed to reconcile REST API client truststore")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+	// Render the JAAS config and password file from the users secret (spec.authentication.basic)
+	if _, err = r.reconcileBasicAuthSecret(ctx, instance, logger); err != nil {
+		logger.Error(err, "Failed to reconcile REST API Basic authentication secret")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+
+	// Resolve spec.version and move version upgrades through their checks and rollout
+	upgradeRequeue, deploy, err := r.reconcileUpgrade(ctx, instance, logger)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile version upgrade")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+	if !deploy {
+		setRegistryCondition(instance, "Ready", metav1.ConditionFalse, "UnsupportedVersion", instance.Status.Upgrade.Message)
+		if err = r.Status().Update(ctx, instance); err != nil {
+			logger.Error(err, "Failed to update CR Status")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Check if the Deployment already exists, if not create a new one
 	found := &apps.Deployment{}
 	err = r.Get(ctx, types.NamespacedName{Name: instance.Name + deploySuffix, Namespace: instance.Namespace}, found)
@@ -152,8 +212,16 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
 			return ctrl.Result{}, err
 		}
-		// Increment instance count
-		monitoring.StrimziSchemaRegistryCurrentInstanceCount.Inc()
+		recreating, err := r.recreatingDeployment(ctx, instance)
+		if err != nil {
+			logger.Error(err, "Failed to list orphaned ReplicaSets")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+		// Increment instance count, unless the registry was running before
+		if !recreating {
+			monitoring.StrimziSchemaRegistryCurrentInstanceCount.Inc()
+		}
 		err = r.Create(ctx, deployment)
 		if err != nil {
 			logger.Error(err, "Failed to create new Deployment", "Deployment.Namespace", deployment.Namespace, "Deployment.Name", deployment.Name)
@@ -169,6 +237,11 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 
+	// Wait for a Deployment recreated by migrateDeploymentSelector to be gone
+	if found.DeletionTimestamp != nil {
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	}
+
 	// Deployment exists — reconcile spec changes
 	updatedDep, specChanged, err := r.updateExistingDeployment(instance, ctx, logger, found)
 	if err != nil {
@@ -177,6 +250,17 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 	if specChanged {
+		// Deployments selecting on the version label are recreated with the current selector
+		// instead of updated
+		deleting, err := r.migrateDeploymentSelector(ctx, found, logger)
+		if err != nil {
+			logger.Error(err, "Failed to recreate deployment with the current selector")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+		if deleting {
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
 		err = r.Update(ctx, updatedDep)
 		if err != nil {
 			logger.Error(err, "Failed to update deployment after spec change")
@@ -187,6 +271,33 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		logger.Info("Deployment updated after spec change", "Deployment.Name", instance.Name+deploySuffix, "Deployment.Namespace", instance.Namespace)
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
+	// Keep the PodDisruptionBudget in line with spec.availability
+	if err = r.reconcilePodDisruptionBudget(ctx, instance, logger); err != nil {
+		logger.Error(err, "Failed to reconcile PodDisruptionBudget")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+	// Keep the HorizontalPodAutoscaler in line with spec.autoscaling
+	hpa, err := r.reconcileHorizontalPodAutoscaler(ctx, instance, logger)
+	if err != nil {
+		logger.Error(err, "Failed to reconcile HorizontalPodAutoscaler")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+	instance.Status.Autoscaling = autoscalingStatus(hpa)
+	instance.Status.Listeners = listenerStatuses(instance)
+	// Expose the REST API outside the cluster as requested in spec.expose
+	if err = r.reconcileExpose(ctx, instance, logger, strimziClusterName); err != nil {
+		logger.Error(err, "Failed to reconcile exposed resources")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+	// Publish the connection ConfigMap and Secret for application teams
+	if err = r.reconcileConnection(ctx, instance, logger, strimziClusterName); err != nil {
+		logger.Error(err, "Failed to reconcile connection ConfigMap and Secret")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
 	conditionType := "Ready"
 	if found.Status.ReadyReplicas == found.Status.Replicas {
 		meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
@@ -203,6 +314,15 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 			Message: fmt.Sprintf("Schema Registry deployment is no ready: %d/%d replicas ready", found.Status.ReadyReplicas, found.Status.Replicas),
 		})
 	}
+	// Check the REST API and apply the settings managed through it once the registry is ready
+	result = ctrl.Result{}
+	if meta.IsStatusConditionTrue(instance.Status.Conditions, conditionType) {
+		r.reconcileRegistryAPI(ctx, instance, logger)
+		result.RequeueAfter = registryAPIResyncPeriod
+	}
+	if upgradeRequeue > 0 && (result.RequeueAfter == 0 || upgradeRequeue < result.RequeueAfter) {
+		result.RequeueAfter = upgradeRequeue
+	}
 	err = r.Status().Update(ctx, instance)
 	if err != nil {
 		logger.Error(err, "Failed to update CR Status")
@@ -213,12 +333,14 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 	foundSvc := &v1.Service{}
 	err = r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, foundSvc)
 	if err != nil && errors.IsNotFound(err) {
+		logger.Info("Creating a new Service", "Service.Namespace", instance.Namespace, "Service.Name", instance.Name)
 		svc, err := r.createService(instance, logger)
 		if err != nil {
 			logger.Error(err, "Failed to create service")
 			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
 			return ctrl.Result{}, err
 		}
+		trackManagedMetadata(svc)
 		err = r.Create(ctx, svc)
 		if err != nil {
 			logger.Error(err, "Failed to create new Service", "Service.Namespace", svc.Namespace, "Service.Name", svc.Name)
@@ -234,7 +356,7 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 
-	// Service exists — reconcile spec changes (e.g., SecureHTTP changed)
+	// Service exists — correct drift of type, ports, metadata and source ranges
 	desiredSvc, err := r.createService(instance, logger)
 	if err != nil {
 		logger.Error(err, "Failed to create desired service spec")
@@ -242,30 +364,9 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 
-	// Compare service ports to detect changes
-	serviceChanged := false
-	if len(foundSvc.Spec.Ports) != len(desiredSvc.Spec.Ports) {
-		serviceChanged = true
-	} else {
-		for i, desiredPort := range desiredSvc.Spec.Ports {
-			existingPort := foundSvc.Spec.Ports[i]
-			if existingPort.Port != desiredPort.Port ||
-				existingPort.TargetPort.IntVal != desiredPort.TargetPort.IntVal ||
-				existingPort.Name != desiredPort.Name {
-				serviceChanged = true
-				break
-			}
-		}
-	}
-
-	if serviceChanged {
-		logger.Info("Service ports changed, updating service",
+	if updateExistingService(foundSvc, desiredSvc) {
+		logger.Info("Service drifted from spec, updating service",
 			"Service.Name", instance.Name, "Service.Namespace", instance.Namespace)
-		foundSvc.Spec.Ports = desiredSvc.Spec.Ports
-		if foundSvc.Annotations == nil {
-			foundSvc.Annotations = make(map[string]string)
-		}
-		foundSvc.Annotations["type"] = desiredSvc.Spec.Ports[0].Name
 		err = r.Update(ctx, foundSvc)
 		if err != nil {
 			logger.Error(err, "Failed to update service after spec change")
@@ -276,7 +377,7 @@ func (r *StrimziSchemaRegistryReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{RequeueAfter: time.Second}, nil
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 // handleSecretRotation detects changes to the user secret or cluster CA secret,
@@ -327,6 +428,12 @@ func (r *StrimziSchemaRegistryReconciler) handleSecretRotation(
 		clusterCASecretChanged = true
 	}
 
+	// A flavor reading another store format needs both stores rendered again.
+	if secretStoreFormat(curr_secret) != flavorFor(instance).storeFormat() {
+		logger.Info("Store format of the flavor changed", "Format", flavorFor(instance).storeFormat())
+		userSecretChanged, clusterCASecretChanged = true, true
+	}
+
 	if userSecretChanged || clusterCASecretChanged {
 		var newSecret *v1.Secret
 		var newSecretCreated bool
@@ -389,23 +496,25 @@ func (r *StrimziSchemaRegistryReconciler) handleSecretRotation(
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *StrimziSchemaRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{},
+		referencedSecretIndex, referencedSecrets); err != nil {
+		return err
+	}
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}).
 		Watches(
 			&v1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
-				// Fast path: all secrets we care about (user secrets, cluster CA cert
-				// secrets) carry the strimzi.io/cluster label. If the secret lacks this
-				// label, it cannot be relevant — return immediately without listing CRs.
+				// Fast path: all Strimzi secrets we care about (user secrets, cluster and
+				// clients CA cert secrets) carry the strimzi.io/cluster label. A secret
+				// without it can only be one referenced in the spec (client CA, Basic
+				// authentication users), looked up through the field index instead of
+				// listing every CR in the namespace.
 				// This avoids O(N²) fan-out where every Secret change triggered a List
 				// of every StrimziSchemaRegistry CR in the namespace.
-				secretLabels := obj.GetLabels()
-				if secretLabels == nil {
-					return nil
-				}
-				secretClusterName := secretLabels[strimziClusterLabel]
+				secretClusterName := obj.GetLabels()[strimziClusterLabel]
 				if secretClusterName == "" {
-					return nil
+					return r.requestsForReferencedSecret(ctx, obj)
 				}
 
 				// List only CRs that belong to the same Kafka cluster, using label
@@ -432,8 +541,11 @@ func (r *StrimziSchemaRegistryReconciler) SetupWithManager(mgr ctrl.Manager) err
 						})
 						continue
 					}
-					// Cluster CA cert secret: name ends with -cluster-ca-cert.
-					if strings.HasSuffix(obj.GetName(), clusterCASuffix) {
+					// Cluster CA cert secret: name ends with -cluster-ca-cert. The clients
+					// CA cert secret (-clients-ca-cert) signs REST API client certificates.
+					if strings.HasSuffix(obj.GetName(), clusterCASuffix) ||
+						(clientAuthEnabled(&item) && strings.HasSuffix(obj.GetName(), clientsCASuffix)) ||
+						slices.Contains(referencedSecrets(&item), obj.GetName()) {
 						requests = append(requests, reconcile.Request{
 							NamespacedName: types.NamespacedName{
 								Name:      item.GetName(),
@@ -445,8 +557,36 @@ func (r *StrimziSchemaRegistryReconciler) SetupWithManager(mgr ctrl.Manager) err
 				return requests
 			}),
 		).
+		Watches(&v1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace)).
 		Owns(&apps.Deployment{}).
-		Complete(r)
+		Owns(&v1.Service{}).
+		Owns(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}).
+		Owns(&v1.ConfigMap{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{})
+	// Optional APIs are only watched when the cluster serves them.
+	if r.AvailableAPIs.Ingress {
+		builder = builder.Owns(&networkingv1.Ingress{})
+	}
+	if r.AvailableAPIs.HTTPRoute {
+		builder = builder.Owns(&gatewayv1.HTTPRoute{})
+	}
+	if r.AvailableAPIs.TLSRoute {
+		builder = builder.Owns(&gatewayv1.TLSRoute{})
+	}
+	if r.AvailableAPIs.BackendTLSPolicy {
+		builder = builder.Owns(&gatewayv1.BackendTLSPolicy{})
+	}
+	if r.AvailableAPIs.Route {
+		builder = builder.Owns(newRoute())
+	}
+	if r.AvailableAPIs.KafkaConnect {
+		builder = builder.Watches(&kafka.KafkaConnect{}, handler.EnqueueRequestsFromMapFunc(r.requestsForKafkaClient))
+	}
+	if r.AvailableAPIs.KafkaBridge {
+		builder = builder.Watches(&kafka.KafkaBridge{}, handler.EnqueueRequestsFromMapFunc(r.requestsForKafkaClient))
+	}
+	return builder.Complete(r)
 }
 
 // renewTLSSecret creates and applies a new TLS secret for Schema Registry REST API.
@@ -484,6 +624,13 @@ func (r *StrimziSchemaRegistryReconciler) renewTLSSecret(instance *strimziregist
 	return nil
 }
 
-func (reconciler *StrimziSchemaRegistryReconciler) finalizeApplication() {
+// finalizeApplication cleans up what owner references cannot: connection copies in other
+// namespaces. It also updates the instance count metric.
+func (r *StrimziSchemaRegistryReconciler) finalizeApplication(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) error {
+	if err := r.deleteConnectionCopies(ctx, instance, logger, nil); err != nil {
+		return err
+	}
 	monitoring.StrimziSchemaRegistryCurrentInstanceCount.Dec()
+	return nil
 }
@@ -1381,7 +1381,7 @@ var _ = Describe("StrimziSchemaRegistry Controller", func() {
 			_ = os.Unsetenv("STRIMZIREGISTRYOPERATOR_IMAGE")
 		})
 
-		It("should update the deployment when CompatibilityLevel changes", func() {
+		It("should not roll the deployment when CompatibilityLevel changes", func() {
 			controllerReconciler := &StrimziSchemaRegistryReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
 
 			By("First reconcile to create the deployment")
@@ -1405,32 +1405,19 @@ var _ = Describe("StrimziSchemaRegistry Controller", func() {
 			instance.Spec.CompatibilityLevel = "backward"
 			Expect(k8sClient.Update(ctx, instance)).To(Succeed())
 
-			By("Reconciling again to trigger deployment update")
+			By("Reconciling again")
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
 
-			By("Checking that deployment hash annotation changed")
-			Eventually(func() string {
+			By("Checking that deployment hash annotation is unchanged")
+			Consistently(func() string {
 				updated := &appsv1.Deployment{}
 				err := k8sClient.Get(ctx, types.NamespacedName{Name: SchemaRegistryName + "-deploy", Namespace: SchemaRegistryName}, updated)
 				if err != nil {
 					return ""
 				}
 				return updated.Annotations[keyPrefix+"/specHash"]
-			}, time.Minute, time.Second).ShouldNot(Equal(initialHash))
-
-			By("Verifying the env var was updated to 'backward'")
-			updatedDeployment := &appsv1.Deployment{}
-			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: SchemaRegistryName + "-deploy", Namespace: SchemaRegistryName}, updatedDeployment)).To(Succeed())
-			envVars := updatedDeployment.Spec.Template.Spec.Containers[0].Env
-			compatLevel := ""
-			for _, env := range envVars {
-				if env.Name == "SCHEMA_REGISTRY_SCHEMA_COMPATIBILITY_LEVEL" {
-					compatLevel = env.Value
-					break
-				}
-			}
-			Expect(compatLevel).To(Equal("backward"), "CompatibilityLevel should be updated to 'backward'")
+			}, 5*time.Second, time.Second).Should(Equal(initialHash))
 		})
 	})
 
@@ -1748,6 +1735,34 @@ var _ = Describe("StrimziSchemaRegistry Controller", func() {
 			Expect(updatedSvc.Spec.Ports[0].Name).To(Equal("http"))
 			Expect(updatedSvc.Spec.Ports[0].TargetPort.IntVal).To(Equal(int32(8081)))
 		})
+
+		It("should restore the service when it is modified", func() {
+			controllerReconciler := &StrimziSchemaRegistryReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			By("First two reconciles to create deployment and service")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("Modifying the service ports")
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+			owner := metav1.GetControllerOf(svc)
+			Expect(owner).NotTo(BeNil())
+			Expect(owner.Kind).To(Equal("StrimziSchemaRegistry"))
+			svc.Spec.Ports[0].Port = 8443
+			Expect(k8sClient.Update(ctx, svc)).To(Succeed())
+
+			By("Reconciling as the Service watch does")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			restored := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, restored)).To(Succeed())
+			Expect(restored.Spec.Ports).To(HaveLen(1))
+			Expect(restored.Spec.Ports[0].Port).To(Equal(int32(443)))
+		})
 	})
 
 	// T1: renewTLSSecret integration test — verifies TLS secret is created/updated
@@ -2060,10 +2075,10 @@ var _ = Describe("StrimziSchemaRegistry Controller", func() {
 			tlsBefore := &corev1.Secret{}
 			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: SchemaRegistryName + "-tls", Namespace: SchemaRegistryName}, tlsBefore)).To(Succeed())
 
-			By("Changing CompatibilityLevel to trigger deployment update")
+			By("Changing HeapOpts to trigger deployment update")
 			instance := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
 			Expect(k8sClient.Get(ctx, typeNamespacedName, instance)).To(Succeed())
-			instance.Spec.CompatibilityLevel = "backward"
+			instance.Spec.HeapOpts = "-Xms1G -Xmx1G"
 			Expect(k8sClient.Update(ctx, instance)).To(Succeed())
 
 			By("Reconciling after spec change")
@@ -2086,14 +2101,14 @@ var _ = Describe("StrimziSchemaRegistry Controller", func() {
 			deployment := &appsv1.Deployment{}
 			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: SchemaRegistryName + "-deploy", Namespace: SchemaRegistryName}, deployment)).To(Succeed())
 			envVars := deployment.Spec.Template.Spec.Containers[0].Env
-			compatLevel := ""
+			heapOpts := ""
 			for _, env := range envVars {
-				if env.Name == "SCHEMA_REGISTRY_SCHEMA_COMPATIBILITY_LEVEL" {
-					compatLevel = env.Value
+				if env.Name == "SCHEMA_REGISTRY_HEAP_OPTS" {
+					heapOpts = env.Value
 					break
 				}
 			}
-			Expect(compatLevel).To(Equal("backward"), "Deployment should reflect updated CompatibilityLevel")
+			Expect(heapOpts).To(Equal("-Xms1G -Xmx1G"), "Deployment should reflect updated HeapOpts")
 		})
 	})
 })
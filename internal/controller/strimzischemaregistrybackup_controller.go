@@ -0,0 +1,349 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultKeepLast is the number of snapshots kept when spec.keepLast is not set.
+const defaultKeepLast = 7
+
+// errJobImageNotSet rejects volume backups when the operator does not know its own image.
+var errJobImageNotSet = errors.New("the operator image is not known: set --job-image or OPERATOR_IMAGE")
+
+// StrimziSchemaRegistryBackupReconciler takes snapshots of a StrimziSchemaRegistry through its
+// REST API, on a schedule or once per generation, and restores them.
+type StrimziSchemaRegistryBackupReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// NewRegistryClient builds the REST API client. Defaults to registryclient.New.
+	NewRegistryClient RegistryClientFactory
+	// JobImage is the operator image run by the Jobs reading and writing snapshots on volumes.
+	JobImage string
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaregistrybackups,verbs=get;list;watch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemaregistrybackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+
+// Reconcile takes a snapshot when one is due, or restores the snapshot selected by spec.restore,
+// and follows the Jobs of volume backups.
+func (r *StrimziSchemaRegistryBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	instance := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get StrimziSchemaRegistryBackup")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+	if instance.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	result, err := r.reconcileBackup(ctx, instance, logger)
+	if statusErr := r.Status().Update(ctx, instance); statusErr != nil {
+		logger.Error(statusErr, "Failed to update StrimziSchemaRegistryBackup status")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil {
+		logger.Error(err, "Failed to back up or restore the registry")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+	}
+	return result, err
+}
+
+func (r *StrimziSchemaRegistryBackupReconciler) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+// reconcileBackup runs the backup or restore when it is due and updates the status in memory.
+// Unscheduled backups and restores run once per generation; scheduled backups run at every tick
+// of spec.schedule, a missed tick being caught up once.
+func (r *StrimziSchemaRegistryBackupReconciler) reconcileBackup(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, logger logr.Logger) (ctrl.Result, error) {
+	spec, status := instance.Spec, &instance.Status
+	if status.ActiveJob != "" {
+		finished, err := r.trackBackupJob(ctx, instance, logger)
+		if err != nil || !finished {
+			return ctrl.Result{RequeueAfter: backupJobPollPeriod}, err
+		}
+	}
+
+	now := r.now()
+	due := status.ObservedGeneration != instance.Generation
+	var next time.Time
+	status.NextScheduleTime = nil
+	if spec.Restore == nil && spec.Schedule != "" {
+		schedule, err := cron.ParseStandard(spec.Schedule)
+		if err != nil {
+			status.ObservedGeneration = instance.Generation
+			setBackupReady(instance, metav1.ConditionFalse, "InvalidSchedule", err.Error())
+			return ctrl.Result{}, nil
+		}
+		last := instance.CreationTimestamp.Time
+		if status.LastScheduleTime != nil {
+			last = status.LastScheduleTime.Time
+		}
+		due = !spec.Suspend && !schedule.Next(last).After(now)
+		if !spec.Suspend {
+			next = schedule.Next(now)
+			status.NextScheduleTime = &metav1.Time{Time: next}
+		}
+	}
+	if !due {
+		return requeueAt(now, next), nil
+	}
+
+	cfg, result, err := r.backupRegistryConfig(ctx, instance)
+	if cfg == nil {
+		return result, err
+	}
+	if spec.Restore == nil && spec.Schedule != "" {
+		status.LastScheduleTime = &metav1.Time{Time: now}
+	}
+	switch {
+	case spec.Storage.PersistentVolumeClaim != nil:
+		err = r.startBackupJob(ctx, instance, *cfg, now, logger)
+	case spec.Restore != nil:
+		err = r.restoreSnapshot(ctx, instance, *cfg, logger)
+	default:
+		err = r.takeSnapshot(ctx, instance, *cfg, now, logger)
+	}
+	if err != nil {
+		return r.backupFailure(instance, err)
+	}
+	status.ObservedGeneration = instance.Generation
+	if status.ActiveJob != "" {
+		return ctrl.Result{RequeueAfter: backupJobPollPeriod}, nil
+	}
+	return requeueAt(now, next), nil
+}
+
+// requeueAt requeues at next, or never when next is zero.
+func requeueAt(now, next time.Time) ctrl.Result {
+	if next.IsZero() {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: max(next.Sub(now), time.Second)}
+}
+
+// backupRegistryConfig returns how to reach the registry of instance. It returns nil without
+// error when the registry cannot be called yet, or must not be by instance, after setting the
+// Ready condition.
+func (r *StrimziSchemaRegistryBackupReconciler) backupRegistryConfig(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) (*registryclient.Config, ctrl.Result, error) {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	registryKey := registryReference(instance.Spec.Registry, instance.Namespace)
+	if err := r.Get(ctx, registryKey, registry); err != nil {
+		if apierrors.IsNotFound(err) {
+			setBackupReady(instance, metav1.ConditionFalse, "RegistryNotFound", fmt.Sprintf("StrimziSchemaRegistry %s not found", registryKey))
+			return nil, ctrl.Result{}, nil
+		}
+		return nil, ctrl.Result{}, err
+	}
+	if !meta.IsStatusConditionTrue(registry.Status.Conditions, "Ready") {
+		setBackupReady(instance, metav1.ConditionFalse, "RegistryNotReady", fmt.Sprintf("StrimziSchemaRegistry %s is not ready", registryKey))
+		return nil, ctrl.Result{RequeueAfter: registryNotReadyRequeue}, nil
+	}
+	if err := registryWideAccess(registry, instance.Namespace); err != nil {
+		setBackupReady(instance, metav1.ConditionFalse, tenancyReason(err), err.Error())
+		return nil, ctrl.Result{}, nil
+	}
+	if instance.Spec.Restore != nil {
+		if err := registryWritable(registry); err != nil {
+			setBackupReady(instance, metav1.ConditionFalse, "ReadOnlyRegistry", err.Error())
+			return nil, ctrl.Result{}, nil
+		}
+	}
+	cfg, err := registryClientConfig(ctx, r.Client, registry)
+	if err != nil {
+		setBackupReady(instance, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+		return nil, ctrl.Result{}, err
+	}
+	return &cfg, ctrl.Result{}, nil
+}
+
+// registryClient builds the REST API client of cfg.
+func (r *StrimziSchemaRegistryBackupReconciler) registryClient(cfg registryclient.Config) (*registryclient.Client, error) {
+	if r.NewRegistryClient != nil {
+		return r.NewRegistryClient(cfg)
+	}
+	return registryclient.New(cfg)
+}
+
+// backupStore returns the store of the ConfigMap and object store storages.
+func (r *StrimziSchemaRegistryBackupReconciler) backupStore(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) (backup.Store, error) {
+	storage := instance.Spec.Storage
+	if objectStore := storage.ObjectStore; objectStore != nil {
+		credentials := &v1.Secret{}
+		key := types.NamespacedName{Name: objectStore.CredentialsSecretName, Namespace: instance.Namespace}
+		if err := r.Get(ctx, key, credentials); err != nil {
+			return nil, fmt.Errorf("failed to get object store credentials %s: %w", key.Name, err)
+		}
+		return &backup.ObjectStore{
+			Endpoint:        objectStore.Endpoint,
+			Bucket:          objectStore.Bucket,
+			Prefix:          objectStore.Prefix,
+			Region:          objectStore.Region,
+			AccessKeyID:     string(credentials.Data["AWS_ACCESS_KEY_ID"]),
+			SecretAccessKey: string(credentials.Data["AWS_SECRET_ACCESS_KEY"]),
+		}, nil
+	}
+	return &backup.ConfigMapStore{Client: r.Client, Namespace: instance.Namespace, Backup: instance.Name}, nil
+}
+
+// keepLast returns the number of snapshots to keep.
+func keepLast(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) int {
+	if instance.Spec.KeepLast > 0 {
+		return int(instance.Spec.KeepLast)
+	}
+	return defaultKeepLast
+}
+
+// takeSnapshot exports the registry into the ConfigMap or object store storage.
+func (r *StrimziSchemaRegistryBackupReconciler) takeSnapshot(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, cfg registryclient.Config, now time.Time,
+	logger logr.Logger) error {
+	rc, err := r.registryClient(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := r.backupStore(ctx, instance)
+	if err != nil {
+		return err
+	}
+	name := backup.SnapshotName(now)
+	snapshot, err := backup.TakeSnapshot(ctx, rc, store, name, keepLast(instance))
+	if err != nil {
+		return err
+	}
+	logger.Info("Took registry snapshot", "Snapshot", name, "Subjects", len(snapshot.Subjects))
+	status := &instance.Status
+	status.LastSnapshot = name
+	status.LastBackupTime = &metav1.Time{Time: now}
+	status.Subjects = int32(len(snapshot.Subjects))
+	status.Schemas = int32(snapshot.SchemaCount())
+	setBackupReady(instance, metav1.ConditionTrue, "SnapshotTaken",
+		fmt.Sprintf("snapshot %s holds %d subjects and %d schemas", name, status.Subjects, status.Schemas))
+	return nil
+}
+
+// restoreSnapshot restores the snapshot selected by spec.restore from the ConfigMap or object
+// store storage.
+func (r *StrimziSchemaRegistryBackupReconciler) restoreSnapshot(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, cfg registryclient.Config, logger logr.Logger) error {
+	rc, err := r.registryClient(cfg)
+	if err != nil {
+		return err
+	}
+	store, err := r.backupStore(ctx, instance)
+	if err != nil {
+		return err
+	}
+	name, err := backup.RestoreSnapshot(ctx, rc, store, instance.Spec.Restore.Snapshot)
+	if err != nil {
+		return err
+	}
+	logger.Info("Restored registry snapshot", "Snapshot", name)
+	now := metav1.NewTime(r.now())
+	instance.Status.RestoredSnapshot = name
+	instance.Status.RestoreTime = &now
+	setBackupReady(instance, metav1.ConditionTrue, "Restored", fmt.Sprintf("snapshot %s restored", name))
+	return nil
+}
+
+// backupFailure reports a failed backup or restore. Failures that need a spec or registry
+// change (missing snapshot, request rejected by the registry, unknown Job image) are not
+// retried until the next generation or tick; the others are retried with backoff.
+func (r *StrimziSchemaRegistryBackupReconciler) backupFailure(
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, err error) (ctrl.Result, error) {
+	reason := ""
+	switch {
+	case errors.Is(err, backup.ErrNotFound):
+		reason = "SnapshotNotFound"
+	case errors.Is(err, errJobImageNotSet):
+		reason = "JobImageNotSet"
+	case registryclient.IsClientError(err):
+		reason = "Rejected"
+	default:
+		setBackupReady(instance, metav1.ConditionFalse, "Failed", err.Error())
+		return ctrl.Result{}, err
+	}
+	instance.Status.ObservedGeneration = instance.Generation
+	setBackupReady(instance, metav1.ConditionFalse, reason, err.Error())
+	return ctrl.Result{}, nil
+}
+
+// setBackupReady sets the Ready condition of a StrimziSchemaRegistryBackup.
+func setBackupReady(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StrimziSchemaRegistryBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{},
+		registryRefIndex, registryRefKey); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}).
+		Owns(&batchv1.Job{}).
+		Watches(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{},
+			handler.EnqueueRequestsFromMapFunc(requestsForRegistryRef(r.Client, &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackupList{}))).
+		Named("strimzischemaregistrybackup").
+		Complete(r)
+}
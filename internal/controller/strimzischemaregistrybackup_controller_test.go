@@ -0,0 +1,298 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/backup"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestBackup(name string) *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup {
+	return &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1,
+			CreationTimestamp: metav1.NewTime(testStart)},
+		Spec: strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackupSpec{
+			Registry: strimziregistryoperatorv1alpha1.RegistryReference{Name: "test-sr"},
+			Storage: strimziregistryoperatorv1alpha1.BackupStorage{
+				ConfigMap: &strimziregistryoperatorv1alpha1.ConfigMapBackupStorage{},
+			},
+		},
+	}
+}
+
+// newTestBackupReconciler returns a reconciler whose registry clients target the fake registry.
+func newTestBackupReconciler(registry *testutil.FakeRegistry, clock *testClock,
+	objs ...client.Object) *StrimziSchemaRegistryBackupReconciler {
+	c := newRegistryResourceClient(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}, objs...)
+	return &StrimziSchemaRegistryBackupReconciler{Client: c, Scheme: c.Scheme(),
+		NewRegistryClient: fakeRegistryClientFactory(registry.URL), Now: clock.Now}
+}
+
+func reconcileBackup(t *testing.T, r *StrimziSchemaRegistryBackupReconciler,
+	name string) (*strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup, ctrl.Result) {
+	t.Helper()
+	instance := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}
+	result := reconcileRegistryResource(t, r, r.Client, name, instance)
+	return instance, result
+}
+
+func listSnapshots(t *testing.T, r *StrimziSchemaRegistryBackupReconciler, name string) []string {
+	t.Helper()
+	names, err := (&backup.ConfigMapStore{Client: r.Client, Namespace: "default", Backup: name}).List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return names
+}
+
+func TestStrimziSchemaRegistryBackupReconcile(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	seedRegistry(t, registry, "orders-value", "customers-value")
+	clock := &testClock{now: testStart}
+	r := newTestBackupReconciler(registry, clock, newReadyRegistry(), newTestBackup("once"))
+
+	t.Run("snapshot is taken once per generation", func(t *testing.T) {
+		instance, result := reconcileBackup(t, r, "once")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionTrue, "SnapshotTaken")
+		if instance.Status.LastSnapshot != "20261019-003000" || instance.Status.Subjects != 2 || instance.Status.Schemas != 2 {
+			t.Errorf("unexpected status %+v", instance.Status)
+		}
+		if result.RequeueAfter != 0 {
+			t.Errorf("an unscheduled backup must not requeue, got %v", result.RequeueAfter)
+		}
+
+		clock.now = clock.now.Add(time.Hour)
+		reconcileBackup(t, r, "once")
+		if snapshots := listSnapshots(t, r, "once"); len(snapshots) != 1 {
+			t.Errorf("expected a single snapshot, got %v", snapshots)
+		}
+
+		updateRegistryResource(t, r.Client, "once", func(b *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) {})
+		instance, _ = reconcileBackup(t, r, "once")
+		if snapshots := listSnapshots(t, r, "once"); len(snapshots) != 2 || instance.Status.LastSnapshot != "20261019-013000" {
+			t.Errorf("expected a snapshot for the new generation, got %v and %+v", snapshots, instance.Status)
+		}
+	})
+
+	t.Run("snapshot is restored with the original IDs", func(t *testing.T) {
+		target := testutil.NewFakeRegistry()
+		defer target.Close()
+		configMaps := &corev1.ConfigMapList{}
+		if err := r.List(context.Background(), configMaps); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		restore := newTestBackup("once")
+		restore.Spec.Restore = &strimziregistryoperatorv1alpha1.BackupRestore{Snapshot: "20261019-003000"}
+		objs := []client.Object{newReadyRegistry(), restore}
+		for i := range configMaps.Items {
+			objs = append(objs, &configMaps.Items[i])
+		}
+		restorer := newTestBackupReconciler(target, clock, objs...)
+
+		instance, _ := reconcileBackup(t, restorer, "once")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionTrue, "Restored")
+		if instance.Status.RestoredSnapshot != "20261019-003000" || instance.Status.RestoreTime == nil {
+			t.Errorf("unexpected status %+v", instance.Status)
+		}
+		if versions := target.Versions("customers-value"); len(versions) != 1 || versions[0].ID != 2 {
+			t.Errorf("unexpected restored versions %+v", versions)
+		}
+		if mode, _ := target.Mode(""); mode != "READWRITE" {
+			t.Errorf("expected the registry to leave IMPORT mode, got %q", mode)
+		}
+
+		updateRegistryResource(t, restorer.Client, "once", func(b *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) {
+			b.Spec.Restore.Snapshot = ""
+		})
+		instance, _ = reconcileBackup(t, restorer, "once")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "Rejected")
+
+		updateRegistryResource(t, restorer.Client, "once", func(b *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) {
+			b.Spec.Restore.Snapshot = "20200101-000000"
+		})
+		instance, _ = reconcileBackup(t, restorer, "once")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "SnapshotNotFound")
+	})
+}
+
+func TestStrimziSchemaRegistryBackupSchedule(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	seedRegistry(t, registry, "orders-value")
+	hourly := newTestBackup("hourly")
+	hourly.Spec.Schedule = "0 * * * *"
+	hourly.Spec.KeepLast = 2
+	invalid := newTestBackup("invalid")
+	invalid.Spec.Schedule = "every hour"
+	clock := &testClock{now: testStart.Add(15 * time.Minute)}
+	r := newTestBackupReconciler(registry, clock, newReadyRegistry(), hourly, invalid)
+
+	t.Run("nothing is taken before the first tick", func(t *testing.T) {
+		instance, result := reconcileBackup(t, r, "hourly")
+		if instance.Status.LastSnapshot != "" || result.RequeueAfter != 15*time.Minute {
+			t.Errorf("unexpected status %+v (requeue after %v)", instance.Status, result.RequeueAfter)
+		}
+		if next := instance.Status.NextScheduleTime; next == nil || !next.Time.Equal(testStart.Add(30*time.Minute)) {
+			t.Errorf("unexpected next schedule time %v", next)
+		}
+	})
+
+	t.Run("a snapshot is taken at every tick and old ones are pruned", func(t *testing.T) {
+		for hour := 1; hour <= 3; hour++ {
+			clock.now = testStart.Add(time.Duration(hour)*time.Hour - 25*time.Minute)
+			instance, result := reconcileBackup(t, r, "hourly")
+			if instance.Status.LastScheduleTime == nil || result.RequeueAfter != 55*time.Minute {
+				t.Errorf("unexpected status %+v (requeue after %v)", instance.Status, result.RequeueAfter)
+			}
+		}
+		if snapshots := listSnapshots(t, r, "hourly"); !slices.Equal(snapshots, []string{"20261019-020500", "20261019-030500"}) {
+			t.Errorf("expected the two newest snapshots, got %v", snapshots)
+		}
+	})
+
+	t.Run("suspended schedule takes nothing", func(t *testing.T) {
+		updateRegistryResource(t, r.Client, "hourly", func(b *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) {
+			b.Spec.Suspend = true
+		})
+		clock.now = clock.now.Add(2 * time.Hour)
+		instance, result := reconcileBackup(t, r, "hourly")
+		if instance.Status.LastSnapshot != "20261019-030500" || instance.Status.NextScheduleTime != nil || result.RequeueAfter != 0 {
+			t.Errorf("unexpected status %+v (requeue after %v)", instance.Status, result.RequeueAfter)
+		}
+	})
+
+	t.Run("invalid schedule is reported", func(t *testing.T) {
+		instance, _ := reconcileBackup(t, r, "invalid")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "InvalidSchedule")
+	})
+}
+
+func TestStrimziSchemaRegistryBackupRegistryNotReady(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	r := newTestBackupReconciler(registry, &testClock{now: testStart}, newTestInstance(), newTestBackup("once"))
+
+	instance, result := reconcileBackup(t, r, "once")
+	expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "RegistryNotReady")
+	if result.RequeueAfter != registryNotReadyRequeue || instance.Status.ObservedGeneration != 0 {
+		t.Errorf("expected the backup to wait for the registry, got %+v (requeue after %v)", instance.Status, result.RequeueAfter)
+	}
+}
+
+func TestStrimziSchemaRegistryBackupJob(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	volume := newTestBackup("volume")
+	volume.Spec.Storage = strimziregistryoperatorv1alpha1.BackupStorage{
+		PersistentVolumeClaim: &strimziregistryoperatorv1alpha1.PersistentVolumeClaimBackupStorage{
+			ClaimName: "backups", Path: "registry"},
+	}
+	clock := &testClock{now: testStart}
+	r := newTestBackupReconciler(registry, clock, newReadyRegistry(), volume)
+	ctx := context.Background()
+
+	finishJob := func(t *testing.T, name string, condition batchv1.JobConditionType) {
+		t.Helper()
+		job := &batchv1.Job{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, job); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+			Type: condition, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(clock.now), Message: "done",
+		})
+		if err := r.Status().Update(ctx, job); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("unknown operator image is reported", func(t *testing.T) {
+		instance, _ := reconcileBackup(t, r, "volume")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "JobImageNotSet")
+	})
+
+	r.JobImage = "ghcr.io/randsw/ssr-operator:test"
+	updateRegistryResource(t, r.Client, "volume", func(b *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) {})
+
+	t.Run("job writes the snapshot to the volume", func(t *testing.T) {
+		instance, result := reconcileBackup(t, r, "volume")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionUnknown, "JobRunning")
+		if instance.Status.ActiveJob != "volume-20261019-003000" || result.RequeueAfter != backupJobPollPeriod {
+			t.Fatalf("unexpected status %+v (requeue after %v)", instance.Status, result.RequeueAfter)
+		}
+
+		job := &batchv1.Job{}
+		if err := r.Get(ctx, types.NamespacedName{Name: instance.Status.ActiveJob, Namespace: "default"}, job); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		container := job.Spec.Template.Spec.Containers[0]
+		expectedArgs := []string{"backup", "--registry-dir=/etc/registry", "--dir=/backup/registry",
+			"--snapshot=20261019-003000", "--keep-last=7"}
+		if container.Image != r.JobImage || !slices.Equal(container.Args, expectedArgs) {
+			t.Errorf("unexpected container %s %v", container.Image, container.Args)
+		}
+		if claim := job.Spec.Template.Spec.Volumes[1].PersistentVolumeClaim; claim == nil || claim.ClaimName != "backups" {
+			t.Errorf("unexpected volumes %+v", job.Spec.Template.Spec.Volumes)
+		}
+		access := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: "volume" + backupAccessSuffix, Namespace: "default"}, access); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(access.Data["url"]) != "http://test-sr.default.svc:80" {
+			t.Errorf("unexpected registry access %v", access.Data)
+		}
+
+		instance, _ = reconcileBackup(t, r, "volume")
+		if instance.Status.ActiveJob == "" {
+			t.Error("a running job must stay active")
+		}
+		finishJob(t, instance.Status.ActiveJob, batchv1.JobComplete)
+		instance, _ = reconcileBackup(t, r, "volume")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionTrue, "SnapshotTaken")
+		if instance.Status.ActiveJob != "" || instance.Status.LastSnapshot != "20261019-003000" || instance.Status.LastBackupTime == nil {
+			t.Errorf("unexpected status %+v", instance.Status)
+		}
+	})
+
+	t.Run("failed restore job is reported", func(t *testing.T) {
+		updateRegistryResource(t, r.Client, "volume", func(b *strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup) {
+			b.Spec.Restore = &strimziregistryoperatorv1alpha1.BackupRestore{}
+		})
+		instance, _ := reconcileBackup(t, r, "volume")
+		if instance.Status.ActiveJob != "volume-restore-3" {
+			t.Fatalf("unexpected active job %q", instance.Status.ActiveJob)
+		}
+		finishJob(t, instance.Status.ActiveJob, batchv1.JobFailed)
+		instance, _ = reconcileBackup(t, r, "volume")
+		expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "JobFailed")
+		if instance.Status.ActiveJob != "" || instance.Status.RestoredSnapshot != "" {
+			t.Errorf("unexpected status %+v", instance.Status)
+		}
+	})
+}
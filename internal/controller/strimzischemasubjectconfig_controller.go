@@ -0,0 +1,331 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	monitoring "github.com/randsw/schema-registry-operator-strimzi/metrics"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// subjectConfigFinalizer removes the subject configuration from the registry on deletion.
+	subjectConfigFinalizer = keyPrefix + "/subject-config"
+	// subjectConfigResyncPeriod is how often the configuration is compared with the registry
+	// to revert changes made outside of the operator.
+	subjectConfigResyncPeriod = time.Minute
+)
+
+// StrimziSchemaSubjectConfigReconciler applies StrimziSchemaSubjectConfig resources through
+// the /config and /mode endpoints of their StrimziSchemaRegistry.
+type StrimziSchemaSubjectConfigReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	// NewRegistryClient builds the REST API client. Defaults to registryclient.New.
+	NewRegistryClient RegistryClientFactory
+}
+
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemasubjectconfigs,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemasubjectconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=strimziregistryoperator.randsw.code,resources=strimzischemasubjectconfigs/finalizers,verbs=update
+
+// Reconcile applies the compatibility level and mode of a subject or context and reverts
+// changes made to them outside of the operator.
+func (r *StrimziSchemaSubjectConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	config := &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "Failed to get StrimziSchemaSubjectConfig")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, err
+	}
+
+	if config.DeletionTimestamp != nil {
+		if !controllerutil.ContainsFinalizer(config, subjectConfigFinalizer) {
+			return ctrl.Result{}, nil
+		}
+		result, err := r.removeSubjectConfig(ctx, config, logger)
+		if err != nil {
+			logger.Error(err, "Failed to remove subject configuration from the registry")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+		if !result.IsZero() {
+			return result, nil
+		}
+		controllerutil.RemoveFinalizer(config, subjectConfigFinalizer)
+		return ctrl.Result{}, r.Update(ctx, config)
+	}
+	if !controllerutil.ContainsFinalizer(config, subjectConfigFinalizer) {
+		controllerutil.AddFinalizer(config, subjectConfigFinalizer)
+		if err := r.Update(ctx, config); err != nil {
+			logger.Error(err, "Failed to add finalizer to StrimziSchemaSubjectConfig")
+			monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+			return ctrl.Result{}, err
+		}
+	}
+
+	result, err := r.applySubjectConfig(ctx, config, logger)
+	if statusErr := r.Status().Update(ctx, config); statusErr != nil {
+		logger.Error(statusErr, "Failed to update StrimziSchemaSubjectConfig status")
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+		return ctrl.Result{}, statusErr
+	}
+	if err != nil {
+		logger.Error(err, "Failed to apply subject configuration", "Subject", config.Spec.Subject, "Context", config.Spec.Context)
+		monitoring.StrimziSchemaRegistryReconcileErrorsTotal.Inc()
+	}
+	return result, err
+}
+
+// subjectConfigTarget returns the subject passed to /config and /mode: the subject, or the
+// ":.<context>:" prefix addressing a context. In a tenant context, subjects are moved into it and
+// other contexts are rejected with errContextNotAllowed.
+func subjectConfigTarget(config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig, tenant string) (string, error) {
+	if config.Spec.Context != "" {
+		if tenant != "" && config.Spec.Context != tenant {
+			return "", fmt.Errorf("%w: context %s is not context %s of namespace %s",
+				errContextNotAllowed, config.Spec.Context, tenant, config.Namespace)
+		}
+		return ":." + config.Spec.Context + ":", nil
+	}
+	return qualifySubject(tenant, config.Spec.Subject)
+}
+
+// subjectConfigClient returns a REST API client for the registry of config and the subject it
+// configures. It returns a nil client without error when the registry cannot be called yet or
+// the subject is outside of the context of the namespace, after setting the Ready condition.
+func (r *StrimziSchemaSubjectConfigReconciler) subjectConfigClient(ctx context.Context,
+	config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig) (*registryclient.Client, string, ctrl.Result, error) {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	registryKey := registryReference(config.Spec.Registry, config.Namespace)
+	if err := r.Get(ctx, registryKey, registry); err != nil {
+		if errors.IsNotFound(err) {
+			setSubjectConfigReady(config, metav1.ConditionFalse, "RegistryNotFound", fmt.Sprintf("StrimziSchemaRegistry %s not found", registryKey))
+			return nil, "", ctrl.Result{}, nil
+		}
+		return nil, "", ctrl.Result{}, err
+	}
+	if !meta.IsStatusConditionTrue(registry.Status.Conditions, "Ready") {
+		setSubjectConfigReady(config, metav1.ConditionFalse, "RegistryNotReady", fmt.Sprintf("StrimziSchemaRegistry %s is not ready", registryKey))
+		return nil, "", ctrl.Result{RequeueAfter: registryNotReadyRequeue}, nil
+	}
+	if err := registryWritable(registry); err != nil {
+		setSubjectConfigReady(config, metav1.ConditionFalse, "ReadOnlyRegistry", err.Error())
+		return nil, "", ctrl.Result{RequeueAfter: subjectConfigResyncPeriod}, nil
+	}
+	tenant, err := tenantContext(ctx, r.Client, registry, config.Namespace)
+	if err == nil {
+		var target string
+		if target, err = subjectConfigTarget(config, tenant); err == nil {
+			rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, registry)
+			if err != nil {
+				setSubjectConfigReady(config, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+				return nil, "", ctrl.Result{}, err
+			}
+			return rc, target, ctrl.Result{}, nil
+		}
+	}
+	if reason := tenancyReason(err); reason != "" {
+		setSubjectConfigReady(config, metav1.ConditionFalse, reason, err.Error())
+		return nil, "", ctrl.Result{RequeueAfter: subjectConfigResyncPeriod}, nil
+	}
+	setSubjectConfigReady(config, metav1.ConditionFalse, "TenancyUnresolved", err.Error())
+	return nil, "", ctrl.Result{}, err
+}
+
+// applySubjectConfig brings the registry in line with the spec and updates the status in memory.
+// Settings removed from the spec are removed from the registry, falling back to the global ones.
+func (r *StrimziSchemaSubjectConfigReconciler) applySubjectConfig(ctx context.Context,
+	config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig, logger logr.Logger) (ctrl.Result, error) {
+	rc, target, result, err := r.subjectConfigClient(ctx, config)
+	if rc == nil {
+		return result, err
+	}
+	spec, status := config.Spec, &config.Status
+	drifted := false
+
+	// Compatibility level and normalize flag (/config).
+	desired := registryclient.CompatibilityConfig{Compatibility: string(spec.Compatibility), Normalize: spec.Normalize}
+	dropped := (status.Compatibility != "" && spec.Compatibility == "") || (status.Normalize != nil && spec.Normalize == nil)
+	if dropped {
+		if err := rc.DeleteConfig(ctx, target); err != nil && !registryclient.IsNotFound(err) {
+			return r.registryFailure(config, err)
+		}
+	}
+	if desired.Compatibility != "" || desired.Normalize != nil {
+		current, err := rc.GetConfig(ctx, target)
+		if err != nil && !registryclient.IsNotFound(err) {
+			return r.registryFailure(config, err)
+		}
+		if dropped || current == nil || !compatibilityConfigMatches(current, desired) {
+			drifted = drifted || current != nil && !dropped && compatibilityConfigApplied(status, desired)
+			if err := rc.SetConfig(ctx, target, desired); err != nil {
+				return r.registryFailure(config, err)
+			}
+		}
+	}
+
+	// Mode (/mode).
+	if spec.Mode == "" && status.Mode != "" {
+		if err := rc.DeleteMode(ctx, target); err != nil && !registryclient.IsNotFound(err) {
+			return r.registryFailure(config, err)
+		}
+	}
+	if spec.Mode != "" {
+		current, err := rc.GetMode(ctx, target)
+		if err != nil && !registryclient.IsNotFound(err) {
+			return r.registryFailure(config, err)
+		}
+		if current != string(spec.Mode) {
+			drifted = drifted || err == nil && status.Mode == spec.Mode
+			if err := rc.SetMode(ctx, target, string(spec.Mode)); err != nil {
+				return r.registryFailure(config, err)
+			}
+		}
+	}
+
+	if drifted {
+		logger.Info("Reverted subject configuration changed outside of the operator", "Subject", target)
+		now := metav1.Now()
+		status.LastDriftTime = &now
+	}
+	status.Compatibility = spec.Compatibility
+	status.Mode = spec.Mode
+	status.Normalize = spec.Normalize
+	status.ObservedGeneration = config.Generation
+	setSubjectConfigReady(config, metav1.ConditionTrue, "Applied", fmt.Sprintf("configuration applied to %q", target))
+	return ctrl.Result{RequeueAfter: subjectConfigResyncPeriod}, nil
+}
+
+// registryFailure reports a failed registry call. Requests rejected by the registry (e.g. IMPORT
+// mode on a subject with schemas) wait for a spec change or the next resync instead of retrying.
+func (r *StrimziSchemaSubjectConfigReconciler) registryFailure(
+	config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig, err error) (ctrl.Result, error) {
+	if registryclient.IsClientError(err) {
+		setSubjectConfigReady(config, metav1.ConditionFalse, "Rejected", err.Error())
+		return ctrl.Result{RequeueAfter: subjectConfigResyncPeriod}, nil
+	}
+	setSubjectConfigReady(config, metav1.ConditionFalse, "RegistryUnreachable", err.Error())
+	return ctrl.Result{}, err
+}
+
+// compatibilityConfigMatches reports whether the registry configuration holds the desired settings.
+func compatibilityConfigMatches(current *registryclient.CompatibilityConfig, desired registryclient.CompatibilityConfig) bool {
+	if desired.Compatibility != "" && current.Compatibility != desired.Compatibility {
+		return false
+	}
+	return desired.Normalize == nil || ptr.Deref(current.Normalize, false) == *desired.Normalize
+}
+
+// compatibilityConfigApplied reports whether the status records desired as already applied,
+// in which case a mismatch in the registry is a change made outside of the operator.
+func compatibilityConfigApplied(status *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfigStatus,
+	desired registryclient.CompatibilityConfig) bool {
+	return string(status.Compatibility) == desired.Compatibility && ptr.Equal(status.Normalize, desired.Normalize)
+}
+
+// removeSubjectConfig removes the settings applied by the operator before the resource is
+// deleted. Nothing is left to clean up when the registry itself is gone, or when the subject was
+// never applied because it is outside of the context of the namespace or the registry is read-only.
+// While the registry is not ready, the cleanup is retried and the finalizer kept, so the settings
+// are not left behind in a registry coming back.
+func (r *StrimziSchemaSubjectConfigReconciler) removeSubjectConfig(ctx context.Context,
+	config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig, logger logr.Logger) (ctrl.Result, error) {
+	registry := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+	if err := r.Get(ctx, registryReference(config.Spec.Registry, config.Namespace), registry); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if registry.DeletionTimestamp != nil || registryReadOnly(registry) {
+		return ctrl.Result{}, nil
+	}
+	if !meta.IsStatusConditionTrue(registry.Status.Conditions, "Ready") {
+		logger.Info("Waiting for the registry to be ready to remove the subject configuration", "Registry", registry.Name)
+		return ctrl.Result{RequeueAfter: registryNotReadyRequeue}, nil
+	}
+	tenant, err := tenantContext(ctx, r.Client, registry, config.Namespace)
+	if tenancyReason(err) != "" {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, err
+	}
+	target, err := subjectConfigTarget(config, tenant)
+	if err != nil {
+		return ctrl.Result{}, nil
+	}
+	rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, registry)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	logger.Info("Removing subject configuration", "Subject", target)
+	if config.Status.Compatibility != "" || config.Status.Normalize != nil {
+		if err := rc.DeleteConfig(ctx, target); err != nil && !registryclient.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+	if config.Status.Mode != "" {
+		if err := rc.DeleteMode(ctx, target); err != nil && !registryclient.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// setSubjectConfigReady sets the Ready condition of a StrimziSchemaSubjectConfig.
+func setSubjectConfigReady(config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig,
+	status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&config.Status.Conditions, metav1.Condition{
+		Type:               "Ready",
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: config.Generation,
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *StrimziSchemaSubjectConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{},
+		registryRefIndex, registryRefKey); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{}).
+		Watches(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{},
+			handler.EnqueueRequestsFromMapFunc(requestsForRegistryRef(r.Client, &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfigList{}))).
+		Named("strimzischemasubjectconfig").
+		Complete(r)
+}
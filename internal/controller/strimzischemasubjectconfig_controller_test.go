@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newTestSubjectConfig(name, subject string) *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig {
+	return &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1},
+		Spec: strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfigSpec{
+			Registry:      strimziregistryoperatorv1alpha1.RegistryReference{Name: "test-sr"},
+			Subject:       subject,
+			Compatibility: "FULL",
+		},
+	}
+}
+
+// newTestSubjectConfigReconciler returns a reconciler whose registry clients target the fake registry.
+func newTestSubjectConfigReconciler(registry *testutil.FakeRegistry, objs ...client.Object) *StrimziSchemaSubjectConfigReconciler {
+	c := newRegistryResourceClient(&strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{}, objs...)
+	return &StrimziSchemaSubjectConfigReconciler{Client: c, Scheme: c.Scheme(), NewRegistryClient: fakeRegistryClientFactory(registry.URL)}
+}
+
+func reconcileSubjectConfig(t *testing.T, r *StrimziSchemaSubjectConfigReconciler,
+	name string) *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig {
+	t.Helper()
+	config := &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{}
+	reconcileRegistryResource(t, r, r.Client, name, config)
+	return config
+}
+
+func TestStrimziSchemaSubjectConfigReconcile(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	orders := newTestSubjectConfig("orders", "orders-value")
+	orders.Spec.Mode = strimziregistryoperatorv1alpha1.RegistryModeReadOnly
+	teamA := newTestSubjectConfig("team-a", "")
+	teamA.Spec.Context = "team-a"
+	r := newTestSubjectConfigReconciler(registry, newReadyRegistry(), orders, teamA)
+
+	t.Run("configuration is applied", func(t *testing.T) {
+		config := reconcileSubjectConfig(t, r, "orders")
+		if level, _ := registry.Compatibility("orders-value"); level != "FULL" {
+			t.Errorf("expected FULL compatibility, got %q", level)
+		}
+		if mode, _ := registry.Mode("orders-value"); mode != "READONLY" {
+			t.Errorf("expected READONLY mode, got %q", mode)
+		}
+		if config.Status.Compatibility != "FULL" || config.Status.Mode != "READONLY" || config.Status.LastDriftTime != nil {
+			t.Errorf("unexpected status %+v", config.Status)
+		}
+		if !meta.IsStatusConditionTrue(config.Status.Conditions, "Ready") {
+			t.Errorf("expected Ready condition, got %+v", config.Status.Conditions)
+		}
+		if level, _ := registry.Compatibility(""); level != "BACKWARD" {
+			t.Error("the global compatibility level must not change")
+		}
+	})
+
+	t.Run("changes made outside of the operator are reverted", func(t *testing.T) {
+		registry.SetCompatibility("orders-value", "NONE")
+		config := reconcileSubjectConfig(t, r, "orders")
+		if level, _ := registry.Compatibility("orders-value"); level != "FULL" {
+			t.Errorf("expected FULL compatibility, got %q", level)
+		}
+		if config.Status.LastDriftTime == nil {
+			t.Error("expected the drift to be reported")
+		}
+	})
+
+	t.Run("settings removed from the spec are removed from the registry", func(t *testing.T) {
+		updateRegistryResource(t, r.Client, "orders", func(config *strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig) {
+			config.Spec.Mode = ""
+		})
+		config := reconcileSubjectConfig(t, r, "orders")
+		if _, ok := registry.Mode("orders-value"); ok {
+			t.Error("expected the subject mode to be removed")
+		}
+		if config.Status.Mode != "" {
+			t.Errorf("unexpected status %+v", config.Status)
+		}
+	})
+
+	t.Run("context is addressed by its prefix", func(t *testing.T) {
+		reconcileSubjectConfig(t, r, "team-a")
+		if level, _ := registry.Compatibility(":.team-a:"); level != "FULL" {
+			t.Errorf("expected FULL compatibility on the context, got %q", level)
+		}
+	})
+
+	t.Run("rejected configuration sets the Ready condition", func(t *testing.T) {
+		imported := newTestSubjectConfig("payments", "payments-value")
+		imported.Spec.Mode = strimziregistryoperatorv1alpha1.RegistryModeImport
+		if err := r.Create(context.Background(), imported); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rc, _ := registryclient.New(registryclient.Config{URL: registry.URL})
+		if _, err := rc.RegisterSchema(context.Background(), "payments-value", registryclient.Schema{Schema: testAvroSchema}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		config := reconcileSubjectConfig(t, r, "payments")
+		ready := meta.FindStatusCondition(config.Status.Conditions, "Ready")
+		if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != "Rejected" {
+			t.Errorf("expected Ready=False with reason Rejected, got %+v", ready)
+		}
+	})
+
+	t.Run("deletion removes the configuration from the registry", func(t *testing.T) {
+		config := &strimziregistryoperatorv1alpha1.StrimziSchemaSubjectConfig{}
+		_ = r.Get(context.Background(), types.NamespacedName{Name: "orders", Namespace: "default"}, config)
+		if err := r.Delete(context.Background(), config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		reconcileSubjectConfig(t, r, "orders")
+		if _, ok := registry.Compatibility("orders-value"); ok {
+			t.Error("expected the subject configuration to be removed")
+		}
+		err := r.Get(context.Background(), types.NamespacedName{Name: "orders", Namespace: "default"}, config)
+		if !errors.IsNotFound(err) {
+			t.Errorf("expected the resource to be deleted once the finalizer is removed, got %v", err)
+		}
+	})
+}
+
+func TestStrimziSchemaSubjectConfigDeletionRegistryNotReady(t *testing.T) {
+	notReady := newTestInstance()
+	config := newTestSubjectConfig("orders", "orders-value")
+	config.Finalizers = []string{subjectConfigFinalizer}
+	config.Status.Compatibility = "FULL"
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	r := newTestSubjectConfigReconciler(registry, notReady, config)
+	if err := r.Delete(context.Background(), config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := reconcileRegistryResource(t, r, r.Client, "orders", config)
+	if result.RequeueAfter != registryNotReadyRequeue {
+		t.Errorf("expected a requeue while the registry is not ready, got %+v", result)
+	}
+	if !slices.Contains(config.Finalizers, subjectConfigFinalizer) {
+		t.Fatal("expected the finalizer to be kept while the registry is not ready")
+	}
+
+	if err := r.Delete(context.Background(), notReady); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reconcileSubjectConfig(t, r, "orders")
+	err := r.Get(context.Background(), types.NamespacedName{Name: "orders", Namespace: "default"}, config)
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the resource to be deleted once the registry is gone, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// errNamespaceNotAllowed rejects resources of a namespace not selected by spec.tenancy.
+	errNamespaceNotAllowed = errors.New("namespace not allowed")
+	// errContextNotAllowed rejects subjects and contexts outside of the context of a tenant.
+	errContextNotAllowed = errors.New("context not allowed")
+)
+
+// tenancyReason returns the condition reason of a tenancy error, or "" for other errors.
+func tenancyReason(err error) string {
+	switch {
+	case errors.Is(err, errNamespaceNotAllowed):
+		return "NamespaceNotAllowed"
+	case errors.Is(err, errContextNotAllowed):
+		return "ContextNotAllowed"
+	}
+	return ""
+}
+
+// tenantContext returns the context the resources of namespace are confined to in the
+// registry, or "" when namespace is the registry namespace. Other namespaces may only use a
+// registry shared through spec.tenancy and selected by it, and are rejected with
+// errNamespaceNotAllowed otherwise.
+func tenantContext(ctx context.Context, c client.Reader,
+	registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, namespace string) (string, error) {
+	if namespace == registry.Namespace {
+		return "", nil
+	}
+	tenancy := registry.Spec.Tenancy
+	if tenancy == nil {
+		return "", notSharedError(registry, namespace)
+	}
+	if tenancy.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(tenancy.NamespaceSelector)
+		if err != nil {
+			return "", fmt.Errorf("invalid spec.tenancy.namespaceSelector of StrimziSchemaRegistry %s/%s: %w",
+				registry.Namespace, registry.Name, err)
+		}
+		ns := &v1.Namespace{}
+		if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+			return "", err
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			return "", fmt.Errorf("%w: namespace %s is not selected by StrimziSchemaRegistry %s/%s",
+				errNamespaceNotAllowed, namespace, registry.Namespace, registry.Name)
+		}
+	}
+	return tenancy.ContextPrefix + namespace, nil
+}
+
+// notSharedError rejects the resources of namespace referencing a registry of another
+// namespace that is not shared through spec.tenancy: the reference alone must not grant
+// the use of the operator's credentials for that registry.
+func notSharedError(registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, namespace string) error {
+	return fmt.Errorf("%w: StrimziSchemaRegistry %s/%s is not shared with namespace %s through spec.tenancy",
+		errNamespaceNotAllowed, registry.Namespace, registry.Name, namespace)
+}
+
+// registryWideAccess rejects with errNamespaceNotAllowed the resources of namespace acting on
+// every context of the registry (backups, exports) unless namespace is the registry namespace:
+// tenants of a registry shared through spec.tenancy must not read or overwrite each other's
+// subjects, and other namespaces may not use the registry at all.
+func registryWideAccess(registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, namespace string) error {
+	if namespace == registry.Namespace {
+		return nil
+	}
+	if registry.Spec.Tenancy == nil {
+		return notSharedError(registry, namespace)
+	}
+	return fmt.Errorf("%w: StrimziSchemaRegistry %s/%s is shared by namespaces and only resources of namespace %s may act on all of its contexts",
+		errNamespaceNotAllowed, registry.Namespace, registry.Name, registry.Namespace)
+}
+
+// qualifySubject moves subject into a tenant context: unqualified subjects are prefixed with
+// ":.<context>:" and subjects of other contexts are rejected with errContextNotAllowed.
+// Subjects are returned unchanged without a tenant context.
+func qualifySubject(tenant, subject string) (string, error) {
+	if tenant == "" {
+		return subject, nil
+	}
+	prefix := ":." + tenant + ":"
+	if strings.HasPrefix(subject, prefix) {
+		return subject, nil
+	}
+	if strings.HasPrefix(subject, ":.") {
+		return "", fmt.Errorf("%w: subject %s is outside of context %s", errContextNotAllowed, subject, tenant)
+	}
+	return prefix + subject, nil
+}
+
+// tenantSchema qualifies the subject and the references of a schema definition for the
+// namespace of a StrimziSchema, and returns the subject.
+func tenantSchema(ctx context.Context, c client.Reader, registry *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
+	schema *strimziregistryoperatorv1alpha1.StrimziSchema, definition *registryclient.Schema) (string, error) {
+	tenant, err := tenantContext(ctx, c, registry, schema.Namespace)
+	if err != nil {
+		return "", err
+	}
+	for i := range definition.References {
+		if definition.References[i].Subject, err = qualifySubject(tenant, definition.References[i].Subject); err != nil {
+			return "", err
+		}
+	}
+	return qualifySubject(tenant, schema.Spec.Subject)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newSharedRegistry returns a ready registry in namespace "registry" shared with the namespaces
+// labelled tenant=true, the test resources living in namespace "default".
+func newSharedRegistry() *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	registry := newReadyRegistry()
+	registry.Namespace = "registry"
+	registry.Spec.Tenancy = &strimziregistryoperatorv1alpha1.TenancySpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+		ContextPrefix:     "ns-",
+	}
+	return registry
+}
+
+func newTestNamespace(name string, tenant bool) *corev1.Namespace {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if tenant {
+		ns.Labels = map[string]string{"tenant": "true"}
+	}
+	return ns
+}
+
+var sharedRegistryReference = strimziregistryoperatorv1alpha1.RegistryReference{Name: "test-sr", Namespace: "registry"}
+
+func TestQualifySubject(t *testing.T) {
+	tests := []struct {
+		tenant, subject, expected string
+		err                       error
+	}{
+		{"", "orders-value", "orders-value", nil},
+		{"", ":.other:orders-value", ":.other:orders-value", nil},
+		{"team-a", "orders-value", ":.team-a:orders-value", nil},
+		{"team-a", ":.team-a:orders-value", ":.team-a:orders-value", nil},
+		{"team-a", ":.team-b:orders-value", "", errContextNotAllowed},
+		{"team-a", ":.team-a-dev:orders-value", "", errContextNotAllowed},
+	}
+	for _, test := range tests {
+		subject, err := qualifySubject(test.tenant, test.subject)
+		if subject != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("qualifySubject(%q, %q) = %q, %v; expected %q, %v",
+				test.tenant, test.subject, subject, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestTenantContext(t *testing.T) {
+	ctx := context.Background()
+	registry := newSharedRegistry()
+	c := fake.NewClientBuilder().WithScheme(newTestReconciler().Scheme).
+		WithObjects(newTestNamespace("team-a", true), newTestNamespace("team-b", false)).Build()
+
+	tests := []struct {
+		namespace, expected string
+		err                 error
+	}{
+		{"registry", "", nil},
+		{"team-a", "ns-team-a", nil},
+		{"team-b", "", errNamespaceNotAllowed},
+	}
+	for _, test := range tests {
+		tenant, err := tenantContext(ctx, c, registry, test.namespace)
+		if tenant != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("tenantContext(%q) = %q, %v; expected %q, %v", test.namespace, tenant, err, test.expected, test.err)
+		}
+	}
+
+	registry.Spec.Tenancy.NamespaceSelector = nil
+	if tenant, err := tenantContext(ctx, c, registry, "team-b"); tenant != "ns-team-b" || err != nil {
+		t.Errorf("expected every namespace to be allowed without a selector, got %q, %v", tenant, err)
+	}
+	registry.Spec.Tenancy = nil
+	if tenant, err := tenantContext(ctx, c, registry, "registry"); tenant != "" || err != nil {
+		t.Errorf("expected no context in the registry namespace, got %q, %v", tenant, err)
+	}
+	if _, err := tenantContext(ctx, c, registry, "team-a"); !errors.Is(err, errNamespaceNotAllowed) {
+		t.Errorf("expected other namespaces to be rejected without tenancy, got %v", err)
+	}
+}
+
+func TestRegistryWideAccess(t *testing.T) {
+	registry := newSharedRegistry()
+	if err := registryWideAccess(registry, "registry"); err != nil {
+		t.Errorf("expected the registry namespace to be allowed, got %v", err)
+	}
+	if err := registryWideAccess(registry, "team-a"); !errors.Is(err, errNamespaceNotAllowed) {
+		t.Errorf("expected tenants to be rejected, got %v", err)
+	}
+	registry.Spec.Tenancy = nil
+	if err := registryWideAccess(registry, "team-a"); !errors.Is(err, errNamespaceNotAllowed) {
+		t.Errorf("expected other namespaces to be rejected without tenancy, got %v", err)
+	}
+}
+
+func TestStrimziSchemaTenancy(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	newSchema := func(name, subject string) *strimziregistryoperatorv1alpha1.StrimziSchema {
+		schema := newTestSchema(name, subject)
+		schema.Spec.Registry = sharedRegistryReference
+		return schema
+	}
+	objs := []client.Object{newSharedRegistry(), newTestNamespace("default", true),
+		newSchema("orders", "orders-value"), newSchema("other", ":.ns-other:orders-value")}
+
+	t.Run("subjects are registered in the context of the namespace", func(t *testing.T) {
+		r := newTestSchemaReconciler(registry, objs...)
+		schema := reconcileSchema(t, r, "orders")
+		if !meta.IsStatusConditionTrue(schema.Status.Conditions, "Ready") {
+			t.Fatalf("expected Ready condition, got %+v", schema.Status.Conditions)
+		}
+		if schema.Status.Subject != ":.ns-default:orders-value" {
+			t.Errorf("unexpected subject %q", schema.Status.Subject)
+		}
+		if len(registry.Versions(":.ns-default:orders-value")) != 1 || len(registry.Versions("orders-value")) != 0 {
+			t.Error("expected the schema to be registered in context ns-default only")
+		}
+	})
+
+	t.Run("subjects of other contexts are rejected", func(t *testing.T) {
+		r := newTestSchemaReconciler(registry, objs...)
+		schema := reconcileSchema(t, r, "other")
+		condition := meta.FindStatusCondition(schema.Status.Conditions, "Ready")
+		if condition == nil || condition.Reason != "ContextNotAllowed" {
+			t.Errorf("expected ContextNotAllowed, got %+v", condition)
+		}
+		if len(registry.Versions(":.ns-other:orders-value")) != 0 {
+			t.Error("expected nothing to be registered in context ns-other")
+		}
+	})
+
+	t.Run("namespaces not selected are rejected", func(t *testing.T) {
+		objs := append([]client.Object{newTestNamespace("default", false)}, objs[0], objs[2])
+		r := newTestSchemaReconciler(registry, objs...)
+		schema := reconcileSchema(t, r, "orders")
+		condition := meta.FindStatusCondition(schema.Status.Conditions, "Ready")
+		if condition == nil || condition.Reason != "NamespaceNotAllowed" {
+			t.Errorf("expected NamespaceNotAllowed, got %+v", condition)
+		}
+	})
+
+	t.Run("registries of other namespaces without tenancy are rejected", func(t *testing.T) {
+		notShared := newSharedRegistry()
+		notShared.Spec.Tenancy = nil
+		r := newTestSchemaReconciler(registry, notShared, objs[1], objs[2])
+		schema := reconcileSchema(t, r, "orders")
+		condition := meta.FindStatusCondition(schema.Status.Conditions, "Ready")
+		if condition == nil || condition.Reason != "NamespaceNotAllowed" {
+			t.Errorf("expected NamespaceNotAllowed, got %+v", condition)
+		}
+		if len(registry.Versions("orders-value")) != 0 {
+			t.Error("expected nothing to be registered")
+		}
+	})
+
+	t.Run("admission rejects subjects of other contexts", func(t *testing.T) {
+		c := fake.NewClientBuilder().WithScheme(newTestReconciler().Scheme).WithObjects(objs...).Build()
+		factory := fakeRegistryClientFactory(registry.URL)
+		if result := CheckSchemaCompatibility(context.Background(), c, factory, newSchema("other", ":.ns-other:orders-value"), nil); result.Compatible {
+			t.Errorf("expected the schema to be rejected, got %+v", result)
+		}
+		if result := CheckSchemaCompatibility(context.Background(), c, factory, newSchema("orders", "orders-value"), nil); !result.Compatible {
+			t.Errorf("expected the schema to be admitted, got %+v", result)
+		}
+	})
+}
+
+func TestStrimziSchemaSubjectConfigTenancy(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	orders := newTestSubjectConfig("orders", "orders-value")
+	orders.Spec.Registry = sharedRegistryReference
+	own := newTestSubjectConfig("own", "")
+	own.Spec.Registry, own.Spec.Context = sharedRegistryReference, "ns-default"
+	other := newTestSubjectConfig("other", "")
+	other.Spec.Registry, other.Spec.Context = sharedRegistryReference, "ns-other"
+	r := newTestSubjectConfigReconciler(registry, newSharedRegistry(), newTestNamespace("default", true), orders, own, other)
+
+	reconcileSubjectConfig(t, r, "orders")
+	if level, _ := registry.Compatibility(":.ns-default:orders-value"); level != "FULL" {
+		t.Errorf("expected FULL compatibility in context ns-default, got %q", level)
+	}
+	if _, ok := registry.Compatibility("orders-value"); ok {
+		t.Error("the subject of the default context must not change")
+	}
+
+	reconcileSubjectConfig(t, r, "own")
+	if level, _ := registry.Compatibility(":.ns-default:"); level != "FULL" {
+		t.Errorf("expected FULL compatibility for context ns-default, got %q", level)
+	}
+
+	config := reconcileSubjectConfig(t, r, "other")
+	condition := meta.FindStatusCondition(config.Status.Conditions, "Ready")
+	if condition == nil || condition.Reason != "ContextNotAllowed" {
+		t.Errorf("expected ContextNotAllowed, got %+v", condition)
+	}
+	if _, ok := registry.Compatibility(":.ns-other:"); ok {
+		t.Error("context ns-other must not change")
+	}
+}
+
+func TestStrimziSchemaRegistryBackupTenancy(t *testing.T) {
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+
+	backup := newTestBackup("snapshots")
+	backup.Spec.Registry = sharedRegistryReference
+	r := newTestBackupReconciler(registry, &testClock{now: testStart}, newSharedRegistry(), backup)
+	instance, _ := reconcileBackup(t, r, "snapshots")
+	expectReady(t, instance.Status.Conditions, metav1.ConditionFalse, "NamespaceNotAllowed")
+	if snapshots := listSnapshots(t, r, "snapshots"); len(snapshots) != 0 {
+		t.Errorf("expected no snapshot, got %v", snapshots)
+	}
+}
@@ -0,0 +1,391 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	apps "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+)
+
+const (
+	// versionKey records, on the pod template, the version of spec.version it runs.
+	versionKey = keyPrefix + "/version"
+	// revisionKey is the revision the Deployment controller records on Deployments and ReplicaSets.
+	revisionKey = "deployment.kubernetes.io/revision"
+	// upgradePollPeriod is how often pending pre-flight checks and rollouts are looked at, on
+	// top of the events of the Deployment and the backup.
+	upgradePollPeriod = 10 * time.Second
+	// defaultProgressDeadlineSeconds is the time a rollout has to become ready.
+	defaultProgressDeadlineSeconds = 600
+)
+
+// errUnsupportedVersion is returned for versions missing from the version table.
+var errUnsupportedVersion = errors.New("version is not in the version table")
+
+// rolloutVersion returns the version the Deployment runs: the target of spec.version once its
+// pre-flight checks passed, the version running before otherwise. It is empty when the version
+// is not managed and the template image is run as is.
+func rolloutVersion(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) string {
+	if instance.Spec.Version == "" {
+		return ""
+	}
+	if up := instance.Status.Upgrade; up != nil && up.ToVersion == instance.Spec.Version {
+		switch up.Phase {
+		case strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut, strimziregistryoperatorv1alpha1.UpgradePhaseSucceeded:
+			return up.ToVersion
+		}
+	}
+	return instance.Status.Version
+}
+
+// progressDeadlineSeconds returns the time a rollout of spec.version has to become ready.
+func progressDeadlineSeconds(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry) int32 {
+	if instance.Spec.Upgrade != nil && instance.Spec.Upgrade.ProgressDeadlineSeconds != nil {
+		return *instance.Spec.Upgrade.ProgressDeadlineSeconds
+	}
+	return defaultProgressDeadlineSeconds
+}
+
+// applyUpgradeStrategy makes Deployments of a managed version roll one pod at a time, never
+// going below the desired replicas, and report a rollout not ready within its deadline.
+func applyUpgradeStrategy(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, spec *apps.DeploymentSpec) {
+	if rolloutVersion(instance) == "" {
+		return
+	}
+	spec.Strategy = apps.DeploymentStrategy{
+		Type: apps.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &apps.RollingUpdateDeployment{
+			MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+			MaxSurge:       ptr.To(intstr.FromInt32(1)),
+		},
+	}
+	spec.ProgressDeadlineSeconds = ptr.To(progressDeadlineSeconds(instance))
+}
+
+// reconcileUpgrade moves a change of spec.version through its phases: pre-flight checks, the
+// rollout of the new image, and its completion or rollback. Phase changes are written to the
+// status right away, as the Deployment is built from them before Reconcile updates the status.
+// It returns when to look at the upgrade again, and false when there is nothing to deploy.
+func (r *StrimziSchemaRegistryReconciler) reconcileUpgrade(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) (time.Duration, bool, error) {
+	target, status := instance.Spec.Version, &instance.Status
+	if target == "" {
+		if status.Version == "" && status.Upgrade == nil {
+			return 0, true, nil
+		}
+		status.Version, status.Upgrade = "", nil
+		return 0, true, r.Status().Update(ctx, instance)
+	}
+
+	dep := &apps.Deployment{}
+	err := r.Get(ctx, types.NamespacedName{Name: instance.Name + deploySuffix, Namespace: instance.Namespace}, dep)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return 0, false, err
+	}
+	exists := err == nil
+
+	image, supported := r.versionTable().image(instance.Spec.Flavor, target)
+	if !supported {
+		// The running version is kept; a new registry is not deployed.
+		deploy := status.Version != "" || exists
+		if up := status.Upgrade; up != nil && up.ToVersion == target && up.Phase == strimziregistryoperatorv1alpha1.UpgradePhaseUnsupported {
+			return 0, deploy, nil
+		}
+		logger.Info("Version is not in the version table", "Version", target)
+		startUpgrade(instance, target, "", strimziregistryoperatorv1alpha1.UpgradePhaseUnsupported,
+			fmt.Sprintf("%v: %s", errUnsupportedVersion, target))
+		return 0, deploy, r.Status().Update(ctx, instance)
+	}
+
+	up := status.Upgrade
+	if !exists {
+		// A Deployment recreated by migrateDeploymentSelector keeps the running version, the
+		// checks of a pending upgrade resuming once it exists again.
+		recreating, err := r.recreatingDeployment(ctx, instance)
+		if err != nil {
+			return 0, false, err
+		}
+		if recreating {
+			return 0, true, nil
+		}
+		// A new registry starts on the target version without checks.
+		if up != nil && up.ToVersion == target && up.Phase == strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut {
+			return 0, true, nil
+		}
+		startUpgrade(instance, target, image, strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut, "deploying a new registry")
+		return 0, true, r.Status().Update(ctx, instance)
+	}
+
+	if target == status.Version {
+		// Nothing to do, unless spec.version was set back during an upgrade.
+		if up != nil && up.ToVersion != target && up.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseSucceeded {
+			logger.Info("Upgrade abandoned", "Version", up.ToVersion)
+			status.Upgrade = nil
+			return 0, true, r.Status().Update(ctx, instance)
+		}
+		return 0, true, nil
+	}
+
+	if up == nil || up.ToVersion != target || up.Phase == strimziregistryoperatorv1alpha1.UpgradePhaseUnsupported {
+		logger.Info("Starting upgrade", "From", status.Version, "To", target)
+		startUpgrade(instance, target, image, strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks, "")
+		if err := r.Status().Update(ctx, instance); err != nil {
+			return 0, true, err
+		}
+	}
+
+	switch status.Upgrade.Phase {
+	case strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks:
+		requeue, err := r.runPreflightChecks(ctx, instance, logger)
+		return requeue, true, err
+	case strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut:
+		requeue, err := r.trackRollout(ctx, instance, dep, logger)
+		return requeue, true, err
+	}
+	return 0, true, nil
+}
+
+// startUpgrade records the start of an upgrade phase for the target version.
+func startUpgrade(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, target, image string,
+	phase strimziregistryoperatorv1alpha1.UpgradePhase, message string) {
+	instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{
+		FromVersion: instance.Status.Version,
+		ToVersion:   target,
+		Image:       image,
+		Phase:       phase,
+		StartTime:   &metav1.Time{Time: time.Now()},
+		Message:     message,
+	}
+}
+
+// setUpgradePhase moves the current upgrade to another phase.
+func setUpgradePhase(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
+	phase strimziregistryoperatorv1alpha1.UpgradePhase, message string) {
+	up := instance.Status.Upgrade
+	up.Phase, up.Message = phase, message
+	up.StartTime = &metav1.Time{Time: time.Now()}
+}
+
+// upgradeWaiting reports what the pre-flight checks wait for, updating the status only when
+// the message changed.
+func (r *StrimziSchemaRegistryReconciler) upgradeWaiting(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, message string) (time.Duration, error) {
+	if instance.Status.Upgrade.Message == message {
+		return upgradePollPeriod, nil
+	}
+	instance.Status.Upgrade.Message = message
+	return upgradePollPeriod, r.Status().Update(ctx, instance)
+}
+
+// runPreflightChecks lets the rollout start once the registry answers and, with
+// spec.upgrade.backup, once a snapshot of its schemas was taken.
+func (r *StrimziSchemaRegistryReconciler) runPreflightChecks(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, logger logr.Logger) (time.Duration, error) {
+	rc, err := newRegistryClient(ctx, r.Client, r.NewRegistryClient, instance)
+	if err == nil {
+		err = rc.Ping(ctx)
+	}
+	if err != nil {
+		logger.Info("Upgrade waits for the registry to answer", "error", err.Error())
+		return r.upgradeWaiting(ctx, instance, "the registry is not responsive: "+err.Error())
+	}
+
+	if instance.Spec.Upgrade != nil && instance.Spec.Upgrade.Backup != nil {
+		name, message, err := r.upgradeBackup(ctx, instance, *instance.Spec.Upgrade.Backup)
+		if err != nil {
+			return 0, err
+		}
+		instance.Status.Upgrade.Backup = name
+		if message != "" {
+			return r.upgradeWaiting(ctx, instance, message)
+		}
+	}
+
+	logger.Info("Pre-flight checks passed, rolling out", "Version", instance.Status.Upgrade.ToVersion)
+	setUpgradePhase(instance, strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut,
+		fmt.Sprintf("rolling out %s", instance.Status.Upgrade.Image))
+	return upgradePollPeriod, r.Status().Update(ctx, instance)
+}
+
+// upgradeBackupName returns the StrimziSchemaRegistryBackup taken before upgrading to a version.
+func upgradeBackupName(instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, version string) string {
+	version = strings.NewReplacer(".", "-", "_", "-").Replace(strings.ToLower(version))
+	return instance.Name + "-upgrade-" + version
+}
+
+// upgradeBackup creates the one-shot StrimziSchemaRegistryBackup of an upgrade. It returns
+// its name and, until its snapshot was taken, what the upgrade waits for.
+func (r *StrimziSchemaRegistryReconciler) upgradeBackup(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, storage strimziregistryoperatorv1alpha1.BackupStorage) (string, string, error) {
+	name := upgradeBackupName(instance, instance.Status.Upgrade.ToVersion)
+	backup := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: instance.Namespace}, backup)
+	if apierrors.IsNotFound(err) {
+		backup = &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: instance.Namespace},
+			Spec: strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackupSpec{
+				Registry: strimziregistryoperatorv1alpha1.RegistryReference{Name: instance.Name},
+				Storage:  storage,
+			},
+		}
+		if err := ctrl.SetControllerReference(instance, backup, r.Scheme); err != nil {
+			return "", "", err
+		}
+		if err := r.Create(ctx, backup); err != nil {
+			return "", "", err
+		}
+		return name, "waiting for StrimziSchemaRegistryBackup " + name, nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	ready := meta.FindStatusCondition(backup.Status.Conditions, "Ready")
+	switch {
+	case backup.Status.ObservedGeneration != backup.Generation || backup.Status.ActiveJob != "" || ready == nil:
+		return name, "waiting for StrimziSchemaRegistryBackup " + name, nil
+	case ready.Status != metav1.ConditionTrue:
+		return name, fmt.Sprintf("StrimziSchemaRegistryBackup %s failed: %s", name, ready.Message), nil
+	}
+	return name, "", nil
+}
+
+// trackRollout completes the upgrade once every replica runs the target version, and rolls it
+// back when the Deployment reports that the rollout missed its progress deadline.
+func (r *StrimziSchemaRegistryReconciler) trackRollout(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, dep *apps.Deployment, logger logr.Logger) (time.Duration, error) {
+	up := instance.Status.Upgrade
+	if rolloutComplete(dep, up.ToVersion) {
+		logger.Info("Upgrade completed", "Version", up.ToVersion)
+		instance.Status.Version = up.ToVersion
+		setUpgradePhase(instance, strimziregistryoperatorv1alpha1.UpgradePhaseSucceeded,
+			fmt.Sprintf("all replicas run %s", up.ToVersion))
+		return 0, r.Status().Update(ctx, instance)
+	}
+	if dep.Spec.Template.Annotations[versionKey] != up.ToVersion || !rolloutFailed(dep) {
+		return upgradePollPeriod, nil
+	}
+	return 0, r.rollBackUpgrade(ctx, instance, dep, logger)
+}
+
+// rolloutComplete reports whether every replica of the Deployment runs the version.
+func rolloutComplete(dep *apps.Deployment, version string) bool {
+	if dep.Spec.Template.Annotations[versionKey] != version || dep.Status.ObservedGeneration < dep.Generation {
+		return false
+	}
+	replicas := ptr.Deref(dep.Spec.Replicas, 1)
+	return dep.Status.UpdatedReplicas == replicas && dep.Status.AvailableReplicas == replicas &&
+		dep.Status.Replicas == replicas
+}
+
+// rolloutFailed reports whether the Deployment gave up waiting for the new pods to be ready.
+func rolloutFailed(dep *apps.Deployment) bool {
+	for _, condition := range dep.Status.Conditions {
+		if condition.Type == apps.DeploymentProgressing && condition.Status == "False" &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+// rollBackUpgrade restores the pod template of the ReplicaSet that ran before the upgrade. The
+// status is updated first: should the Deployment update fail, the next reconcile builds the
+// Deployment for the previous version anyway.
+func (r *StrimziSchemaRegistryReconciler) rollBackUpgrade(ctx context.Context,
+	instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry, dep *apps.Deployment, logger logr.Logger) error {
+	up := instance.Status.Upgrade
+	previous, err := r.previousReplicaSet(ctx, dep, up.ToVersion)
+	if err != nil {
+		return err
+	}
+	if previous == nil {
+		message := fmt.Sprintf("the rollout of %s was not ready within %ds and there is no previous ReplicaSet to roll back to",
+			up.ToVersion, progressDeadlineSeconds(instance))
+		if up.Message == message {
+			return nil
+		}
+		up.Message = message
+		return r.Status().Update(ctx, instance)
+	}
+
+	logger.Info("Rolling back upgrade", "Version", up.ToVersion, "ReplicaSet", previous.Name)
+	setUpgradePhase(instance, strimziregistryoperatorv1alpha1.UpgradePhaseRolledBack,
+		fmt.Sprintf("the rollout of %s was not ready within %ds; rolled back to ReplicaSet %s",
+			up.ToVersion, progressDeadlineSeconds(instance), previous.Name))
+	if err := r.Status().Update(ctx, instance); err != nil {
+		return err
+	}
+
+	template := previous.Spec.Template.DeepCopy()
+	delete(template.Labels, apps.DefaultDeploymentUniqueLabelKey)
+	dep.Spec.Template = *template
+	// The spec hash of the previous template lets updateExistingDeployment keep it unless
+	// something besides the version changed.
+	if dep.Annotations == nil {
+		dep.Annotations = map[string]string{}
+	}
+	dep.Annotations[keyPrefix+"/specHash"] = template.Annotations[keyPrefix+"/specHash"]
+	return r.Update(ctx, dep)
+}
+
+// previousReplicaSet returns the ReplicaSet of the Deployment with the highest revision that
+// does not run the version, or nil when there is none.
+func (r *StrimziSchemaRegistryReconciler) previousReplicaSet(ctx context.Context,
+	dep *apps.Deployment, version string) (*apps.ReplicaSet, error) {
+	replicaSets := &apps.ReplicaSetList{}
+	opts := []client.ListOption{client.InNamespace(dep.Namespace)}
+	if dep.Spec.Selector != nil {
+		opts = append(opts, client.MatchingLabels(dep.Spec.Selector.MatchLabels))
+	}
+	if err := r.List(ctx, replicaSets, opts...); err != nil {
+		return nil, err
+	}
+	var previous *apps.ReplicaSet
+	var previousRevision int64
+	for i := range replicaSets.Items {
+		rs := &replicaSets.Items[i]
+		if !metav1.IsControlledBy(rs, dep) || rs.Spec.Template.Annotations[versionKey] == version {
+			continue
+		}
+		revision, err := strconv.ParseInt(rs.Annotations[revisionKey], 10, 64)
+		if err != nil {
+			continue
+		}
+		if previous == nil || revision > previousRevision {
+			previous, previousRevision = rs, revision
+		}
+	}
+	return previous, nil
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newUpgradeReconciler returns a reconciler knowing Deployments whose registry clients target url.
+// The instance is created first and read back so its status can be updated.
+func newUpgradeReconciler(t *testing.T, url string, instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry,
+	objs ...client.Object) *StrimziSchemaRegistryReconciler {
+	t.Helper()
+	r := newTestAPIReconciler(url)
+	_ = apps.AddToScheme(r.Scheme)
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).
+		WithObjects(append(objs, instance)...).
+		WithStatusSubresource(&strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}).Build()
+	if err := r.Get(context.Background(), client.ObjectKeyFromObject(instance), instance); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// newVersionInstance returns a test instance running from and asked to run to.
+func newVersionInstance(from, to string) *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry {
+	instance := newTestInstance()
+	instance.Spec.Version = to
+	instance.Status.Version = from
+	return instance
+}
+
+// newVersionDeployment returns the Deployment of the test instance with its pods on version.
+func newVersionDeployment(version string) *apps.Deployment {
+	return &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sr" + deploySuffix, Namespace: "default", UID: "deploy-uid", Generation: 2},
+		Spec: apps.DeploymentSpec{
+			Replicas: ptr.To[int32](1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/instance": "test-sr"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{versionKey: version}},
+			},
+		},
+		Status: apps.DeploymentStatus{ObservedGeneration: 2},
+	}
+}
+
+// newVersionReplicaSet returns a ReplicaSet of dep at a revision running version.
+func newVersionReplicaSet(dep *apps.Deployment, revision, version string) *apps.ReplicaSet {
+	return &apps.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dep.Name + "-" + strings.ReplaceAll(version, ".", ""),
+			Namespace:   dep.Namespace,
+			Labels:      dep.Spec.Selector.MatchLabels,
+			Annotations: map[string]string{revisionKey: revision},
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment",
+				Name: dep.Name, UID: dep.UID, Controller: ptr.To(true)}},
+		},
+		Spec: apps.ReplicaSetSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app.kubernetes.io/instance": "test-sr",
+						apps.DefaultDeploymentUniqueLabelKey: "hash-" + revision},
+					Annotations: map[string]string{versionKey: version, keyPrefix + "/specHash": "hash-" + version},
+				},
+			},
+		},
+	}
+}
+
+func TestRolloutVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *strimziregistryoperatorv1alpha1.StrimziSchemaRegistry
+		phase    strimziregistryoperatorv1alpha1.UpgradePhase
+		want     string
+	}{
+		{"unmanaged", newVersionInstance("", ""), "", ""},
+		{"running version", newVersionInstance("7.6.5", "7.9.0"), "", "7.6.5"},
+		{"pre-flight checks", newVersionInstance("7.6.5", "7.9.0"), strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks, "7.6.5"},
+		{"rolling out", newVersionInstance("7.6.5", "7.9.0"), strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut, "7.9.0"},
+		{"rolled back", newVersionInstance("7.6.5", "7.9.0"), strimziregistryoperatorv1alpha1.UpgradePhaseRolledBack, "7.6.5"},
+	}
+	for _, test := range tests {
+		if test.phase != "" {
+			test.instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{ToVersion: "7.9.0", Phase: test.phase}
+		}
+		if got := rolloutVersion(test.instance); got != test.want {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+}
+
+func TestReconcileUpgrade(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("new registry deploys the target version", func(t *testing.T) {
+		instance := newVersionInstance("", "7.9.0")
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance)
+		_, deploy, err := r.reconcileUpgrade(ctx, instance, logr.Discard())
+		if err != nil || !deploy {
+			t.Fatalf("expected the registry to be deployed, got deploy=%v err=%v", deploy, err)
+		}
+		up := instance.Status.Upgrade
+		if up == nil || up.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut ||
+			up.Image != "confluentinc/cp-schema-registry:7.9.0" {
+			t.Errorf("expected the rollout of 7.9.0, got %+v", up)
+		}
+		if rolloutVersion(instance) != "7.9.0" {
+			t.Errorf("expected the Deployment to be built for 7.9.0, got %q", rolloutVersion(instance))
+		}
+	})
+
+	t.Run("unsupported version blocks a new registry", func(t *testing.T) {
+		instance := newVersionInstance("", "9.9.9")
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance)
+		_, deploy, err := r.reconcileUpgrade(ctx, instance, logr.Discard())
+		if err != nil || deploy {
+			t.Fatalf("expected no Deployment, got deploy=%v err=%v", deploy, err)
+		}
+		if up := instance.Status.Upgrade; up == nil || up.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseUnsupported {
+			t.Errorf("expected the Unsupported phase, got %+v", up)
+		}
+	})
+
+	t.Run("unsupported version keeps a running registry", func(t *testing.T) {
+		instance := newVersionInstance("7.6.5", "9.9.9")
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance, newVersionDeployment("7.6.5"))
+		if _, deploy, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil || !deploy {
+			t.Fatalf("expected the running version to be kept, got deploy=%v err=%v", deploy, err)
+		}
+		if rolloutVersion(instance) != "7.6.5" {
+			t.Errorf("expected the Deployment to stay on 7.6.5, got %q", rolloutVersion(instance))
+		}
+	})
+
+	t.Run("pre-flight checks wait for the registry", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		instance := newVersionInstance("7.6.5", "7.9.0")
+		r := newUpgradeReconciler(t, server.URL, instance, newVersionDeployment("7.6.5"))
+		requeue, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard())
+		if err != nil {
+			t.Fatal(err)
+		}
+		up := instance.Status.Upgrade
+		if up.Phase != strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks ||
+			!strings.Contains(up.Message, "not responsive") || requeue != upgradePollPeriod {
+			t.Errorf("expected the upgrade to wait for the registry, got %+v requeue=%s", up, requeue)
+		}
+		if up.FromVersion != "7.6.5" || rolloutVersion(instance) != "7.6.5" {
+			t.Errorf("expected the registry to stay on 7.6.5, got %+v", up)
+		}
+	})
+
+	t.Run("pre-flight checks start the rollout", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		instance := newVersionInstance("7.6.5", "7.9.0")
+		r := newUpgradeReconciler(t, registry.URL, instance, newVersionDeployment("7.6.5"))
+		if _, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil {
+			t.Fatal(err)
+		}
+		if up := instance.Status.Upgrade; up.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut {
+			t.Errorf("expected the rollout to start, got %+v", up)
+		}
+		stored := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(instance), stored); err != nil {
+			t.Fatal(err)
+		}
+		if stored.Status.Upgrade == nil || stored.Status.Upgrade.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut {
+			t.Errorf("expected the phase to be persisted, got %+v", stored.Status.Upgrade)
+		}
+	})
+
+	t.Run("pre-flight checks take a backup", func(t *testing.T) {
+		registry := testutil.NewFakeRegistry()
+		defer registry.Close()
+		instance := newVersionInstance("7.6.5", "7.9.0")
+		instance.Spec.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeSpec{
+			Backup: &strimziregistryoperatorv1alpha1.BackupStorage{
+				ConfigMap: &strimziregistryoperatorv1alpha1.ConfigMapBackupStorage{},
+			},
+		}
+		r := newUpgradeReconciler(t, registry.URL, instance, newVersionDeployment("7.6.5"))
+		if _, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil {
+			t.Fatal(err)
+		}
+		up := instance.Status.Upgrade
+		if up.Phase != strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks || up.Backup != "test-sr-upgrade-7-9-0" {
+			t.Fatalf("expected the upgrade to wait for its backup, got %+v", up)
+		}
+		backup := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistryBackup{}
+		if err := r.Get(ctx, types.NamespacedName{Name: up.Backup, Namespace: "default"}, backup); err != nil {
+			t.Fatalf("expected the backup to be created: %v", err)
+		}
+		if backup.Spec.Registry.Name != "test-sr" || !metav1.IsControlledBy(backup, instance) {
+			t.Errorf("expected a backup of test-sr owned by it, got %+v", backup)
+		}
+
+		backup.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Succeeded",
+			LastTransitionTime: metav1.Now()}}
+		backup.Status.ObservedGeneration = backup.Generation
+		if err := r.Update(ctx, backup); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil {
+			t.Fatal(err)
+		}
+		if up := instance.Status.Upgrade; up.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut {
+			t.Errorf("expected the rollout to start after the backup, got %+v", up)
+		}
+	})
+
+	t.Run("completed rollout records the version", func(t *testing.T) {
+		instance := newVersionInstance("7.6.5", "7.9.0")
+		instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{FromVersion: "7.6.5", ToVersion: "7.9.0",
+			Phase: strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut}
+		dep := newVersionDeployment("7.9.0")
+		dep.Status.Replicas, dep.Status.UpdatedReplicas, dep.Status.AvailableReplicas = 1, 1, 1
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance, dep)
+		if _, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil {
+			t.Fatal(err)
+		}
+		if instance.Status.Version != "7.9.0" || instance.Status.Upgrade.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseSucceeded {
+			t.Errorf("expected the upgrade to succeed, got version %q %+v", instance.Status.Version, instance.Status.Upgrade)
+		}
+	})
+
+	t.Run("failed rollout rolls back", func(t *testing.T) {
+		instance := newVersionInstance("7.6.5", "7.9.0")
+		instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{FromVersion: "7.6.5", ToVersion: "7.9.0",
+			Phase: strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut}
+		dep := newVersionDeployment("7.9.0")
+		dep.Status.Conditions = []apps.DeploymentCondition{{Type: apps.DeploymentProgressing,
+			Status: corev1.ConditionFalse, Reason: "ProgressDeadlineExceeded"}}
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance, dep,
+			newVersionReplicaSet(dep, "1", "7.6.0"), newVersionReplicaSet(dep, "2", "7.6.5"),
+			newVersionReplicaSet(dep, "3", "7.9.0"))
+		if _, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil {
+			t.Fatal(err)
+		}
+		if up := instance.Status.Upgrade; up.Phase != strimziregistryoperatorv1alpha1.UpgradePhaseRolledBack ||
+			instance.Status.Version != "7.6.5" {
+			t.Errorf("expected the upgrade to be rolled back, got version %q %+v", instance.Status.Version, up)
+		}
+
+		found := &apps.Deployment{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(dep), found); err != nil {
+			t.Fatal(err)
+		}
+		template := found.Spec.Template
+		if template.Annotations[versionKey] != "7.6.5" || found.Annotations[keyPrefix+"/specHash"] != "hash-7.6.5" {
+			t.Errorf("expected the template of revision 2, got %+v", template.ObjectMeta)
+		}
+		if _, ok := template.Labels[apps.DefaultDeploymentUniqueLabelKey]; ok {
+			t.Error("the pod-template-hash label must not be copied to the Deployment")
+		}
+		if rolloutVersion(instance) != "7.6.5" {
+			t.Errorf("expected the Deployment to be built for 7.6.5 again, got %q", rolloutVersion(instance))
+		}
+	})
+
+	t.Run("setting the version back abandons the upgrade", func(t *testing.T) {
+		instance := newVersionInstance("7.6.5", "7.6.5")
+		instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{FromVersion: "7.6.5", ToVersion: "7.9.0",
+			Phase: strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks}
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance, newVersionDeployment("7.6.5"))
+		if _, _, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil {
+			t.Fatal(err)
+		}
+		if instance.Status.Upgrade != nil {
+			t.Errorf("expected the upgrade to be cleared, got %+v", instance.Status.Upgrade)
+		}
+	})
+
+	t.Run("a recreated Deployment does not skip the pre-flight checks", func(t *testing.T) {
+		instance := newVersionInstance("7.6.5", "7.9.0")
+		instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{FromVersion: "7.6.5", ToVersion: "7.9.0",
+			Phase: strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks}
+		r := newUpgradeReconciler(t, "http://127.0.0.1:1", instance, newOrphanedReplicaSet())
+		if _, deploy, err := r.reconcileUpgrade(ctx, instance, logr.Discard()); err != nil || !deploy {
+			t.Fatalf("expected the Deployment to be recreated, got deploy=%v err=%v", deploy, err)
+		}
+		if up := instance.Status.Upgrade; up.Phase != strimziregistryoperatorv1alpha1.UpgradePhasePreflightChecks {
+			t.Errorf("expected the upgrade to stay in its pre-flight checks, got %+v", up)
+		}
+		if rolloutVersion(instance) != "7.6.5" {
+			t.Errorf("expected the Deployment to be recreated on 7.6.5, got %q", rolloutVersion(instance))
+		}
+	})
+}
+
+// newOrphanedReplicaSet returns a ReplicaSet of the test instance left behind by
+// migrateDeploymentSelector.
+func newOrphanedReplicaSet() *apps.ReplicaSet {
+	return &apps.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sr" + deploySuffix + "-legacy",
+			Namespace: "default",
+			Labels:    labelsForStrimziSchemaRegistryOperator("test-sr", "7.6.5", "kafka"),
+		},
+	}
+}
+
+func TestBuildDeploymentSpecVersion(t *testing.T) {
+	r := newTestReconciler()
+	instance := newVersionInstance("7.6.5", "7.9.0")
+	instance.Status.Upgrade = &strimziregistryoperatorv1alpha1.UpgradeStatus{ToVersion: "7.9.0",
+		Phase: strimziregistryoperatorv1alpha1.UpgradePhaseRollingOut}
+	instance.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name: "schema-registry", Image: "confluentinc/cp-schema-registry:7.6.5"}}
+	dep, err := r.buildDeploymentSpec(instance, "kafka:9093", "kafka", "1", "", logr.Discard())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if image := dep.Spec.Template.Spec.Containers[0].Image; image != "confluentinc/cp-schema-registry:7.9.0" {
+		t.Errorf("expected the image of the version table, got %s", image)
+	}
+	if dep.Labels["app.kubernetes.io/version"] != "7.9.0" || dep.Spec.Template.Annotations[versionKey] != "7.9.0" {
+		t.Errorf("expected the version label and annotation, got %+v", dep.Spec.Template.ObjectMeta)
+	}
+	if _, ok := dep.Spec.Selector.MatchLabels["app.kubernetes.io/version"]; ok {
+		t.Error("the immutable selector must not depend on the version")
+	}
+	if ru := dep.Spec.Strategy.RollingUpdate; ru == nil || ru.MaxUnavailable.IntValue() != 0 ||
+		ptr.Deref(dep.Spec.ProgressDeadlineSeconds, 0) != defaultProgressDeadlineSeconds {
+		t.Errorf("expected a surge-only rollout with a progress deadline, got %+v", dep.Spec)
+	}
+
+	instance.Status.Upgrade = nil
+	instance.Status.Version = "1.0.0"
+	if _, err := r.buildDeploymentSpec(instance, "kafka:9093", "kafka", "1", "", logr.Discard()); !errors.Is(err, errUnsupportedVersion) {
+		t.Errorf("expected errUnsupportedVersion, got %v", err)
+	}
+}
+
+func TestLoadVersionTable(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	table, err := LoadVersionTable(write("versions.yaml",
+		"confluent:\n  7.9.0: registry.example.com/cp-schema-registry:7.9.0\n"))
+	if err != nil {
+		t.Fatalf("LoadVersionTable() error = %v", err)
+	}
+	if image, ok := table.image("", "7.9.0"); !ok || image != "registry.example.com/cp-schema-registry:7.9.0" {
+		t.Errorf("expected the mirrored image, got %q", image)
+	}
+	if _, ok := table.image(strimziregistryoperatorv1alpha1.RegistryFlavorConfluent, "7.6.5"); ok {
+		t.Error("a loaded table replaces the default one")
+	}
+
+	for name, content := range map[string]string{
+		"unknown flavor": "schemaregistry:\n  1.0.0: example/registry:1.0.0\n",
+		"empty image":    "karapace:\n  4.1.0: \"\"\n",
+	} {
+		if _, err := LoadVersionTable(write("invalid.yaml", content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestMigrateDeploymentSelector(t *testing.T) {
+	ctx := context.Background()
+	legacy := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sr" + deploySuffix, Namespace: "default"},
+		Spec: apps.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labelsForStrimziSchemaRegistryOperator("test-sr", "7.6.5", "kafka")},
+		},
+	}
+	current := legacy.DeepCopy()
+	current.Spec.Selector.MatchLabels = deploymentSelector(current.Spec.Selector.MatchLabels)
+
+	r := newTestReconciler()
+	_ = apps.AddToScheme(r.Scheme)
+	r.Client = fake.NewClientBuilder().WithScheme(r.Scheme).WithObjects(legacy).Build()
+
+	deleting, err := r.migrateDeploymentSelector(ctx, current, logr.Discard())
+	if err != nil || deleting {
+		t.Errorf("expected a Deployment with the current selector to be kept, got deleting=%v err=%v", deleting, err)
+	}
+	deleting, err = r.migrateDeploymentSelector(ctx, legacy, logr.Discard())
+	if err != nil || !deleting {
+		t.Fatalf("expected the legacy Deployment to be deleted, got deleting=%v err=%v", deleting, err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(legacy), &apps.Deployment{}); err == nil {
+		t.Error("expected the legacy Deployment to be gone so it is recreated")
+	}
+
+	instance := newTestInstance()
+	if recreating, err := r.recreatingDeployment(ctx, instance); err != nil || recreating {
+		t.Errorf("expected a new registry without ReplicaSets, got recreating=%v err=%v", recreating, err)
+	}
+	controlled := newVersionReplicaSet(newVersionDeployment("7.6.5"), "1", "7.6.5")
+	controlled.Labels = newOrphanedReplicaSet().Labels
+	if err := r.Create(ctx, controlled); err != nil {
+		t.Fatal(err)
+	}
+	if recreating, err := r.recreatingDeployment(ctx, instance); err != nil || recreating {
+		t.Errorf("expected ReplicaSets of a Deployment to be ignored, got recreating=%v err=%v", recreating, err)
+	}
+	if err := r.Create(ctx, newOrphanedReplicaSet()); err != nil {
+		t.Fatal(err)
+	}
+	if recreating, err := r.recreatingDeployment(ctx, instance); err != nil || !recreating {
+		t.Errorf("expected the orphaned ReplicaSet to mark the Deployment as recreated, got recreating=%v err=%v", recreating, err)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+)
+
+// VersionTable maps the supported versions of each flavor to the image run for them.
+type VersionTable map[strimziregistryoperatorv1alpha1.RegistryFlavor]map[string]string
+
+// DefaultVersionTable is the version table of an operator started without --version-table.
+var DefaultVersionTable = VersionTable{
+	strimziregistryoperatorv1alpha1.RegistryFlavorConfluent: {
+		"7.6.5": "confluentinc/cp-schema-registry:7.6.5",
+		"7.7.1": "confluentinc/cp-schema-registry:7.7.1",
+		"7.8.0": "confluentinc/cp-schema-registry:7.8.0",
+		"7.9.0": "confluentinc/cp-schema-registry:7.9.0",
+	},
+	strimziregistryoperatorv1alpha1.RegistryFlavorKarapace: {
+		"4.1.0": "ghcr.io/aiven-open/karapace:4.1.0",
+	},
+	strimziregistryoperatorv1alpha1.RegistryFlavorApicurio: {
+		"2.6.5.Final": "quay.io/apicurio/apicurio-registry-kafkasql:2.6.5.Final",
+	},
+}
+
+// LoadVersionTable reads a version table from a YAML file mapping each flavor to its versions
+// and their images, e.g. "confluent: {7.9.0: confluentinc/cp-schema-registry:7.9.0}".
+func LoadVersionTable(path string) (VersionTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	table := VersionTable{}
+	if err := yaml.UnmarshalStrict(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse version table %s: %w", path, err)
+	}
+	for flavor, versions := range table {
+		switch flavor {
+		case strimziregistryoperatorv1alpha1.RegistryFlavorConfluent, strimziregistryoperatorv1alpha1.RegistryFlavorKarapace,
+			strimziregistryoperatorv1alpha1.RegistryFlavorApicurio:
+		default:
+			return nil, fmt.Errorf("version table %s: unknown flavor %q", path, flavor)
+		}
+		for version, image := range versions {
+			if strings.TrimSpace(image) == "" {
+				return nil, fmt.Errorf("version table %s: no image for %s %s", path, flavor, version)
+			}
+		}
+	}
+	return table, nil
+}
+
+// image returns the image of a version of the flavor.
+func (t VersionTable) image(flavor strimziregistryoperatorv1alpha1.RegistryFlavor, version string) (string, bool) {
+	if flavor == "" {
+		flavor = strimziregistryoperatorv1alpha1.RegistryFlavorConfluent
+	}
+	image, ok := t[flavor][version]
+	return image, ok
+}
+
+// versionTable returns the version table of the operator.
+func (r *StrimziSchemaRegistryReconciler) versionTable() VersionTable {
+	if r.Versions == nil {
+		return DefaultVersionTable
+	}
+	return r.Versions
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Schema types, as named by the registry.
+const (
+	TypeAvro     = "AVRO"
+	TypeJSON     = "JSON"
+	TypeProtobuf = "PROTOBUF"
+)
+
+// Definition is the part of an Avro, JSON Schema or Protobuf definition checked by policies.
+type Definition struct {
+	// Type is AVRO, JSON or PROTOBUF.
+	Type string
+	// Name is the full name of the top-level record or message, or the title of a JSON Schema.
+	Name string
+	// Namespace is the Avro namespace, the Protobuf package or the $id of a JSON Schema.
+	Namespace string
+	// Doc documents the top-level record or message: its doc, leading comment or description.
+	Doc string
+	// Size is the size of the definition in bytes.
+	Size int
+	// Fields lists the fields of the records or messages, nested ones included.
+	Fields []Field
+}
+
+// Field is a field of a Definition.
+type Field struct {
+	// Path is the dotted path of the field: field names for Avro and JSON Schema, message and
+	// field names for Protobuf.
+	Path string
+	// Type is the type of the field, e.g. "string", "array<long>", "union<null,string>".
+	Type string
+	// Doc is the doc, comment or description of the field.
+	Doc string
+}
+
+// Parse reads a schema definition of the given type (AVRO when empty).
+func Parse(schemaType, schema string) (*Definition, error) {
+	if schemaType == "" {
+		schemaType = TypeAvro
+	}
+	def := &Definition{Type: schemaType, Size: len(schema)}
+	var err error
+	switch schemaType {
+	case TypeAvro:
+		err = parseAvro(def, schema)
+	case TypeJSON:
+		err = parseJSONSchema(def, schema)
+	case TypeProtobuf:
+		err = parseProtobuf(def, schema)
+	default:
+		err = fmt.Errorf("unknown schema type %q", schemaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+func parseAvro(def *Definition, schema string) error {
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return fmt.Errorf("invalid Avro schema: %w", err)
+	}
+	if record, ok := root.(map[string]any); ok {
+		if name, ok := record["name"].(string); ok {
+			def.Name = avroFullName(name, stringField(record, "namespace"))
+			if i := strings.LastIndex(def.Name, "."); i >= 0 {
+				def.Namespace = def.Name[:i]
+			}
+		}
+		def.Doc = stringField(record, "doc")
+	}
+	avroType(def, root, "", "")
+	return nil
+}
+
+// avroType returns the type name of an Avro schema, adding the fields of its records under path.
+func avroType(def *Definition, schema any, namespace, path string) string {
+	switch s := schema.(type) {
+	case string:
+		return s
+	case []any:
+		members := make([]string, 0, len(s))
+		for _, member := range s {
+			members = append(members, avroType(def, member, namespace, path))
+		}
+		return "union<" + strings.Join(members, ",") + ">"
+	case map[string]any:
+		typ := stringField(s, "type")
+		switch typ {
+		case "record", "error":
+			name := avroFullName(stringField(s, "name"), stringField(s, "namespace"), namespace)
+			if i := strings.LastIndex(name, "."); i >= 0 {
+				namespace = name[:i]
+			}
+			fields, _ := s["fields"].([]any)
+			for _, f := range fields {
+				field, ok := f.(map[string]any)
+				if !ok {
+					continue
+				}
+				fieldPath := joinPath(path, stringField(field, "name"))
+				i := len(def.Fields)
+				def.Fields = append(def.Fields, Field{Path: fieldPath, Doc: stringField(field, "doc")})
+				def.Fields[i].Type = avroType(def, field["type"], namespace, fieldPath)
+			}
+			return name
+		case "enum", "fixed":
+			return avroFullName(stringField(s, "name"), stringField(s, "namespace"), namespace)
+		case "array":
+			return "array<" + avroType(def, s["items"], namespace, path) + ">"
+		case "map":
+			return "map<" + avroType(def, s["values"], namespace, path) + ">"
+		}
+		if logical := stringField(s, "logicalType"); logical != "" {
+			return typ + "(" + logical + ")"
+		}
+		if typ == "" {
+			return avroType(def, s["type"], namespace, path)
+		}
+		return typ
+	}
+	return ""
+}
+
+// avroFullName qualifies an Avro name with the first non-empty namespace, unless it has one.
+func avroFullName(name string, namespaces ...string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	for _, namespace := range namespaces {
+		if namespace != "" {
+			return namespace + "." + name
+		}
+	}
+	return name
+}
+
+func parseJSONSchema(def *Definition, schema string) error {
+	var root map[string]any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+	def.Name = stringField(root, "title")
+	def.Namespace = stringField(root, "$id")
+	def.Doc = stringField(root, "description")
+	jsonSchemaFields(def, root, "")
+	return nil
+}
+
+// jsonSchemaFields adds the properties of a JSON Schema object under path, in name order.
+func jsonSchemaFields(def *Definition, schema map[string]any, path string) {
+	if items, ok := schema["items"].(map[string]any); ok {
+		jsonSchemaFields(def, items, path+"[]")
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		fieldPath := joinPath(path, name)
+		def.Fields = append(def.Fields, Field{
+			Path: fieldPath, Type: jsonSchemaType(property), Doc: stringField(property, "description"),
+		})
+		jsonSchemaFields(def, property, fieldPath)
+	}
+}
+
+// jsonSchemaType returns the type of a JSON Schema property, e.g. "string", "array<integer>",
+// "null|string" or the target of its $ref.
+func jsonSchemaType(schema map[string]any) string {
+	if ref := stringField(schema, "$ref"); ref != "" {
+		return ref
+	}
+	var typ string
+	switch t := schema["type"].(type) {
+	case string:
+		typ = t
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, member := range t {
+			if s, ok := member.(string); ok {
+				types = append(types, s)
+			}
+		}
+		typ = strings.Join(types, "|")
+	default:
+		if _, ok := schema["properties"]; ok {
+			typ = "object"
+		}
+	}
+	if items, ok := schema["items"].(map[string]any); ok && typ == "array" {
+		typ += "<" + jsonSchemaType(items) + ">"
+	}
+	return typ
+}
+
+var (
+	protoBlockComment = regexp.MustCompile(`(?s)/\*+(.*?)\*/`)
+	protoField        = regexp.MustCompile(`^(?:(repeated|optional|required)\s+)?(map\s*<[^>]*>|[.\w]+)\s+(\w+)\s*=\s*\d+`)
+)
+
+// protoScope is a block of a Protobuf definition: message, enum, oneof, service...
+type protoScope struct {
+	kind, name string
+}
+
+// parseProtobuf reads the package, messages, fields and their comments of a .proto file. Comments
+// just before a declaration, or trailing it on the same line, document it.
+func parseProtobuf(def *Definition, schema string) error {
+	schema = protoBlockComment.ReplaceAllStringFunc(schema, func(comment string) string {
+		lines := strings.Split(protoBlockComment.FindStringSubmatch(comment)[1], "\n")
+		for i, line := range lines {
+			lines[i] = "//" + strings.TrimLeft(strings.TrimSpace(line), "*")
+		}
+		return strings.Join(lines, "\n")
+	})
+
+	var scopes []protoScope
+	var pending []string
+	var statement strings.Builder
+	for _, line := range strings.Split(schema, "\n") {
+		code, comment, _ := strings.Cut(line, "//")
+		comment = strings.TrimSpace(comment)
+		if strings.TrimSpace(code) == "" {
+			if comment != "" {
+				pending = append(pending, comment)
+			} else if strings.TrimSpace(statement.String()) == "" {
+				pending = nil
+			}
+			continue
+		}
+		for _, r := range code {
+			if r != '{' && r != '}' && r != ';' {
+				statement.WriteRune(r)
+				continue
+			}
+			doc := strings.Join(pending, " ")
+			if doc == "" {
+				doc = comment
+			}
+			var err error
+			scopes, err = protoStatement(def, scopes, strings.TrimSpace(statement.String()), r, doc)
+			if err != nil {
+				return err
+			}
+			statement.Reset()
+			pending = nil
+		}
+	}
+	if len(scopes) > 0 || strings.TrimSpace(statement.String()) != "" {
+		return fmt.Errorf("invalid Protobuf schema: unexpected end of definition")
+	}
+	return nil
+}
+
+// protoStatement handles a statement ending with delim ('{', '}' or ';') and returns the scopes.
+func protoStatement(def *Definition, scopes []protoScope, statement string, delim rune, doc string) ([]protoScope, error) {
+	words := strings.Fields(statement)
+	switch delim {
+	case '{':
+		scope := protoScope{kind: "block"}
+		if len(words) == 2 && (words[0] == "message" || words[0] == "enum" || words[0] == "oneof" ||
+			words[0] == "service" || words[0] == "extend") {
+			scope = protoScope{kind: words[0], name: words[1]}
+		}
+		if scope.kind == "message" && len(scopes) == 0 && def.Name == "" {
+			def.Name = joinPath(def.Namespace, scope.name)
+			def.Doc = doc
+		}
+		return append(scopes, scope), nil
+	case '}':
+		if len(scopes) == 0 {
+			return nil, fmt.Errorf("invalid Protobuf schema: unbalanced braces")
+		}
+		return scopes[:len(scopes)-1], nil
+	}
+	if len(words) == 2 && words[0] == "package" && len(scopes) == 0 {
+		def.Namespace = words[1]
+		return scopes, nil
+	}
+	if len(scopes) == 0 {
+		return scopes, nil
+	}
+	if kind := scopes[len(scopes)-1].kind; kind != "message" && kind != "oneof" {
+		return scopes, nil
+	}
+	match := protoField.FindStringSubmatch(statement)
+	if match == nil || match[2] == "option" || match[2] == "reserved" {
+		return scopes, nil
+	}
+	typ := strings.Join(strings.Fields(match[2]), "")
+	if match[1] == "repeated" {
+		typ = "repeated<" + typ + ">"
+	}
+	def.Fields = append(def.Fields, Field{
+		Path: joinPath(strings.Join(protoMessagePath(scopes), "."), match[3]), Type: typ, Doc: doc,
+	})
+	return scopes, nil
+}
+
+// protoMessagePath returns the names of the enclosing messages.
+func protoMessagePath(scopes []protoScope) []string {
+	var path []string
+	for _, scope := range scopes {
+		if scope.kind == "message" {
+			path = append(path, scope.name)
+		}
+	}
+	return path
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func stringField(object map[string]any, key string) string {
+	s, _ := object[key].(string)
+	return s
+}
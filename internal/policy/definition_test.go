@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Avro", func(t *testing.T) {
+		def, err := Parse("", `{
+			"type": "record", "name": "Order", "namespace": "com.example.orders", "doc": "An order.",
+			"fields": [
+				{"name": "id", "type": "long", "doc": "Order ID."},
+				{"name": "placed", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+				{"name": "customer", "type": {"type": "record", "name": "Customer", "fields": [
+					{"name": "email", "type": ["null", "string"], "doc": "Contact."}
+				]}},
+				{"name": "lines", "type": {"type": "array", "items": "string"}}
+			]}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if def.Name != "com.example.orders.Order" || def.Namespace != "com.example.orders" || def.Doc != "An order." {
+			t.Errorf("unexpected definition %+v", def)
+		}
+		expected := []Field{
+			{Path: "id", Type: "long", Doc: "Order ID."},
+			{Path: "placed", Type: "long(timestamp-millis)"},
+			{Path: "customer", Type: "com.example.orders.Customer"},
+			{Path: "customer.email", Type: "union<null,string>", Doc: "Contact."},
+			{Path: "lines", Type: "array<string>"},
+		}
+		if !reflect.DeepEqual(def.Fields, expected) {
+			t.Errorf("expected fields %+v, got %+v", expected, def.Fields)
+		}
+	})
+
+	t.Run("JSON Schema", func(t *testing.T) {
+		def, err := Parse(TypeJSON, `{
+			"$id": "https://example.com/order.json", "title": "Order", "description": "An order.",
+			"type": "object",
+			"properties": {
+				"id": {"type": "integer", "description": "Order ID."},
+				"note": {"type": ["null", "string"]},
+				"lines": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}}
+			}}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if def.Name != "Order" || def.Namespace != "https://example.com/order.json" || def.Doc != "An order." {
+			t.Errorf("unexpected definition %+v", def)
+		}
+		expected := []Field{
+			{Path: "id", Type: "integer", Doc: "Order ID."},
+			{Path: "lines", Type: "array<object>"},
+			{Path: "lines[].sku", Type: "string"},
+			{Path: "note", Type: "null|string"},
+		}
+		if !reflect.DeepEqual(def.Fields, expected) {
+			t.Errorf("expected fields %+v, got %+v", expected, def.Fields)
+		}
+	})
+
+	t.Run("Protobuf", func(t *testing.T) {
+		def, err := Parse(TypeProtobuf, `syntax = "proto3";
+package com.example.orders;
+
+import "google/protobuf/timestamp.proto";
+
+// An order.
+message Order {
+  int64 id = 1; // Order ID.
+  /* When the order was placed. */
+  google.protobuf.Timestamp placed = 2;
+  map<string, int32> quantities = 3;
+  oneof payment {
+    string card = 4;
+  }
+  message Line { string sku = 1; }
+  repeated Line lines = 5;
+  enum Status { UNKNOWN = 0; }
+  reserved 6;
+}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if def.Name != "com.example.orders.Order" || def.Namespace != "com.example.orders" || def.Doc != "An order." {
+			t.Errorf("unexpected definition %+v", def)
+		}
+		expected := []Field{
+			{Path: "Order.id", Type: "int64", Doc: "Order ID."},
+			{Path: "Order.placed", Type: "google.protobuf.Timestamp", Doc: "When the order was placed."},
+			{Path: "Order.quantities", Type: "map<string,int32>"},
+			{Path: "Order.card", Type: "string"},
+			{Path: "Order.Line.sku", Type: "string"},
+			{Path: "Order.lines", Type: "repeated<Line>"},
+		}
+		if !reflect.DeepEqual(def.Fields, expected) {
+			t.Errorf("expected fields %+v, got %+v", expected, def.Fields)
+		}
+	})
+
+	t.Run("invalid definitions", func(t *testing.T) {
+		for schemaType, schema := range map[string]string{
+			TypeAvro:     `{"type": "record"`,
+			TypeJSON:     `[]`,
+			TypeProtobuf: `message Order { int64 id = 1;`,
+			"XML":        `<order/>`,
+		} {
+			if _, err := Parse(schemaType, schema); err == nil {
+				t.Errorf("expected an error for %s", schemaType)
+			}
+		}
+	})
+}
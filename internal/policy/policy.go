@@ -0,0 +1,209 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy checks schema definitions against rules before they are registered: built-in
+// checks and CEL expressions over a format-independent view of Avro, JSON Schema and Protobuf.
+package policy
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+)
+
+// Input is what a policy checks: a schema about to be registered under a subject, and the
+// latest version of the subject when there is one.
+type Input struct {
+	Subject  string
+	Schema   *Definition
+	Previous *Definition
+}
+
+// Violation is a failed check of a Rule.
+type Violation struct {
+	Rule    string
+	Message string
+}
+
+// Rule is a check of a Policy.
+type Rule interface {
+	// Check returns the violations of the rule by the input.
+	Check(in *Input) ([]Violation, error)
+}
+
+// Policy is a set of rules.
+type Policy []Rule
+
+// Evaluate returns the violations of all the rules of the policy, in rule order.
+func (p Policy) Evaluate(in *Input) ([]Violation, error) {
+	var violations []Violation
+	for _, rule := range p {
+		v, err := rule.Check(in)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, v...)
+	}
+	return violations, nil
+}
+
+// RuleFunc adapts a function to a Rule.
+type RuleFunc func(in *Input) ([]Violation, error)
+
+// Check calls f.
+func (f RuleFunc) Check(in *Input) ([]Violation, error) {
+	return f(in)
+}
+
+// RequireDoc requires a doc (Avro), description (JSON Schema) or comment (Protobuf) on the
+// top-level record or message and on every field.
+func RequireDoc() Rule {
+	return RuleFunc(func(in *Input) ([]Violation, error) {
+		var violations []Violation
+		if in.Schema.Doc == "" {
+			violations = append(violations, Violation{Rule: "requireDoc", Message: "the schema has no doc"})
+		}
+		for _, field := range in.Schema.Fields {
+			if field.Doc == "" {
+				violations = append(violations, Violation{Rule: "requireDoc", Message: fmt.Sprintf("field %s has no doc", field.Path)})
+			}
+		}
+		return violations, nil
+	})
+}
+
+// NamespacePattern requires the namespace of the schema to match a regular expression.
+func NamespacePattern(pattern string) (Rule, error) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace pattern: %w", err)
+	}
+	return RuleFunc(func(in *Input) ([]Violation, error) {
+		if re.MatchString(in.Schema.Namespace) {
+			return nil, nil
+		}
+		return []Violation{{Rule: "namespacePattern",
+			Message: fmt.Sprintf("namespace %q does not match %q", in.Schema.Namespace, pattern)}}, nil
+	}), nil
+}
+
+// MaxSize limits the size of the schema definition in bytes.
+func MaxSize(bytes int) Rule {
+	return RuleFunc(func(in *Input) ([]Violation, error) {
+		if in.Schema.Size <= bytes {
+			return nil, nil
+		}
+		return []Violation{{Rule: "maxSchemaBytes",
+			Message: fmt.Sprintf("the schema is %d bytes, more than %d", in.Schema.Size, bytes)}}, nil
+	})
+}
+
+// NoFieldTypeChanges forbids changing the type of a field of the latest version of the subject.
+// Removed and added fields are left to the compatibility level of the registry.
+func NoFieldTypeChanges() Rule {
+	return RuleFunc(func(in *Input) ([]Violation, error) {
+		if in.Previous == nil || in.Previous.Type != in.Schema.Type {
+			return nil, nil
+		}
+		previous := make(map[string]string, len(in.Previous.Fields))
+		for _, field := range in.Previous.Fields {
+			previous[field.Path] = field.Type
+		}
+		var violations []Violation
+		for _, field := range in.Schema.Fields {
+			if typ, ok := previous[field.Path]; ok && typ != field.Type {
+				violations = append(violations, Violation{Rule: "forbidFieldTypeChanges",
+					Message: fmt.Sprintf("field %s changes type from %s to %s", field.Path, typ, field.Type)})
+			}
+		}
+		return violations, nil
+	})
+}
+
+// celCostLimit bounds the evaluation of CEL expressions.
+const celCostLimit = 1000000
+
+// CELRule returns a rule failing with message when the CEL expression is false. The expression
+// sees the variables subject (string), schema and previous (null without a previous version):
+// maps with the type, name, namespace, doc and size of the definition, and its fields as a list
+// of maps with a path, type and doc. The string extension functions (lowerAscii, split...) are
+// available. For example:
+//
+//	schema.fields.all(f, f.path.matches('^[a-z][a-zA-Z0-9.]*$'))
+func CELRule(name, expression, message string) (Rule, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("subject", cel.StringType),
+		cel.Variable("schema", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("previous", cel.DynType),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, issues.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("rule %s: the expression returns %s, not bool", name, ast.OutputType())
+	}
+	program, err := env.Program(ast, cel.CostLimit(celCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", name, err)
+	}
+	if message == "" {
+		message = fmt.Sprintf("the schema fails %s", expression)
+	}
+	return RuleFunc(func(in *Input) ([]Violation, error) {
+		var previous any = types.NullValue
+		if in.Previous != nil {
+			previous = in.Previous.celValue()
+		}
+		out, _, err := program.Eval(map[string]any{
+			"subject":  in.Subject,
+			"schema":   in.Schema.celValue(),
+			"previous": previous,
+		})
+		if err != nil {
+			return []Violation{{Rule: name, Message: fmt.Sprintf("evaluation failed: %v", err)}}, nil
+		}
+		if ok, isBool := out.Value().(bool); !isBool {
+			return nil, fmt.Errorf("rule %s: the expression returned %v, not bool", name, out.Value())
+		} else if !ok {
+			return []Violation{{Rule: name, Message: message}}, nil
+		}
+		return nil, nil
+	}), nil
+}
+
+// celValue returns the definition as seen by CEL expressions.
+func (d *Definition) celValue() map[string]any {
+	fields := make([]any, 0, len(d.Fields))
+	for _, field := range d.Fields {
+		fields = append(fields, map[string]any{"path": field.Path, "type": field.Type, "doc": field.Doc})
+	}
+	return map[string]any{
+		"type":      d.Type,
+		"name":      d.Name,
+		"namespace": d.Namespace,
+		"doc":       d.Doc,
+		"size":      int64(d.Size),
+		"fields":    fields,
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	schema := &Definition{
+		Type: TypeAvro, Name: "com.example.Order", Namespace: "com.example", Size: 120,
+		Fields: []Field{{Path: "id", Type: "long", Doc: "Order ID."}, {Path: "note", Type: "string"}},
+	}
+	previous := &Definition{Type: TypeAvro, Fields: []Field{{Path: "id", Type: "int"}, {Path: "note", Type: "string"}}}
+
+	namespace, err := NamespacePattern(`com\.acme(\..+)?`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cel, err := CELRule("subjectSuffix", "subject.endsWith('-value')", "subjects must end with -value")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	violations, err := Policy{RequireDoc(), namespace, MaxSize(100), NoFieldTypeChanges(), cel}.Evaluate(&Input{
+		Subject: "orders-key", Schema: schema, Previous: previous,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []Violation{
+		{Rule: "requireDoc", Message: "the schema has no doc"},
+		{Rule: "requireDoc", Message: "field note has no doc"},
+		{Rule: "namespacePattern", Message: `namespace "com.example" does not match "com\\.acme(\\..+)?"`},
+		{Rule: "maxSchemaBytes", Message: "the schema is 120 bytes, more than 100"},
+		{Rule: "forbidFieldTypeChanges", Message: "field id changes type from int to long"},
+		{Rule: "subjectSuffix", Message: "subjects must end with -value"},
+	}
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("expected %+v, got %+v", expected, violations)
+	}
+
+	t.Run("compliant schema", func(t *testing.T) {
+		schema := &Definition{Type: TypeAvro, Namespace: "com.acme.orders", Doc: "An order.", Size: 10}
+		violations, err := Policy{RequireDoc(), namespace, MaxSize(100), NoFieldTypeChanges(), cel}.Evaluate(&Input{
+			Subject: "orders-value", Schema: schema,
+		})
+		if err != nil || len(violations) != 0 {
+			t.Errorf("expected no violation, got %+v (%v)", violations, err)
+		}
+	})
+
+	t.Run("CEL expressions", func(t *testing.T) {
+		for _, tc := range []struct {
+			expression string
+			violated   bool
+		}{
+			{"schema.fields.all(f, f.doc != '')", true},
+			{"schema.size < 1000 && schema.type == 'AVRO'", false},
+			{"previous == null || previous.fields.size() <= schema.fields.size()", false},
+			{"schema.fields.exists(f, f.path == 'id' && f.type == 'long')", false},
+		} {
+			rule, err := CELRule("rule", tc.expression, "")
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.expression, err)
+			}
+			violations, err := rule.Check(&Input{Subject: "orders-value", Schema: schema})
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tc.expression, err)
+			}
+			if violated := len(violations) > 0; violated != tc.violated {
+				t.Errorf("%s: expected violated=%t, got %+v", tc.expression, tc.violated, violations)
+			}
+		}
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		if _, err := NamespacePattern("("); err == nil {
+			t.Error("expected an invalid pattern error")
+		}
+		for _, expression := range []string{"schema.", "subject + 'x'"} {
+			if _, err := CELRule("rule", expression, ""); err == nil {
+				t.Errorf("%s: expected a compile error", expression)
+			}
+		}
+	})
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registryclient is a client for the Schema Registry REST API.
+package registryclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	contentType    = "application/vnd.schemaregistry.v1+json"
+	defaultTimeout = 10 * time.Second
+)
+
+// Error codes returned by the registry in the error_code field.
+const (
+	ErrorCodeSubjectNotFound    = 40401
+	ErrorCodeVersionNotFound    = 40402
+	ErrorCodeSchemaNotFound     = 40403
+	ErrorCodeConfigNotFound     = 40408
+	ErrorCodeModeNotFound       = 40409
+	ErrorCodeIncompatibleSchema = 409
+	ErrorCodeInvalidSchema      = 42201
+)
+
+// Config describes how to reach a registry.
+type Config struct {
+	// URL is the base URL of the REST API.
+	URL string
+	// CACert is the PEM bundle trusted for HTTPS. The system roots are used when empty.
+	CACert []byte
+	// ClientCert and ClientKey are the PEM certificate and key presented when the REST API
+	// asks for client certificates.
+	ClientCert []byte
+	ClientKey  []byte
+	// Username and Password are sent with HTTP Basic authentication when Username is set.
+	Username string
+	Password string
+	// Timeout bounds every request. Defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// Client calls the Schema Registry REST API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	username   string
+	password   string
+}
+
+// New returns a client for the registry described by cfg.
+func New(cfg Config) (*Client, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("registry URL is required")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(cfg.CACert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.CACert) {
+			return nil, errors.New("no certificate found in the CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(cfg.ClientCert) > 0 || len(cfg.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.URL, "/"),
+		httpClient: &http.Client{Transport: transport, Timeout: timeout},
+		username:   cfg.Username,
+		password:   cfg.Password,
+	}, nil
+}
+
+// Ping checks that the REST API answers requests, including the authentication of the client.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/", nil, nil)
+}
+
+// Error is an error response of the registry.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry error %d (HTTP %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is a registry "not found" response.
+func IsNotFound(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusNotFound
+}
+
+// IsClientError reports whether err is a request rejected by the registry (HTTP 4xx).
+func IsClientError(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode >= http.StatusBadRequest && e.StatusCode < http.StatusInternalServerError
+}
+
+// IsIncompatible reports whether err rejects a schema as incompatible with the subject.
+func IsIncompatible(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.StatusCode == http.StatusConflict
+}
+
+// IsInvalidSchema reports whether err rejects a schema as invalid.
+func IsInvalidSchema(err error) bool {
+	var e *Error
+	return errors.As(err, &e) && e.Code == ErrorCodeInvalidSchema
+}
+
+// Reference is a reference to another registered schema.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int32  `json:"version"`
+}
+
+// Schema is a schema definition as sent to the registry.
+type Schema struct {
+	Schema string `json:"schema"`
+	// SchemaType is AVRO, JSON or PROTOBUF. The registry assumes AVRO when empty.
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// SubjectVersion is a schema registered under a subject.
+type SubjectVersion struct {
+	Subject    string      `json:"subject"`
+	ID         int32       `json:"id"`
+	Version    int32       `json:"version"`
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// RegisterSchema registers schema under subject and returns its ID. Registering a schema
+// already present under the subject returns the existing ID.
+func (c *Client) RegisterSchema(ctx context.Context, subject string, schema Schema) (int32, error) {
+	var resp struct {
+		ID int32 `json:"id"`
+	}
+	err := c.do(ctx, http.MethodPost, subjectPath(subject, "versions"), schema, &resp)
+	return resp.ID, err
+}
+
+// ImportSchema registers version under subject with its original ID and version number, as
+// done when replaying a backup. The registry only accepts it in IMPORT mode.
+func (c *Client) ImportSchema(ctx context.Context, subject string, version SubjectVersion) (int32, error) {
+	body := struct {
+		Schema
+		ID      int32 `json:"id"`
+		Version int32 `json:"version"`
+	}{
+		Schema:  Schema{Schema: version.Schema, SchemaType: version.SchemaType, References: version.References},
+		ID:      version.ID,
+		Version: version.Version,
+	}
+	var resp struct {
+		ID int32 `json:"id"`
+	}
+	err := c.do(ctx, http.MethodPost, subjectPath(subject, "versions"), body, &resp)
+	return resp.ID, err
+}
+
+// LookupSchema returns the version of subject matching schema.
+func (c *Client) LookupSchema(ctx context.Context, subject string, schema Schema) (*SubjectVersion, error) {
+	resp := &SubjectVersion{}
+	if err := c.do(ctx, http.MethodPost, subjectPath(subject), schema, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CheckCompatibility tests schema against a version of subject ("latest" for the last one), or
+// against the versions selected by the compatibility level of the subject when version is empty.
+// It returns whether the schema is compatible and the reasons when it is not.
+func (c *Client) CheckCompatibility(ctx context.Context, subject, version string, schema Schema) (bool, []string, error) {
+	var resp struct {
+		IsCompatible bool     `json:"is_compatible"`
+		Messages     []string `json:"messages"`
+	}
+	path := "/compatibility/subjects/" + url.PathEscape(subject) + "/versions"
+	if version != "" {
+		path += "/" + url.PathEscape(version)
+	}
+	path += "?verbose=true"
+	if err := c.do(ctx, http.MethodPost, path, schema, &resp); err != nil {
+		return false, nil, err
+	}
+	return resp.IsCompatible, resp.Messages, nil
+}
+
+// Compatibility levels.
+const (
+	CompatibilityNone               = "NONE"
+	CompatibilityBackward           = "BACKWARD"
+	CompatibilityBackwardTransitive = "BACKWARD_TRANSITIVE"
+	CompatibilityForward            = "FORWARD"
+	CompatibilityForwardTransitive  = "FORWARD_TRANSITIVE"
+	CompatibilityFull               = "FULL"
+	CompatibilityFullTransitive     = "FULL_TRANSITIVE"
+)
+
+// Modes.
+const (
+	ModeReadWrite = "READWRITE"
+	ModeReadOnly  = "READONLY"
+	ModeImport    = "IMPORT"
+)
+
+// CompatibilityConfig is the compatibility configuration of a subject or of the registry.
+type CompatibilityConfig struct {
+	Compatibility string `json:"compatibility,omitempty"`
+	Normalize     *bool  `json:"normalize,omitempty"`
+}
+
+// configPath returns the /config or /mode path of subject, or the global one when subject is empty.
+func configPath(resource, subject string) string {
+	if subject == "" {
+		return "/" + resource
+	}
+	return "/" + resource + "/" + url.PathEscape(subject)
+}
+
+// GetConfig returns the configuration set on subject, or the global configuration when subject
+// is empty. A subject without its own configuration returns a not found error.
+func (c *Client) GetConfig(ctx context.Context, subject string) (*CompatibilityConfig, error) {
+	var resp struct {
+		CompatibilityLevel string `json:"compatibilityLevel"`
+		Normalize          *bool  `json:"normalize"`
+	}
+	path := configPath("config", subject)
+	if subject != "" {
+		path += "?defaultToGlobal=false"
+	}
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &CompatibilityConfig{Compatibility: resp.CompatibilityLevel, Normalize: resp.Normalize}, nil
+}
+
+// SetConfig sets the configuration of subject, or the global configuration when subject is empty.
+func (c *Client) SetConfig(ctx context.Context, subject string, config CompatibilityConfig) error {
+	return c.do(ctx, http.MethodPut, configPath("config", subject), config, nil)
+}
+
+// DeleteConfig removes the configuration of subject, which falls back to the global one.
+func (c *Client) DeleteConfig(ctx context.Context, subject string) error {
+	return c.do(ctx, http.MethodDelete, configPath("config", subject), nil, nil)
+}
+
+// GetMode returns the mode set on subject, or the global mode when subject is empty. A subject
+// without its own mode returns a not found error.
+func (c *Client) GetMode(ctx context.Context, subject string) (string, error) {
+	var resp struct {
+		Mode string `json:"mode"`
+	}
+	path := configPath("mode", subject)
+	if subject != "" {
+		path += "?defaultToGlobal=false"
+	}
+	err := c.do(ctx, http.MethodGet, path, nil, &resp)
+	return resp.Mode, err
+}
+
+// SetMode sets the mode of subject, or the global mode when subject is empty.
+func (c *Client) SetMode(ctx context.Context, subject, mode string) error {
+	return c.do(ctx, http.MethodPut, configPath("mode", subject), map[string]string{"mode": mode}, nil)
+}
+
+// DeleteMode removes the mode of subject, which falls back to the global one.
+func (c *Client) DeleteMode(ctx context.Context, subject string) error {
+	return c.do(ctx, http.MethodDelete, configPath("mode", subject), nil, nil)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response into out.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", contentType)
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		regErr := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, regErr) != nil || regErr.Message == "" {
+			regErr.Code = resp.StatusCode
+			regErr.Message = strings.TrimSpace(string(data))
+		}
+		return regErr
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pw, ok := r.BasicAuth(); !ok || user != "admin" || pw != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+			return
+		}
+		var schema Schema
+		_ = json.NewDecoder(r.Body).Decode(&schema)
+		switch r.URL.EscapedPath() {
+		case "/subjects/orders%2Fvalue/versions":
+			if schema.SchemaType != "PROTOBUF" || len(schema.References) != 1 {
+				t.Errorf("unexpected request body: %+v", schema)
+			}
+			_, _ = w.Write([]byte(`{"id":42}`))
+		case "/subjects/orders%2Fvalue":
+			_, _ = w.Write([]byte(`{"subject":"orders/value","id":42,"version":3,"schema":"{}"}`))
+		case "/subjects/payments-value/versions":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error_code":409,"message":"Schema being registered is incompatible"}`))
+		case "/compatibility/subjects/payments-value/versions/latest":
+			if r.URL.Query().Get("verbose") != "true" {
+				t.Error("expected verbose compatibility check")
+			}
+			_, _ = w.Write([]byte(`{"is_compatible":false,"messages":["READER_FIELD_MISSING_DEFAULT_VALUE"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Subject not found."}`))
+		}
+	}))
+	defer server.Close()
+
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	c, err := New(Config{URL: server.URL, CACert: caCert, Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	schema := Schema{Schema: "syntax = \"proto3\";", SchemaType: "PROTOBUF",
+		References: []Reference{{Name: "common.proto", Subject: "common", Version: 1}}}
+
+	t.Run("register and lookup", func(t *testing.T) {
+		id, err := c.RegisterSchema(ctx, "orders/value", schema)
+		if err != nil || id != 42 {
+			t.Fatalf("expected id 42, got %d (err: %v)", id, err)
+		}
+		version, err := c.LookupSchema(ctx, "orders/value", schema)
+		if err != nil || version.Version != 3 || version.ID != 42 {
+			t.Errorf("unexpected lookup result %+v (err: %v)", version, err)
+		}
+	})
+
+	t.Run("errors are decoded", func(t *testing.T) {
+		_, err := c.RegisterSchema(ctx, "payments-value", schema)
+		if !IsIncompatible(err) || IsNotFound(err) {
+			t.Errorf("expected an incompatible schema error, got %v", err)
+		}
+		_, err = c.LookupSchema(ctx, "missing", schema)
+		if !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+
+	t.Run("compatibility check", func(t *testing.T) {
+		ok, messages, err := c.CheckCompatibility(ctx, "payments-value", "latest", schema)
+		if err != nil || ok || len(messages) != 1 {
+			t.Errorf("unexpected compatibility result %v %v (err: %v)", ok, messages, err)
+		}
+	})
+
+	t.Run("untrusted server certificate is rejected", func(t *testing.T) {
+		untrusted, _ := New(Config{URL: server.URL})
+		if _, err := untrusted.RegisterSchema(ctx, "orders/value", schema); err == nil {
+			t.Error("expected a TLS verification error")
+		}
+	})
+}
+
+func TestConfigAndMode(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	c, err := New(Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("global configuration", func(t *testing.T) {
+		config, err := c.GetConfig(ctx, "")
+		if err != nil || config.Compatibility != CompatibilityBackward {
+			t.Errorf("unexpected global configuration %+v (err: %v)", config, err)
+		}
+		if err := c.SetConfig(ctx, "", CompatibilityConfig{Compatibility: CompatibilityFull}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if level, _ := registry.Compatibility(""); level != CompatibilityFull {
+			t.Errorf("expected FULL, got %q", level)
+		}
+	})
+
+	t.Run("subject configuration does not fall back to the global one", func(t *testing.T) {
+		if _, err := c.GetConfig(ctx, "orders-value"); !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+		if err := c.SetConfig(ctx, "orders-value", CompatibilityConfig{Compatibility: CompatibilityNone}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		config, err := c.GetConfig(ctx, "orders-value")
+		if err != nil || config.Compatibility != CompatibilityNone {
+			t.Errorf("unexpected subject configuration %+v (err: %v)", config, err)
+		}
+		if err := c.DeleteConfig(ctx, "orders-value"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := registry.Compatibility("orders-value"); ok {
+			t.Error("expected the subject configuration to be removed")
+		}
+	})
+
+	t.Run("subject mode", func(t *testing.T) {
+		if _, err := c.GetMode(ctx, "orders-value"); !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+		if err := c.SetMode(ctx, "orders-value", ModeReadOnly); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mode, err := c.GetMode(ctx, "orders-value")
+		if err != nil || mode != ModeReadOnly {
+			t.Errorf("expected READONLY, got %q (err: %v)", mode, err)
+		}
+		if err := c.DeleteMode(ctx, "orders-value"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClientCertificate(t *testing.T) {
+	ctx := context.Background()
+	ca, err := testutil.GenerateTestCA()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	user, err := testutil.GenerateTestUserCert(ca, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.CACert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	c, err := New(Config{URL: server.URL, CACert: serverCA,
+		ClientCert: []byte(user.UserCertPEM), ClientKey: []byte(user.UserKeyPEM)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Ping(ctx); err != nil {
+		t.Errorf("expected the client certificate to be accepted, got %v", err)
+	}
+
+	anonymous, _ := New(Config{URL: server.URL, CACert: serverCA})
+	if err := anonymous.Ping(ctx); err == nil {
+		t.Error("expected a TLS error without client certificate")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}); err == nil {
+		t.Error("expected an error without URL")
+	}
+	if _, err := New(Config{URL: "https://registry", CACert: []byte("not a certificate")}); err == nil {
+		t.Error("expected an error for an invalid CA bundle")
+	}
+	if _, err := New(Config{URL: "https://registry", ClientCert: []byte("not a certificate")}); err == nil {
+		t.Error("expected an error for an invalid client certificate")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Exporter context types.
+const (
+	ExporterContextAuto   = "AUTO"
+	ExporterContextCustom = "CUSTOM"
+	ExporterContextNone   = "NONE"
+)
+
+// Exporter states reported by GetExporterStatus.
+const (
+	ExporterStateStarting = "STARTING"
+	ExporterStateRunning  = "RUNNING"
+	ExporterStatePaused   = "PAUSED"
+	ExporterStateError    = "ERROR"
+)
+
+// Exporter copies the schemas of a registry to another one (schema linking).
+type Exporter struct {
+	Name string `json:"name,omitempty"`
+	// ContextType is AUTO, CUSTOM or NONE: where the schemas land in the destination registry.
+	ContextType string `json:"contextType,omitempty"`
+	// Context is the destination context when ContextType is CUSTOM.
+	Context string `json:"context,omitempty"`
+	// Subjects lists the exported subjects; "*" exports them all.
+	Subjects []string `json:"subjects,omitempty"`
+	// SubjectRenameFormat renames the subjects in the destination, e.g. "dc1.${subject}".
+	SubjectRenameFormat string `json:"subjectRenameFormat,omitempty"`
+	// Config holds the client configuration of the destination registry.
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// ExporterStatus is the progress of an exporter.
+type ExporterStatus struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// Offset is the position of the exporter in the schemas topic.
+	Offset int64 `json:"offset"`
+	// Timestamp is the time of the last exported schema, in milliseconds since the epoch.
+	Timestamp int64 `json:"ts"`
+	// Trace is the error that stopped the exporter, if any.
+	Trace string `json:"trace,omitempty"`
+}
+
+// exporterPath returns the path of exporter name, followed by action when set.
+func exporterPath(name, action string) string {
+	path := "/exporters/" + url.PathEscape(name)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+// ListExporters returns the names of the exporters.
+func (c *Client) ListExporters(ctx context.Context) ([]string, error) {
+	var names []string
+	err := c.do(ctx, http.MethodGet, "/exporters", nil, &names)
+	return names, err
+}
+
+// GetExporter returns the exporter called name.
+func (c *Client) GetExporter(ctx context.Context, name string) (*Exporter, error) {
+	resp := &Exporter{}
+	if err := c.do(ctx, http.MethodGet, exporterPath(name, ""), nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CreateExporter creates an exporter, which starts exporting right away.
+func (c *Client) CreateExporter(ctx context.Context, exporter Exporter) error {
+	return c.do(ctx, http.MethodPost, "/exporters", exporter, nil)
+}
+
+// UpdateExporter replaces the settings of the exporter called exporter.Name. The registry only
+// accepts updates of paused exporters.
+func (c *Client) UpdateExporter(ctx context.Context, exporter Exporter) error {
+	name := exporter.Name
+	exporter.Name = ""
+	return c.do(ctx, http.MethodPut, exporterPath(name, ""), exporter, nil)
+}
+
+// DeleteExporter deletes the exporter called name.
+func (c *Client) DeleteExporter(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, exporterPath(name, ""), nil, nil)
+}
+
+// GetExporterStatus returns the progress of the exporter called name.
+func (c *Client) GetExporterStatus(ctx context.Context, name string) (*ExporterStatus, error) {
+	resp := &ExporterStatus{}
+	if err := c.do(ctx, http.MethodGet, exporterPath(name, "status"), nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PauseExporter pauses the exporter called name.
+func (c *Client) PauseExporter(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, exporterPath(name, "pause"), nil, nil)
+}
+
+// ResumeExporter resumes the exporter called name.
+func (c *Client) ResumeExporter(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, exporterPath(name, "resume"), nil, nil)
+}
+
+// ResetExporter restarts the exporter called name from the beginning of the schemas topic.
+func (c *Client) ResetExporter(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPut, exporterPath(name, "reset"), nil, nil)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+)
+
+func TestExporters(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	c, err := New(Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exporter := Exporter{
+		Name:        "to-dr",
+		ContextType: ExporterContextCustom,
+		Context:     "dc1",
+		Subjects:    []string{"*"},
+		Config:      map[string]string{"schema.registry.url": "https://dr-registry:8085"},
+	}
+
+	t.Run("exporter is created and read", func(t *testing.T) {
+		if err := c.CreateExporter(ctx, exporter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		names, err := c.ListExporters(ctx)
+		if err != nil || !slices.Equal(names, []string{"to-dr"}) {
+			t.Errorf("unexpected exporters %v (err: %v)", names, err)
+		}
+		got, err := c.GetExporter(ctx, "to-dr")
+		if err != nil || got.Context != "dc1" || got.Config["schema.registry.url"] != "https://dr-registry:8085" {
+			t.Errorf("unexpected exporter %+v (err: %v)", got, err)
+		}
+	})
+
+	t.Run("status reports the progress", func(t *testing.T) {
+		registry.SetExporterProgress("to-dr", ExporterStateRunning, 42)
+		status, err := c.GetExporterStatus(ctx, "to-dr")
+		if err != nil || status.State != ExporterStateRunning || status.Offset != 42 {
+			t.Errorf("unexpected status %+v (err: %v)", status, err)
+		}
+	})
+
+	t.Run("exporter is updated while paused", func(t *testing.T) {
+		exporter.Subjects = []string{"orders-value"}
+		if err := c.UpdateExporter(ctx, exporter); !IsClientError(err) {
+			t.Errorf("expected running exporters to reject updates, got %v", err)
+		}
+		if err := c.PauseExporter(ctx, "to-dr"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.UpdateExporter(ctx, exporter); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.ResetExporter(ctx, "to-dr"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := c.ResumeExporter(ctx, "to-dr"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stored, _ := registry.Exporter("to-dr")
+		if !slices.Equal(stored.Subjects, []string{"orders-value"}) || stored.State != ExporterStateRunning || stored.Offset != 0 {
+			t.Errorf("unexpected exporter %+v", stored)
+		}
+	})
+
+	t.Run("exporter is deleted", func(t *testing.T) {
+		if err := c.DeleteExporter(ctx, "to-dr"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := c.GetExporter(ctx, "to-dr"); !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+}
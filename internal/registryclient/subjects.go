@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// LatestVersion addresses the last version of a subject.
+const LatestVersion = "latest"
+
+// SchemaByID is a schema fetched by its global ID.
+type SchemaByID struct {
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// subjectPath returns the path of subject, followed by the escaped elements of rest.
+func subjectPath(subject string, rest ...string) string {
+	path := "/subjects/" + url.PathEscape(subject)
+	for _, element := range rest {
+		path += "/" + url.PathEscape(element)
+	}
+	return path
+}
+
+// withQuery appends the non-empty query parameters to path.
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// deletedQuery returns the query asking for soft-deleted entries too when deleted is set.
+func deletedQuery(deleted bool) url.Values {
+	query := url.Values{}
+	if deleted {
+		query.Set("deleted", "true")
+	}
+	return query
+}
+
+// ListSubjects returns the subjects starting with prefix (all of them when empty), including
+// soft-deleted ones when deleted is set.
+func (c *Client) ListSubjects(ctx context.Context, prefix string, deleted bool) ([]string, error) {
+	query := deletedQuery(deleted)
+	if prefix != "" {
+		query.Set("subjectPrefix", prefix)
+	}
+	var subjects []string
+	err := c.do(ctx, http.MethodGet, withQuery("/subjects", query), nil, &subjects)
+	return subjects, err
+}
+
+// DeleteSubject deletes subject and returns its deleted versions. A soft delete keeps the
+// schemas readable by ID; a permanent delete requires a soft delete first.
+func (c *Client) DeleteSubject(ctx context.Context, subject string, permanent bool) ([]int32, error) {
+	query := url.Values{}
+	if permanent {
+		query.Set("permanent", "true")
+	}
+	var versions []int32
+	err := c.do(ctx, http.MethodDelete, withQuery(subjectPath(subject), query), nil, &versions)
+	return versions, err
+}
+
+// ListVersions returns the versions registered under subject, including soft-deleted ones when
+// deleted is set.
+func (c *Client) ListVersions(ctx context.Context, subject string, deleted bool) ([]int32, error) {
+	var versions []int32
+	err := c.do(ctx, http.MethodGet, withQuery(subjectPath(subject, "versions"), deletedQuery(deleted)), nil, &versions)
+	return versions, err
+}
+
+// GetVersion returns a version of subject: a version number or LatestVersion. Soft-deleted
+// versions are only returned when deleted is set.
+func (c *Client) GetVersion(ctx context.Context, subject, version string, deleted bool) (*SubjectVersion, error) {
+	resp := &SubjectVersion{}
+	path := withQuery(subjectPath(subject, "versions", version), deletedQuery(deleted))
+	if err := c.do(ctx, http.MethodGet, path, nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteVersion deletes a version of subject and returns its number.
+func (c *Client) DeleteVersion(ctx context.Context, subject, version string, permanent bool) (int32, error) {
+	query := url.Values{}
+	if permanent {
+		query.Set("permanent", "true")
+	}
+	var deleted int32
+	err := c.do(ctx, http.MethodDelete, withQuery(subjectPath(subject, "versions", version), query), nil, &deleted)
+	return deleted, err
+}
+
+// GetSchemaByID returns the schema registered with a global ID.
+func (c *Client) GetSchemaByID(ctx context.Context, id int32) (*SchemaByID, error) {
+	resp := &SchemaByID{}
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(int(id)), nil, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListContexts returns the contexts of the registry, "." being the default one.
+func (c *Client) ListContexts(ctx context.Context) ([]string, error) {
+	var contexts []string
+	err := c.do(ctx, http.MethodGet, "/contexts", nil, &contexts)
+	return contexts, err
+}
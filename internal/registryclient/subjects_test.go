@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registryclient
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+)
+
+func TestSubjects(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	c, err := New(Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order := Schema{Schema: `{"type":"string"}`}
+	orderV2 := Schema{Schema: `{"type":"int"}`}
+	customer := Schema{Schema: `syntax = "proto3";`, SchemaType: "PROTOBUF",
+		References: []Reference{{Name: "order.avsc", Subject: "orders-value", Version: 1}}}
+	for _, register := range []struct {
+		subject string
+		schema  Schema
+	}{{"orders-value", order}, {"orders-value", orderV2}, {"customers-value", customer}, {":.team-a:payments-value", order}} {
+		if _, err := c.RegisterSchema(ctx, register.subject, register.schema); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("subjects are listed by prefix", func(t *testing.T) {
+		subjects, err := c.ListSubjects(ctx, "", false)
+		if err != nil || len(subjects) != 3 {
+			t.Errorf("unexpected subjects %v (err: %v)", subjects, err)
+		}
+		subjects, err = c.ListSubjects(ctx, "orders", false)
+		if err != nil || !slices.Equal(subjects, []string{"orders-value"}) {
+			t.Errorf("unexpected subjects %v (err: %v)", subjects, err)
+		}
+	})
+
+	t.Run("versions are listed and read", func(t *testing.T) {
+		versions, err := c.ListVersions(ctx, "orders-value", false)
+		if err != nil || !slices.Equal(versions, []int32{1, 2}) {
+			t.Errorf("unexpected versions %v (err: %v)", versions, err)
+		}
+		latest, err := c.GetVersion(ctx, "orders-value", LatestVersion, false)
+		if err != nil || latest.Version != 2 || latest.Schema != orderV2.Schema {
+			t.Errorf("unexpected latest version %+v (err: %v)", latest, err)
+		}
+		withRefs, err := c.GetVersion(ctx, "customers-value", "1", false)
+		if err != nil || withRefs.SchemaType != "PROTOBUF" || len(withRefs.References) != 1 {
+			t.Errorf("unexpected version %+v (err: %v)", withRefs, err)
+		}
+		if _, err := c.GetVersion(ctx, "orders-value", "7", false); !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+
+	t.Run("schema is read by ID", func(t *testing.T) {
+		schema, err := c.GetSchemaByID(ctx, 1)
+		if err != nil || schema.Schema != order.Schema {
+			t.Errorf("unexpected schema %+v (err: %v)", schema, err)
+		}
+	})
+
+	t.Run("contexts are listed", func(t *testing.T) {
+		contexts, err := c.ListContexts(ctx)
+		if err != nil || !slices.Equal(contexts, []string{".", ".team-a"}) {
+			t.Errorf("unexpected contexts %v (err: %v)", contexts, err)
+		}
+	})
+
+	t.Run("versions and subjects are deleted", func(t *testing.T) {
+		deleted, err := c.DeleteVersion(ctx, "orders-value", "1", false)
+		if err != nil || deleted != 1 {
+			t.Errorf("unexpected deleted version %d (err: %v)", deleted, err)
+		}
+		if versions, err := c.ListVersions(ctx, "orders-value", false); err != nil || !slices.Equal(versions, []int32{2}) {
+			t.Errorf("unexpected live versions %v (err: %v)", versions, err)
+		}
+		if versions, err := c.ListVersions(ctx, "orders-value", true); err != nil || !slices.Equal(versions, []int32{1, 2}) {
+			t.Errorf("soft-deleted versions must be listed with deleted=true, got %v (err: %v)", versions, err)
+		}
+		if _, err := c.GetVersion(ctx, "orders-value", "1", false); !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+		if version, err := c.GetVersion(ctx, "orders-value", "1", true); err != nil || version.Version != 1 {
+			t.Errorf("soft-deleted versions must be read with deleted=true, got %+v (err: %v)", version, err)
+		}
+		versions, err := c.DeleteSubject(ctx, "orders-value", false)
+		if err != nil || !slices.Equal(versions, []int32{2}) {
+			t.Errorf("unexpected deleted versions %v (err: %v)", versions, err)
+		}
+		subjects, _ := c.ListSubjects(ctx, "orders", true)
+		if !slices.Equal(subjects, []string{"orders-value"}) {
+			t.Errorf("soft-deleted subjects must be listed with deleted=true, got %v", subjects)
+		}
+		if _, err := c.DeleteSubject(ctx, "orders-value", true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if subjects, _ := c.ListSubjects(ctx, "orders", true); len(subjects) != 0 {
+			t.Errorf("expected the subject to be deleted permanently, got %v", subjects)
+		}
+	})
+	t.Run("compatibility is checked against the versions of the subject", func(t *testing.T) {
+		registry.Incompatible = func(_, schema string) bool { return schema == `{"type":"boolean"}` }
+		defer func() { registry.Incompatible = nil }()
+		if _, err := c.RegisterSchema(ctx, "shipments-value", order); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ok, messages, err := c.CheckCompatibility(ctx, "shipments-value", "", Schema{Schema: `{"type":"boolean"}`})
+		if err != nil || ok || len(messages) == 0 {
+			t.Errorf("expected an incompatible result, got %v %v (err: %v)", ok, messages, err)
+		}
+		ok, _, err = c.CheckCompatibility(ctx, "new-value", "", Schema{Schema: `{"type":"boolean"}`})
+		if err != nil || !ok {
+			t.Errorf("expected a new subject to be compatible, got %v (err: %v)", ok, err)
+		}
+		if _, _, err := c.CheckCompatibility(ctx, "new-value", "latest", order); !IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+	})
+}
+
+func TestImportSchema(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	c, err := New(Config{URL: registry.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	version := SubjectVersion{ID: 42, Version: 3, Schema: `{"type":"string"}`}
+
+	if _, err := c.ImportSchema(ctx, "orders-value", version); !IsClientError(err) {
+		t.Errorf("expected the import to be rejected outside of IMPORT mode, got %v", err)
+	}
+	if err := c.SetMode(ctx, "", ModeImport); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id, err := c.ImportSchema(ctx, "orders-value", version)
+	if err != nil || id != 42 {
+		t.Fatalf("expected ID 42, got %d (err: %v)", id, err)
+	}
+	imported, err := c.GetVersion(ctx, "orders-value", "3", false)
+	if err != nil || imported.ID != 42 {
+		t.Errorf("unexpected imported version %+v (err: %v)", imported, err)
+	}
+}
@@ -0,0 +1,576 @@
+package testutil
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SchemaReference is a reference of a schema stored by FakeRegistry.
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int32  `json:"version"`
+}
+
+// RegisteredSchema is a schema version stored by FakeRegistry.
+type RegisteredSchema struct {
+	ID         int32
+	Version    int32
+	Schema     string
+	SchemaType string
+	References []SchemaReference
+	// Deleted marks a soft-deleted version.
+	Deleted bool
+}
+
+// FakeExporter is an exporter stored by FakeRegistry.
+type FakeExporter struct {
+	Name                string            `json:"name"`
+	ContextType         string            `json:"contextType,omitempty"`
+	Context             string            `json:"context,omitempty"`
+	Subjects            []string          `json:"subjects,omitempty"`
+	SubjectRenameFormat string            `json:"subjectRenameFormat,omitempty"`
+	Config              map[string]string `json:"config,omitempty"`
+
+	// State and Offset are reported by the status endpoint.
+	State  string `json:"-"`
+	Offset int64  `json:"-"`
+}
+
+// FakeRegistry is an in-memory Schema Registry REST API for tests.
+type FakeRegistry struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	subjects map[string][]RegisteredSchema
+	nextID   int32
+	// configs and modes hold the compatibility level and mode per subject; "" is the global one.
+	configs   map[string]string
+	modes     map[string]string
+	exporters map[string]*FakeExporter
+
+	// Incompatible, when set, rejects the registration of schema under subject with HTTP 409.
+	Incompatible func(subject, schema string) bool
+	// NoExporters answers 404 on /exporters, like registries without schema linking.
+	NoExporters bool
+}
+
+// NewFakeRegistry starts a plain HTTP fake registry. Close it when done.
+func NewFakeRegistry() *FakeRegistry {
+	f := &FakeRegistry{
+		subjects:  map[string][]RegisteredSchema{},
+		nextID:    1,
+		configs:   map[string]string{"": "BACKWARD"},
+		modes:     map[string]string{"": "READWRITE"},
+		exporters: map[string]*FakeExporter{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	return f
+}
+
+// Versions returns the versions registered under subject, soft-deleted ones included.
+func (f *FakeRegistry) Versions(subject string) []RegisteredSchema {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]RegisteredSchema(nil), f.subjects[subject]...)
+}
+
+// DeleteSubject removes a subject, as a user deleting it by hand would.
+func (f *FakeRegistry) DeleteSubject(subject string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.subjects, subject)
+}
+
+// Compatibility returns the compatibility level set on subject ("" for the global one).
+func (f *FakeRegistry) Compatibility(subject string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	level, ok := f.configs[subject]
+	return level, ok
+}
+
+// SetCompatibility sets the compatibility level of subject, as a user calling the API would.
+func (f *FakeRegistry) SetCompatibility(subject, level string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.configs[subject] = level
+}
+
+// Mode returns the mode set on subject ("" for the global one).
+func (f *FakeRegistry) Mode(subject string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	mode, ok := f.modes[subject]
+	return mode, ok
+}
+
+// SetMode sets the mode of subject, as a user calling the API would.
+func (f *FakeRegistry) SetMode(subject, mode string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modes[subject] = mode
+}
+
+// Exporter returns a copy of the exporter called name.
+func (f *FakeRegistry) Exporter(name string) (FakeExporter, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	exporter, ok := f.exporters[name]
+	if !ok {
+		return FakeExporter{}, false
+	}
+	return *exporter, true
+}
+
+// SetExporterProgress sets the state and offset reported for the exporter called name.
+func (f *FakeRegistry) SetExporterProgress(name, state string, offset int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if exporter, ok := f.exporters[name]; ok {
+		exporter.State, exporter.Offset = state, offset
+	}
+}
+
+type schemaRequest struct {
+	Schema        string            `json:"schema"`
+	SchemaType    string            `json:"schemaType"`
+	References    []SchemaReference `json:"references"`
+	Compatibility string            `json:"compatibility"`
+	Mode          string            `json:"mode"`
+	// ID and Version are set when importing schemas.
+	ID      int32 `json:"id"`
+	Version int32 `json:"version"`
+}
+
+func (f *FakeRegistry) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.Trim(r.URL.EscapedPath(), "/")
+	parts := strings.Split(path, "/")
+	for i := range parts {
+		parts[i], _ = url.PathUnescape(parts[i])
+	}
+	data, _ := io.ReadAll(r.Body)
+	var body schemaRequest
+	_ = json.Unmarshal(data, &body)
+	if body.SchemaType == "" {
+		body.SchemaType = "AVRO"
+	}
+	get, query := r.Method == http.MethodGet, r.URL.Query()
+
+	switch {
+	case path == "" && get:
+		writeRegistryJSON(w, map[string]string{})
+	case parts[0] == "config" && len(parts) <= 2:
+		f.serveSetting(w, r, f.configs, parts, body.Compatibility, "compatibilityLevel", "compatibility", 40408)
+	case parts[0] == "mode" && len(parts) <= 2 && r.Method == http.MethodPut && body.Mode == "IMPORT" && f.hasSchemas(parts[1:]):
+		writeRegistryError(w, http.StatusUnprocessableEntity, 42205, "Cannot import since found existing subjects")
+	case parts[0] == "mode" && len(parts) <= 2:
+		f.serveSetting(w, r, f.modes, parts, body.Mode, "mode", "mode", 40409)
+	case parts[0] == "contexts" && len(parts) == 1 && get:
+		writeRegistryJSON(w, f.contexts())
+	case parts[0] == "schemas" && len(parts) == 3 && parts[1] == "ids" && get:
+		f.schemaByID(w, parts[2])
+	case parts[0] == "compatibility" && (len(parts) == 4 || len(parts) == 5) && parts[1] == "subjects" &&
+		parts[3] == "versions" && r.Method == http.MethodPost:
+		f.checkCompatibility(w, parts[2], len(parts) == 5, body)
+	case parts[0] == "exporters" && !f.NoExporters:
+		f.serveExporters(w, r, parts, data)
+	case parts[0] == "subjects" && len(parts) == 1 && get:
+		writeRegistryJSON(w, f.subjectNames(query.Get("subjectPrefix"), query.Get("deleted") == "true"))
+	case parts[0] == "subjects" && len(parts) == 2 && r.Method == http.MethodPost:
+		f.lookup(w, parts[1], body)
+	case parts[0] == "subjects" && len(parts) == 2 && r.Method == http.MethodDelete:
+		f.deleteVersions(w, parts[1], "", query.Get("permanent") == "true")
+	case parts[0] == "subjects" && len(parts) == 3 && parts[2] == "versions" && r.Method == http.MethodPost:
+		f.register(w, parts[1], body)
+	case parts[0] == "subjects" && len(parts) == 3 && parts[2] == "versions" && get:
+		f.listVersions(w, parts[1], query.Get("deleted") == "true")
+	case parts[0] == "subjects" && len(parts) == 4 && parts[2] == "versions" && get:
+		f.getVersion(w, parts[1], parts[3], query.Get("deleted") == "true")
+	case parts[0] == "subjects" && len(parts) == 4 && parts[2] == "versions" && r.Method == http.MethodDelete:
+		f.deleteVersions(w, parts[1], parts[3], query.Get("permanent") == "true")
+	default:
+		writeRegistryError(w, http.StatusNotFound, 404, "HTTP 404 Not Found")
+	}
+}
+
+// serveSetting serves GET, PUT and DELETE on /config and /mode, globally or for a subject.
+func (f *FakeRegistry) serveSetting(w http.ResponseWriter, r *http.Request, settings map[string]string,
+	parts []string, value, getField, putField string, notFoundCode int) {
+	subject := ""
+	if len(parts) == 2 {
+		subject = parts[1]
+	}
+	switch r.Method {
+	case http.MethodGet:
+		current, ok := settings[subject]
+		if !ok && r.URL.Query().Get("defaultToGlobal") != "true" {
+			writeRegistryError(w, http.StatusNotFound, notFoundCode, "Subject '"+subject+"' does not have subject-level setting")
+			return
+		}
+		if !ok {
+			current = settings[""]
+		}
+		writeRegistryJSON(w, map[string]string{getField: current})
+	case http.MethodPut:
+		if value == "" {
+			// A request without the setting (e.g. normalize only) keeps the current value.
+			value = settings[subject]
+		}
+		if value != "" {
+			settings[subject] = value
+		}
+		writeRegistryJSON(w, map[string]string{putField: value})
+	case http.MethodDelete:
+		current, ok := settings[subject]
+		if !ok || subject == "" {
+			writeRegistryError(w, http.StatusNotFound, notFoundCode, "Subject '"+subject+"' does not have subject-level setting")
+			return
+		}
+		delete(settings, subject)
+		writeRegistryJSON(w, map[string]string{getField: current})
+	default:
+		writeRegistryError(w, http.StatusMethodNotAllowed, 405, "HTTP 405 Method Not Allowed")
+	}
+}
+
+// hasSchemas reports whether the subject in parts, or any subject when parts is empty, has live versions.
+func (f *FakeRegistry) hasSchemas(parts []string) bool {
+	if len(parts) == 1 {
+		return len(f.live(parts[0])) > 0
+	}
+	for subject := range f.subjects {
+		if len(f.live(subject)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// mode returns the mode applying to subject.
+func (f *FakeRegistry) mode(subject string) string {
+	if mode, ok := f.modes[subject]; ok {
+		return mode
+	}
+	return f.modes[""]
+}
+
+// live returns the versions of subject that are not soft-deleted.
+func (f *FakeRegistry) live(subject string) []RegisteredSchema {
+	var versions []RegisteredSchema
+	for _, version := range f.subjects[subject] {
+		if !version.Deleted {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+func (f *FakeRegistry) register(w http.ResponseWriter, subject string, body schemaRequest) {
+	if strings.TrimSpace(body.Schema) == "" {
+		writeRegistryError(w, http.StatusUnprocessableEntity, 42201, "Invalid schema")
+		return
+	}
+	for _, existing := range f.live(subject) {
+		if existing.Schema == body.Schema && existing.SchemaType == body.SchemaType {
+			writeRegistryJSON(w, map[string]int32{"id": existing.ID})
+			return
+		}
+	}
+	if body.ID != 0 {
+		f.importSchema(w, subject, body)
+		return
+	}
+	if f.Incompatible != nil && f.Incompatible(subject, body.Schema) {
+		writeRegistryError(w, http.StatusConflict, 409, "Schema being registered is incompatible with an earlier schema")
+		return
+	}
+	id := f.nextID
+	f.nextID++
+	f.subjects[subject] = append(f.subjects[subject], RegisteredSchema{
+		ID: id, Version: int32(len(f.subjects[subject]) + 1), Schema: body.Schema, SchemaType: body.SchemaType,
+		References: body.References,
+	})
+	writeRegistryJSON(w, map[string]int32{"id": id})
+}
+
+// checkCompatibility serves the compatibility check of a schema against the versions of subject,
+// or one of them when version is set, using Incompatible.
+func (f *FakeRegistry) checkCompatibility(w http.ResponseWriter, subject string, version bool, body schemaRequest) {
+	if len(f.live(subject)) == 0 && version {
+		writeRegistryError(w, http.StatusNotFound, 40401, "Subject '"+subject+"' not found.")
+		return
+	}
+	if len(f.live(subject)) > 0 && f.Incompatible != nil && f.Incompatible(subject, body.Schema) {
+		writeRegistryJSON(w, map[string]any{
+			"is_compatible": false,
+			"messages":      []string{"Schema being registered is incompatible with an earlier schema"},
+		})
+		return
+	}
+	writeRegistryJSON(w, map[string]any{"is_compatible": true})
+}
+
+// importSchema registers a schema with the ID and version of the request, as in IMPORT mode.
+func (f *FakeRegistry) importSchema(w http.ResponseWriter, subject string, body schemaRequest) {
+	if f.mode(subject) != "IMPORT" {
+		writeRegistryError(w, http.StatusUnprocessableEntity, 42205, "Subject "+subject+" is not in import mode")
+		return
+	}
+	version := body.Version
+	if version == 0 {
+		version = int32(len(f.subjects[subject]) + 1)
+	}
+	f.subjects[subject] = append(f.subjects[subject], RegisteredSchema{
+		ID: body.ID, Version: version, Schema: body.Schema, SchemaType: body.SchemaType, References: body.References,
+	})
+	if body.ID >= f.nextID {
+		f.nextID = body.ID + 1
+	}
+	writeRegistryJSON(w, map[string]int32{"id": body.ID})
+}
+
+func (f *FakeRegistry) lookup(w http.ResponseWriter, subject string, body schemaRequest) {
+	versions := f.live(subject)
+	if len(versions) == 0 {
+		writeRegistryError(w, http.StatusNotFound, 40401, "Subject '"+subject+"' not found.")
+		return
+	}
+	for _, existing := range versions {
+		if existing.Schema == body.Schema && existing.SchemaType == body.SchemaType {
+			writeRegistryJSON(w, versionJSON(subject, existing))
+			return
+		}
+	}
+	writeRegistryError(w, http.StatusNotFound, 40403, "Schema not found")
+}
+
+// subjectNames returns the sorted subjects starting with prefix.
+func (f *FakeRegistry) subjectNames(prefix string, deleted bool) []string {
+	names := []string{}
+	for subject, versions := range f.subjects {
+		if !strings.HasPrefix(subject, prefix) || len(versions) == 0 {
+			continue
+		}
+		if deleted || len(f.live(subject)) > 0 {
+			names = append(names, subject)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// versions returns the versions of subject, soft-deleted ones included when deleted is set.
+func (f *FakeRegistry) versions(subject string, deleted bool) []RegisteredSchema {
+	if deleted {
+		return f.subjects[subject]
+	}
+	return f.live(subject)
+}
+
+func (f *FakeRegistry) listVersions(w http.ResponseWriter, subject string, deleted bool) {
+	versions := f.versions(subject, deleted)
+	if len(versions) == 0 {
+		writeRegistryError(w, http.StatusNotFound, 40401, "Subject '"+subject+"' not found.")
+		return
+	}
+	numbers := make([]int32, 0, len(versions))
+	for _, version := range versions {
+		numbers = append(numbers, version.Version)
+	}
+	writeRegistryJSON(w, numbers)
+}
+
+// findVersion returns the version of subject addressed by a number or "latest", only among the
+// live ones unless deleted is set.
+func (f *FakeRegistry) findVersion(w http.ResponseWriter, subject, version string, deleted bool) (RegisteredSchema, bool) {
+	versions := f.versions(subject, deleted)
+	if len(versions) == 0 {
+		writeRegistryError(w, http.StatusNotFound, 40401, "Subject '"+subject+"' not found.")
+		return RegisteredSchema{}, false
+	}
+	if version == "latest" || version == "-1" {
+		return versions[len(versions)-1], true
+	}
+	number, _ := strconv.Atoi(version)
+	for _, existing := range versions {
+		if existing.Version == int32(number) {
+			return existing, true
+		}
+	}
+	writeRegistryError(w, http.StatusNotFound, 40402, "Version "+version+" not found.")
+	return RegisteredSchema{}, false
+}
+
+func (f *FakeRegistry) getVersion(w http.ResponseWriter, subject, version string, deleted bool) {
+	if existing, ok := f.findVersion(w, subject, version, deleted); ok {
+		writeRegistryJSON(w, versionJSON(subject, existing))
+	}
+}
+
+// deleteVersions deletes a version of subject, or all of them when version is empty.
+func (f *FakeRegistry) deleteVersions(w http.ResponseWriter, subject, version string, permanent bool) {
+	var targets []int32
+	if version != "" {
+		existing, ok := f.findVersion(w, subject, version, false)
+		if !ok {
+			return
+		}
+		targets = []int32{existing.Version}
+	} else {
+		for _, existing := range f.subjects[subject] {
+			if permanent || !existing.Deleted {
+				targets = append(targets, existing.Version)
+			}
+		}
+		if len(targets) == 0 {
+			writeRegistryError(w, http.StatusNotFound, 40401, "Subject '"+subject+"' not found.")
+			return
+		}
+	}
+	kept := f.subjects[subject][:0]
+	for _, existing := range f.subjects[subject] {
+		if slices.Contains(targets, existing.Version) {
+			if permanent {
+				continue
+			}
+			existing.Deleted = true
+		}
+		kept = append(kept, existing)
+	}
+	f.subjects[subject] = kept
+	if version != "" {
+		writeRegistryJSON(w, targets[0])
+		return
+	}
+	writeRegistryJSON(w, targets)
+}
+
+func (f *FakeRegistry) schemaByID(w http.ResponseWriter, id string) {
+	number, _ := strconv.Atoi(id)
+	for _, versions := range f.subjects {
+		for _, existing := range versions {
+			if existing.ID == int32(number) {
+				writeRegistryJSON(w, map[string]any{
+					"schema": existing.Schema, "schemaType": existing.SchemaType, "references": existing.References,
+				})
+				return
+			}
+		}
+	}
+	writeRegistryError(w, http.StatusNotFound, 40403, "Schema "+id+" not found")
+}
+
+// contexts returns the default context and the contexts of the ":.<context>:" prefixed subjects.
+func (f *FakeRegistry) contexts() []string {
+	contexts := []string{"."}
+	for subject := range f.subjects {
+		if rest, ok := strings.CutPrefix(subject, ":."); ok {
+			if name, _, found := strings.Cut(rest, ":"); found && !slices.Contains(contexts, "."+name) {
+				contexts = append(contexts, "."+name)
+			}
+		}
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
+// serveExporters serves /exporters and /exporters/{name}[/status|pause|resume|reset].
+func (f *FakeRegistry) serveExporters(w http.ResponseWriter, r *http.Request, parts []string, data []byte) {
+	if len(parts) == 1 {
+		switch r.Method {
+		case http.MethodGet:
+			names := []string{}
+			for name := range f.exporters {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			writeRegistryJSON(w, names)
+		case http.MethodPost:
+			exporter := &FakeExporter{}
+			if err := json.Unmarshal(data, exporter); err != nil || exporter.Name == "" {
+				writeRegistryError(w, http.StatusUnprocessableEntity, 42251, "Invalid exporter")
+				return
+			}
+			if _, ok := f.exporters[exporter.Name]; ok {
+				writeRegistryError(w, http.StatusConflict, 40950, "Exporter "+exporter.Name+" already exists")
+				return
+			}
+			exporter.State = "RUNNING"
+			f.exporters[exporter.Name] = exporter
+			writeRegistryJSON(w, map[string]string{"name": exporter.Name})
+		default:
+			writeRegistryError(w, http.StatusMethodNotAllowed, 405, "HTTP 405 Method Not Allowed")
+		}
+		return
+	}
+
+	exporter, ok := f.exporters[parts[1]]
+	if !ok {
+		writeRegistryError(w, http.StatusNotFound, 40450, "Exporter "+parts[1]+" not found")
+		return
+	}
+	action := ""
+	if len(parts) == 3 {
+		action = parts[2]
+	}
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeRegistryJSON(w, exporter)
+	case action == "" && r.Method == http.MethodPut:
+		if exporter.State != "PAUSED" {
+			writeRegistryError(w, http.StatusConflict, 40951, "Exporter "+exporter.Name+" must be paused to be updated")
+			return
+		}
+		updated := &FakeExporter{}
+		_ = json.Unmarshal(data, updated)
+		updated.Name, updated.State, updated.Offset = exporter.Name, exporter.State, exporter.Offset
+		f.exporters[exporter.Name] = updated
+		writeRegistryJSON(w, map[string]string{"name": exporter.Name})
+	case action == "" && r.Method == http.MethodDelete:
+		delete(f.exporters, exporter.Name)
+		writeRegistryJSON(w, map[string]string{})
+	case action == "status" && r.Method == http.MethodGet:
+		writeRegistryJSON(w, map[string]any{"name": exporter.Name, "state": exporter.State, "offset": exporter.Offset})
+	case action == "pause" && r.Method == http.MethodPut:
+		exporter.State = "PAUSED"
+		writeRegistryJSON(w, map[string]string{"name": exporter.Name})
+	case action == "resume" && r.Method == http.MethodPut:
+		exporter.State = "RUNNING"
+		writeRegistryJSON(w, map[string]string{"name": exporter.Name})
+	case action == "reset" && r.Method == http.MethodPut:
+		exporter.Offset = 0
+		writeRegistryJSON(w, map[string]string{"name": exporter.Name})
+	default:
+		writeRegistryError(w, http.StatusNotFound, 404, "HTTP 404 Not Found")
+	}
+}
+
+func versionJSON(subject string, version RegisteredSchema) map[string]any {
+	return map[string]any{
+		"subject": subject, "id": version.ID, "version": version.Version,
+		"schema": version.Schema, "schemaType": version.SchemaType, "references": version.References,
+	}
+}
+
+func writeRegistryJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeRegistryError(w http.ResponseWriter, status, code int, message string) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error_code": code, "message": message})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/randsw/schema-registry-operator-strimzi/internal/controller"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var podlog = logf.Log.WithName("pod-resource")
+
+const (
+	// InjectAnnotation on a pod names the StrimziSchemaRegistry whose connection settings
+	// are injected. The registry connection ConfigMap and Secret must exist in the pod
+	// namespace: either the registry lives there or they were copied by spec.connection.
+	InjectAnnotation = "strimziregistryoperator.randsw.code/inject"
+	// injectedAnnotation records the registry that was injected.
+	injectedAnnotation = "strimziregistryoperator.randsw.code/injected"
+
+	connectionVolumeName = "schema-registry-connection"
+	connectionMountPath  = "/etc/schema-registry"
+)
+
+// SetupPodWebhookWithManager registers the webhook injecting registry connection settings into pods.
+func SetupPodWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1.Pod{}).
+		WithDefaulter(&PodCustomDefaulter{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.strimziregistryoperator.randsw.code,admissionReviewVersions=v1
+
+// PodCustomDefaulter injects SCHEMA_REGISTRY_URL, the CA truststore and the Basic
+// authentication credentials of a registry into pods annotated with InjectAnnotation.
+type PodCustomDefaulter struct {
+	Client client.Reader
+}
+
+// Default implements admission.Defaulter.
+func (d *PodCustomDefaulter) Default(ctx context.Context, pod *corev1.Pod) error {
+	registry := pod.Annotations[InjectAnnotation]
+	if registry == "" {
+		return nil
+	}
+	// Pods created by controllers have no namespace yet; it is set on the request.
+	namespace := pod.Namespace
+	if namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			namespace = req.Namespace
+		}
+	}
+
+	name := controller.ConnectionName(registry)
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	if err := d.Client.Get(ctx, key, &corev1.ConfigMap{}); err != nil {
+		return fmt.Errorf("connection settings of Schema Registry %q not found in namespace %s: %w", registry, namespace, err)
+	}
+	secret := &corev1.Secret{}
+	if err := d.Client.Get(ctx, key, secret); err != nil {
+		return fmt.Errorf("connection secret of Schema Registry %q not found in namespace %s: %w", registry, namespace, err)
+	}
+
+	podlog.V(1).Info("Injecting Schema Registry connection settings", "registry", registry, "namespace", namespace, "pod", pod.GenerateName+pod.Name)
+	injectConnection(pod, name, secret)
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[injectedAnnotation] = registry
+	return nil
+}
+
+// injectConnection adds the connection env vars, and the connection Secret volume when it
+// carries a CA bundle, to every container. Env vars and mounts already defined by the pod
+// are kept, so the injection is idempotent and can be overridden.
+func injectConnection(pod *corev1.Pod, name string, secret *corev1.Secret) {
+	env := []corev1.EnvVar{
+		{Name: "SCHEMA_REGISTRY_URL", ValueFrom: &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  "url",
+			},
+		}},
+	}
+	var mount *corev1.VolumeMount
+	if _, ok := secret.Data["ca.crt"]; ok {
+		mount = &corev1.VolumeMount{Name: connectionVolumeName, MountPath: connectionMountPath, ReadOnly: true}
+		env = append(env,
+			corev1.EnvVar{Name: "SCHEMA_REGISTRY_CA_LOCATION", Value: connectionMountPath + "/ca.crt"},
+			corev1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_TRUSTSTORE_LOCATION", Value: connectionMountPath + "/truststore.p12"},
+			corev1.EnvVar{Name: "SCHEMA_REGISTRY_SSL_TRUSTSTORE_TYPE", Value: "PKCS12"},
+			secretEnv("SCHEMA_REGISTRY_SSL_TRUSTSTORE_PASSWORD", name, "truststore.password"),
+		)
+		if !slices.ContainsFunc(pod.Spec.Volumes, func(v corev1.Volume) bool { return v.Name == connectionVolumeName }) {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name:         connectionVolumeName,
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}},
+			})
+		}
+	}
+	if _, ok := secret.Data["basic.auth.user.info"]; ok {
+		env = append(env,
+			corev1.EnvVar{Name: "SCHEMA_REGISTRY_BASIC_AUTH_CREDENTIALS_SOURCE", Value: "USER_INFO"},
+			secretEnv("SCHEMA_REGISTRY_BASIC_AUTH_USER_INFO", name, "basic.auth.user.info"),
+		)
+	}
+
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			injectContainer(&containers[i], env, mount)
+		}
+	}
+}
+
+// injectContainer adds the env vars and the mount the container does not define yet.
+func injectContainer(container *corev1.Container, env []corev1.EnvVar, mount *corev1.VolumeMount) {
+	for _, e := range env {
+		if !slices.ContainsFunc(container.Env, func(existing corev1.EnvVar) bool { return existing.Name == e.Name }) {
+			container.Env = append(container.Env, e)
+		}
+	}
+	if mount != nil && !slices.ContainsFunc(container.VolumeMounts, func(m corev1.VolumeMount) bool {
+		return m.Name == mount.Name || m.MountPath == mount.MountPath
+	}) {
+		container.VolumeMounts = append(container.VolumeMounts, *mount)
+	}
+}
+
+// secretEnv returns an env var read from a key of the connection Secret.
+func secretEnv(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+		SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+			Key:                  key,
+		},
+	}}
+}
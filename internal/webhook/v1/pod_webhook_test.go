@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newInjectedPod(registry string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "app-",
+			Annotations:  map[string]string{InjectAnnotation: registry},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "migrate"}},
+			Containers: []corev1.Container{{
+				Name: "app",
+				Env:  []corev1.EnvVar{{Name: "SCHEMA_REGISTRY_URL", Value: "http://override:8081"}},
+			}},
+		},
+	}
+}
+
+func envValue(container corev1.Container, name string) (corev1.EnvVar, bool) {
+	for _, e := range container.Env {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return corev1.EnvVar{}, false
+}
+
+func TestPodCustomDefaulter(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-connection", Namespace: "apps"},
+		Data:       map[string]string{"url": "https://registry.kafka.svc:443"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-connection", Namespace: "apps"},
+		Data: map[string][]byte{
+			"ca.crt": []byte("CA"), "truststore.p12": []byte("P12"), "truststore.password": []byte("pw"),
+			"basic.auth.user.info": []byte("ci:pw"),
+		},
+	}
+	defaulter := &PodCustomDefaulter{Client: fake.NewClientBuilder().WithObjects(configMap, secret).Build()}
+	// Pods created by controllers only get their namespace from the admission request.
+	ctx := admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{Namespace: "apps"},
+	})
+
+	t.Run("pods without the annotation are left alone", func(t *testing.T) {
+		pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+		if err := defaulter.Default(ctx, pod); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pod.Spec.Containers[0].Env) != 0 || len(pod.Spec.Volumes) != 0 {
+			t.Errorf("unexpected injection: %+v", pod.Spec)
+		}
+	})
+
+	t.Run("annotated pods get the connection settings", func(t *testing.T) {
+		pod := newInjectedPod("registry")
+		if err := defaulter.Default(ctx, pod); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			for _, name := range []string{"SCHEMA_REGISTRY_URL", "SCHEMA_REGISTRY_SSL_TRUSTSTORE_LOCATION",
+				"SCHEMA_REGISTRY_SSL_TRUSTSTORE_PASSWORD", "SCHEMA_REGISTRY_BASIC_AUTH_USER_INFO"} {
+				if _, ok := envValue(container, name); !ok {
+					t.Errorf("container %s: expected %s", container.Name, name)
+				}
+			}
+			if len(container.VolumeMounts) != 1 || container.VolumeMounts[0].MountPath != connectionMountPath {
+				t.Errorf("container %s: expected connection mount, got %+v", container.Name, container.VolumeMounts)
+			}
+		}
+		if url, _ := envValue(pod.Spec.Containers[0], "SCHEMA_REGISTRY_URL"); url.Value != "http://override:8081" {
+			t.Errorf("env vars set by the pod must win, got %+v", url)
+		}
+		if url, _ := envValue(pod.Spec.InitContainers[0], "SCHEMA_REGISTRY_URL"); url.ValueFrom.ConfigMapKeyRef.Name != "registry-connection" {
+			t.Errorf("expected URL from the connection ConfigMap, got %+v", url)
+		}
+		if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].Secret.SecretName != "registry-connection" {
+			t.Errorf("expected connection volume, got %+v", pod.Spec.Volumes)
+		}
+		if pod.Annotations[injectedAnnotation] != "registry" {
+			t.Error("expected injected annotation")
+		}
+
+		// A second admission (e.g. reinvocation) must not duplicate anything.
+		if err := defaulter.Default(ctx, pod); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pod.Spec.Volumes) != 1 || len(pod.Spec.Containers[0].VolumeMounts) != 1 {
+			t.Errorf("injection is not idempotent: %+v", pod.Spec)
+		}
+	})
+
+	t.Run("plain HTTP registries only get the URL", func(t *testing.T) {
+		plain := secret.DeepCopy()
+		plain.Data = nil
+		d := &PodCustomDefaulter{Client: fake.NewClientBuilder().WithObjects(configMap, plain).Build()}
+		pod := newInjectedPod("registry")
+		if err := d.Default(ctx, pod); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(pod.Spec.Volumes) != 0 || len(pod.Spec.InitContainers[0].Env) != 1 {
+			t.Errorf("expected only SCHEMA_REGISTRY_URL, got %+v", pod.Spec)
+		}
+	})
+
+	t.Run("unknown registry is rejected", func(t *testing.T) {
+		if err := defaulter.Default(ctx, newInjectedPod("missing")); err == nil {
+			t.Error("expected an error for a registry without connection settings")
+		}
+	})
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/controller"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var strimzischemalog = logf.Log.WithName("strimzischema-resource")
+
+// SetupStrimziSchemaWebhookWithManager registers the webhook checking StrimziSchemas for
+// compatibility with the registered versions of their subject.
+func SetupStrimziSchemaWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &strimziregistryoperatorv1alpha1.StrimziSchema{}).
+		WithValidator(&StrimziSchemaCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-strimziregistryoperator-randsw-code-v1alpha1-strimzischema,mutating=false,failurePolicy=ignore,sideEffects=None,groups=strimziregistryoperator.randsw.code,resources=strimzischemas,verbs=create;update,versions=v1alpha1,name=vstrimzischema-v1alpha1.strimziregistryoperator.randsw.code,admissionReviewVersions=v1
+
+// StrimziSchemaCustomValidator rejects StrimziSchemas the registry would reject as incompatible
+// with the versions of their subject, so that `kubectl apply --dry-run=server` reports them.
+// Checks that could not reach the registry, or could not run at all, are returned as warnings.
+type StrimziSchemaCustomValidator struct {
+	Client client.Reader
+	// NewRegistryClient builds the REST API client. Defaults to registryclient.New.
+	NewRegistryClient controller.RegistryClientFactory
+}
+
+// ValidateCreate implements admission.Validator.
+func (v *StrimziSchemaCustomValidator) ValidateCreate(ctx context.Context,
+	schema *strimziregistryoperatorv1alpha1.StrimziSchema) (admission.Warnings, error) {
+	return v.validate(ctx, schema, nil)
+}
+
+// ValidateUpdate implements admission.Validator. Updates leaving the spec unchanged are not checked.
+func (v *StrimziSchemaCustomValidator) ValidateUpdate(ctx context.Context,
+	oldSchema, schema *strimziregistryoperatorv1alpha1.StrimziSchema) (admission.Warnings, error) {
+	if equality.Semantic.DeepEqual(oldSchema.Spec, schema.Spec) {
+		return nil, nil
+	}
+	return v.validate(ctx, schema, oldSchema)
+}
+
+// ValidateDelete implements admission.Validator.
+func (v *StrimziSchemaCustomValidator) ValidateDelete(context.Context, *strimziregistryoperatorv1alpha1.StrimziSchema) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *StrimziSchemaCustomValidator) validate(ctx context.Context,
+	schema, oldSchema *strimziregistryoperatorv1alpha1.StrimziSchema) (admission.Warnings, error) {
+	result := controller.CheckSchemaCompatibility(ctx, v.Client, v.NewRegistryClient, schema, oldSchema)
+	var warnings admission.Warnings
+	if result.Warning != "" {
+		warnings = append(warnings, result.Warning)
+	}
+	if result.Compatible {
+		return warnings, nil
+	}
+
+	strimzischemalog.V(1).Info("Rejecting incompatible schema", "namespace", schema.Namespace, "name", schema.Name,
+		"subject", schema.Spec.Subject)
+	path := field.NewPath("spec", "schema")
+	if schema.Spec.SchemaFrom != nil {
+		path = field.NewPath("spec", "schemaFrom")
+	}
+	errs := make(field.ErrorList, 0, len(result.Messages))
+	for _, message := range result.Messages {
+		errs = append(errs, field.Forbidden(path, message))
+	}
+	return warnings, apierrors.NewInvalid(strimziregistryoperatorv1alpha1.GroupVersion.WithKind("StrimziSchema").GroupKind(),
+		schema.Name, errs)
+}
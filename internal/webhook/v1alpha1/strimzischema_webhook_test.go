@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	strimziregistryoperatorv1alpha1 "github.com/randsw/schema-registry-operator-strimzi/api/v1alpha1"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/registryclient"
+	"github.com/randsw/schema-registry-operator-strimzi/internal/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	orderV1 = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+	orderV2 = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"total","type":"double"}]}`
+)
+
+func newSchema(schema string) *strimziregistryoperatorv1alpha1.StrimziSchema {
+	return &strimziregistryoperatorv1alpha1.StrimziSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "default"},
+		Spec: strimziregistryoperatorv1alpha1.StrimziSchemaSpec{
+			Registry:   strimziregistryoperatorv1alpha1.RegistryReference{Name: "test-sr"},
+			Subject:    "orders-value",
+			SchemaType: strimziregistryoperatorv1alpha1.SchemaTypeAvro,
+			Schema:     schema,
+		},
+	}
+}
+
+func newValidator(t *testing.T, registry *testutil.FakeRegistry, ready bool) *StrimziSchemaCustomValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = strimziregistryoperatorv1alpha1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	sr := &strimziregistryoperatorv1alpha1.StrimziSchemaRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-sr", Namespace: "default"},
+		Spec:       strimziregistryoperatorv1alpha1.StrimziSchemaRegistrySpec{CompatibilityLevel: "backward"},
+	}
+	if ready {
+		sr.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue, Reason: "Ready"}}
+	}
+	return &StrimziSchemaCustomValidator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(sr).Build(),
+		NewRegistryClient: func(cfg registryclient.Config) (*registryclient.Client, error) {
+			cfg.URL = registry.URL
+			return registryclient.New(cfg)
+		},
+	}
+}
+
+func TestStrimziSchemaValidator(t *testing.T) {
+	ctx := context.Background()
+	registry := testutil.NewFakeRegistry()
+	defer registry.Close()
+	registry.Incompatible = func(_, schema string) bool { return schema == orderV2 }
+	rc, _ := registryclient.New(registryclient.Config{URL: registry.URL})
+	if _, err := rc.RegisterSchema(ctx, "orders-value", registryclient.Schema{Schema: orderV1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("incompatible schema is rejected", func(t *testing.T) {
+		_, err := newValidator(t, registry, true).ValidateCreate(ctx, newSchema(orderV2))
+		if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "spec.schema") {
+			t.Errorf("expected an invalid spec.schema error, got %v", err)
+		}
+	})
+
+	t.Run("compatible schema is admitted", func(t *testing.T) {
+		warnings, err := newValidator(t, registry, true).ValidateCreate(ctx, newSchema(orderV1))
+		if err != nil || len(warnings) != 0 {
+			t.Errorf("expected the schema to be admitted, got %v (warnings: %v)", err, warnings)
+		}
+	})
+
+	t.Run("unchanged spec is not checked", func(t *testing.T) {
+		old, updated := newSchema(orderV2), newSchema(orderV2)
+		updated.Labels = map[string]string{"team": "orders"}
+		if _, err := newValidator(t, registry, true).ValidateUpdate(ctx, old, updated); err != nil {
+			t.Errorf("expected a metadata update to be admitted, got %v", err)
+		}
+	})
+
+	t.Run("offline check explains the broken field", func(t *testing.T) {
+		old := newSchema(orderV1)
+		old.Status.Version = 1
+		warnings, err := newValidator(t, registry, false).ValidateUpdate(ctx, old, newSchema(orderV2))
+		if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), `field "total"`) {
+			t.Errorf("expected an offline rejection naming the field, got %v", err)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "checked offline") {
+			t.Errorf("expected an offline warning, got %v", warnings)
+		}
+	})
+}